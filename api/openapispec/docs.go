@@ -1,17 +1,10 @@
-// Package openapispec GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
-// This file was generated by swaggo/swag
-package openapispec
+// Code generated by swaggo/swag. DO NOT EDIT.
 
-import (
-	"bytes"
-	"encoding/json"
-	"strings"
-	"text/template"
+package openapispec
 
-	"github.com/swaggo/swag"
-)
+import "github.com/swaggo/swag"
 
-var doc = `{
+const docTemplate = `{
     "schemes": {{ marshal .Schemes }},
     "swagger": "2.0",
     "info": {
@@ -23,24 +16,24 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/systemconfig": {
-            "put": {
-                "description": "Update the specified system config",
+        "/api/v1/environment": {
+            "post": {
+                "description": "Create a new environment",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update system config",
+                "summary": "Create environment",
                 "parameters": [
                     {
-                        "description": "Updated system config",
-                        "name": "config",
+                        "description": "Created environment",
+                        "name": "environment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/systemconfig.UpdateSystemConfigRequest"
+                            "$ref": "#/definitions/pkg_handler_api_v1_environment.CreateEnvironmentRequest"
                         }
                     }
                 ],
@@ -48,48 +41,145 @@ var doc = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/entity.SystemConfig"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Environment"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "429": {
                         "description": "Too Many Requests",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/environment/{id}": {
+            "get": {
+                "description": "Get environment information by environment ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get environment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Environment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Environment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     }
                 }
             },
-            "post": {
-                "description": "Create a new system config instance",
+            "put": {
+                "description": "Update the specified environment, the name of an environment can't be changed",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Create system config",
+                "summary": "Update environment",
                 "parameters": [
                     {
-                        "description": "Created system config",
-                        "name": "config",
+                        "type": "integer",
+                        "description": "Environment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated environment",
+                        "name": "environment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/systemconfig.CreateSystemConfigRequest"
+                            "$ref": "#/definitions/pkg_handler_api_v1_environment.UpdateEnvironmentRequest"
                         }
                     }
                 ],
@@ -97,90 +187,234 @@ var doc = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/entity.SystemConfig"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Environment"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "429": {
                         "description": "Too Many Requests",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete specified environment by ID, protected environments and environments in use can't be deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete environment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Environment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/systemconfig/count": {
+        "/api/v1/environments": {
             "get": {
-                "description": "Count the total number of system configs",
+                "description": "List all environments ordered by their order in the promotion path",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Count system configs",
+                "summary": "List environments",
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/entity.SystemConfig"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Environment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "429": {
                         "description": "Too Many Requests",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/systemconfig/{id}": {
+        "/api/v1/errcodes": {
             "get": {
-                "description": "Get system config information by system config ID",
+                "description": "List the registered error codes with their HTTP statuses, messages by locale and whether they're retryable",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get system config",
+                "summary": "List error codes",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_errcode.Meta"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/freezewindow": {
+            "put": {
+                "description": "Update the scope and the period of the specified freeze window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update freeze window",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "SystemConfig ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Updated freeze window",
+                        "name": "window",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_freezewindow.UpdateFreezeWindowRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/entity.SystemConfig"
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeWindow"
                         }
                     },
                     "400": {
@@ -205,26 +439,31 @@ var doc = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete specified system config by ID",
+            "post": {
+                "description": "Create a new freeze window blocking writes to the system configs in its scope",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete system config",
+                "summary": "Create freeze window",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "SystemConfig ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Created freeze window",
+                        "name": "window",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_freezewindow.CreateFreezeWindowRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/entity.SystemConfig"
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeWindow"
                         }
                     },
                     "400": {
@@ -250,32 +489,41 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/systemconfigs": {
+        "/api/v1/freezewindow/status": {
             "get": {
-                "description": "Find system configs with query",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Report whether the system configs of the scope are frozen now",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Find system configs",
+                "summary": "Get freeze status",
                 "parameters": [
                     {
-                        "description": "query body",
-                        "name": "query",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/systemconfig.QuerySystemConfigRequest"
-                        }
+                        "type": "string",
+                        "description": "Tenant of the system configs",
+                        "name": "tenant",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment of the system configs",
+                        "name": "env",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the system configs",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/entity.SystemConfig"
+                            "$ref": "#/definitions/pkg_handler_api_v1_freezewindow.FreezeStatusResponse"
                         }
                     },
                     "400": {
@@ -300,59 +548,1893 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.SystemConfig": {
-            "type": "object",
-            "properties": {
-                "config": {
-                    "description": "Configuration data in JSON or YAML format",
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "Timestamp when the system was created",
-                    "type": "string"
-                },
-                "creator": {
-                    "description": "Username or ID of the user who created the system",
-                    "type": "string"
-                },
-                "description": {
-                    "description": "Description or purpose of the system",
-                    "type": "string"
-                },
-                "env": {
-                    "description": "Environment where the system is deployed (e.g. prod, gray)",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "Unique ID of the system",
-                    "type": "integer"
-                },
+        },
+        "/api/v1/freezewindow/{id}": {
+            "get": {
+                "description": "Get freeze window information by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get freeze window",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Freeze window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeWindow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete specified freeze window by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete freeze window",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Freeze window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/freezewindow/{id}/overrides": {
+            "get": {
+                "description": "Find the recorded writes which bypassed the freeze window, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find freeze overrides",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Freeze window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "query body",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_freezewindow.QueryFreezeWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeOverride"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/freezewindows": {
+            "get": {
+                "description": "Find freeze windows with query, latest start first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find freeze windows",
+                "parameters": [
+                    {
+                        "description": "query body",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_freezewindow.QueryFreezeWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeWindow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/schedule/{id}": {
+            "get": {
+                "description": "Get scheduled change information by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get scheduled change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a scheduled change which is waiting to be applied, an applied change is reverted at the next scan of the scheduler",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Cancel scheduled change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/schedules": {
+            "get": {
+                "description": "List the scheduled changes waiting to be applied or reverted, ordered by effective time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List scheduled changes",
+                "parameters": [
+                    {
+                        "description": "query body",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_schedule.QueryScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.ScheduledChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfig": {
+            "put": {
+                "description": "Update the specified system config, the update is scheduled if effectiveAt or expiresAt is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update system config",
+                "parameters": [
+                    {
+                        "description": "Updated system config",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_systemconfig.UpdateSystemConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success, or entity.ScheduledChange if the update is scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new system config instance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create system config",
+                "parameters": [
+                    {
+                        "description": "Created system config",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_systemconfig.CreateSystemConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfig/by-key/{tenant}/{env}/{type}": {
+            "get": {
+                "description": "Get system config information by its tenant, env and type",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get system config by key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant of the system config",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Env of the system config",
+                        "name": "env",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the system config",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Create or update the system config identified by its tenant, env and type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Upsert system config by key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant of the system config",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Env of the system config",
+                        "name": "env",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the system config",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Upserted system config",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_systemconfig.UpsertSystemConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfig/count": {
+            "get": {
+                "description": "Count the total number of system configs",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Count system configs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Keyword to search for",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label selector, e.g. team=payments,region!=us",
+                        "name": "labelSelector",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfig/{id}": {
+            "get": {
+                "description": "Get system config information by system config ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get system config",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SystemConfig ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete specified system config by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete system config",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SystemConfig ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason to delete the system config during a freeze window",
+                        "name": "freezeOverrideReason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete the system config even if others reference it",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfig/{id}/dependents": {
+            "get": {
+                "description": "Find the system configs which reference the specified system config",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find system config dependents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SystemConfig ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfig/{id}/render": {
+            "get": {
+                "description": "Get system config by ID with the references in its content resolved",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Render system config",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SystemConfig ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/systemconfigs": {
+            "get": {
+                "description": "Find system configs with query",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find system configs",
+                "parameters": [
+                    {
+                        "description": "query body",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_systemconfig.QuerySystemConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/tenant": {
+            "put": {
+                "description": "Update the specified tenant, the name of a tenant can't be changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update tenant",
+                "parameters": [
+                    {
+                        "description": "Updated tenant",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_tenant.UpdateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new tenant with owners and quotas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create tenant",
+                "parameters": [
+                    {
+                        "description": "Created tenant",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_tenant.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/tenant/count": {
+            "get": {
+                "description": "Count the total number of tenants",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Count tenants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Keyword to search for",
+                        "name": "keyword",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_tenant.CountTenantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/tenant/{id}": {
+            "get": {
+                "description": "Get tenant information by tenant ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get tenant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete specified tenant by ID, tenants which own system configs can't be deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete tenant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants": {
+            "get": {
+                "description": "Find tenants with query",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find tenants",
+                "parameters": [
+                    {
+                        "description": "query body",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_handler_api_v1_tenant.QueryTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Tenant"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.Env": {
+            "type": "string",
+            "enum": [
+                "pre",
+                "gray",
+                "prod",
+                "dev",
+                "test",
+                "stable"
+            ],
+            "x-enum-varnames": [
+                "EnvPre",
+                "EnvGray",
+                "EnvProd",
+                "EnvDev",
+                "EnvTest",
+                "EnvStable"
+            ]
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.Environment": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Timestamp when the environment was created",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "Username or ID of the user who created the environment",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description or purpose of the environment",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the environment",
+                    "type": "integer"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the environment",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the environment (e.g. prod, gray), which is immutable",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Env"
+                        }
+                    ]
+                },
+                "order": {
+                    "description": "Order of the environment in the promotion path, environments with a\nlower order are promoted to environments with a higher order",
+                    "type": "integer"
+                },
+                "protected": {
+                    "description": "Protected environments can't be deleted",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "description": "Timestamp when the environment was last updated",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeOverride": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Write action on the system config (create, update or delete)",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Timestamp when the override was recorded",
+                    "type": "string"
+                },
+                "freezeWindowID": {
+                    "description": "ID of the bypassed freeze window",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Unique ID of the override",
+                    "type": "integer"
+                },
+                "operator": {
+                    "description": "Username or ID of the admin who bypassed the freeze",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Why the freeze had to be bypassed",
+                    "type": "string"
+                },
+                "systemConfigID": {
+                    "description": "ID of the written system config",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeWindow": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Timestamp when the freeze window was created",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "Username or ID of the user who created the freeze window",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description or purpose of the freeze window",
+                    "type": "string"
+                },
+                "endAt": {
+                    "description": "Time when the freeze ends",
+                    "type": "string"
+                },
+                "env": {
+                    "description": "Environment of the frozen system configs, empty means all environments",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Env"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Unique ID of the freeze window",
+                    "type": "integer"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the freeze window",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the freeze window (e.g. 2023 spring festival)",
+                    "type": "string"
+                },
+                "startAt": {
+                    "description": "Time when the freeze starts",
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant of the frozen system configs, empty means all tenants",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the frozen system configs, empty means all types",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Timestamp when the freeze window was last updated",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.ScheduleStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "applied",
+                "reverted",
+                "cancelled",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ScheduleStatusPending",
+                "ScheduleStatusApplied",
+                "ScheduleStatusReverted",
+                "ScheduleStatusCancelled",
+                "ScheduleStatusFailed"
+            ]
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.ScheduledChange": {
+            "type": "object",
+            "properties": {
+                "appliedAt": {
+                    "description": "Timestamp when the change was applied",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Timestamp when the change was submitted",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "Username or ID of the user who submitted the change",
+                    "type": "string"
+                },
+                "effectiveAt": {
+                    "description": "Time when the change goes live",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "Time when the change is reverted, nil means the change is permanent",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the scheduled change",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Reason of the last failure",
+                    "type": "string"
+                },
+                "previous": {
+                    "description": "Snapshot of the system config taken when the change was applied,\nwhich is restored when the change expires",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    ]
+                },
+                "revertedAt": {
+                    "description": "Timestamp when the change was reverted",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status of the scheduled change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.ScheduleStatus"
+                        }
+                    ]
+                },
+                "systemConfigID": {
+                    "description": "ID of the system config to change",
+                    "type": "integer"
+                },
+                "target": {
+                    "description": "Values applied to the system config, empty fields are left unchanged",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "description": "Timestamp when the change was last updated",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.SystemConfig": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "description": "Configuration data in JSON or YAML format",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Timestamp when the system was created",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "Username or ID of the user who created the system",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description or purpose of the system",
+                    "type": "string"
+                },
+                "env": {
+                    "description": "Environment where the system is deployed (e.g. prod, gray)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.Env"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Unique ID of the system",
+                    "type": "integer"
+                },
+                "labels": {
+                    "description": "Arbitrary key/value labels used to organize and select the system\nconfig (e.g. team=payments, region=eu)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the system",
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant or organization that the system belongs to",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type or category of the system (e.g. cache, message queue)",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Timestamp when the system was last updated",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.Tenant": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Timestamp when the tenant was created",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "Username or ID of the user who created the tenant",
+                    "type": "string"
+                },
+                "displayName": {
+                    "description": "Human readable name of the tenant",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the tenant",
+                    "type": "integer"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the tenant",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the tenant referenced by system configs, which is immutable",
+                    "type": "string"
+                },
+                "owners": {
+                    "description": "Usernames or IDs of the users who own the tenant",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quota": {
+                    "description": "Resource limits of the tenant",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.TenantQuota"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status of the tenant, suspended tenants are read-only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.TenantStatus"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "description": "Timestamp when the tenant was last updated",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.TenantQuota": {
+            "type": "object",
+            "properties": {
+                "maxConfigSize": {
+                    "description": "Maximum size in bytes of the content of a single system config",
+                    "type": "integer"
+                },
+                "maxConfigs": {
+                    "description": "Maximum number of system configs owned by the tenant",
+                    "type": "integer"
+                },
+                "maxRevisions": {
+                    "description": "Maximum number of revisions retained for a single system config",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_domain_entity.TenantStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "suspended"
+            ],
+            "x-enum-varnames": [
+                "TenantStatusActive",
+                "TenantStatusSuspended"
+            ]
+        },
+        "github_com_elliotxx_go-web-template_pkg_errcode.Meta": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the error code, e.g. A0400",
+                    "type": "string"
+                },
+                "messages": {
+                    "description": "Messages maps the locales to the messages of the code",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "retryAfter": {
+                    "description": "RetryAfter is the number of seconds to wait before sending the failed\nrequest again, answered in the Retry-After header",
+                    "type": "integer"
+                },
+                "retryable": {
+                    "description": "Retryable is true if the failed request may succeed when sent again",
+                    "type": "boolean"
+                },
+                "scope": {
+                    "description": "Scope is the first three characters of the code, e.g. A04",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the HTTP status code of the responses failing with the code",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_handler.Duration": {
+            "type": "integer",
+            "enum": [
+                -9223372036854775808,
+                9223372036854775807,
+                1,
+                1000,
+                1000000,
+                1000000000,
+                60000000000,
+                3600000000000,
+                -9223372036854775808,
+                9223372036854775807,
+                1,
+                1000,
+                1000000,
+                1000000000,
+                60000000000,
+                3600000000000,
+                1,
+                1000,
+                1000000,
+                1000000000,
+                60000000000,
+                3600000000000
+            ],
+            "x-enum-varnames": [
+                "minDuration",
+                "maxDuration",
+                "Nanosecond",
+                "Microsecond",
+                "Millisecond",
+                "Second",
+                "Minute",
+                "Hour",
+                "minDuration",
+                "maxDuration",
+                "Nanosecond",
+                "Microsecond",
+                "Millisecond",
+                "Second",
+                "Minute",
+                "Hour",
+                "Nanosecond",
+                "Microsecond",
+                "Millisecond",
+                "Second",
+                "Minute",
+                "Hour"
+            ]
+        },
+        "github_com_elliotxx_go-web-template_pkg_handler.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the name of the field in the request, e.g. page or\npagination.perPage, by the tag of its source",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message describes the failed rule",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "Rule is the failed rule, e.g. required or gte",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_elliotxx_go-web-template_pkg_handler.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "costTime": {
+                    "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.Duration"
+                },
+                "data": {},
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_handler.FieldError"
+                    }
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "traceID": {
+                    "type": "string"
+                }
+            }
+        },
+        "pkg_handler_api_v1_environment.CreateEnvironmentRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "creator": {
+                    "description": "Username or ID of the user who created the environment, required unless\nthe request has an authenticated principal, which overrides it",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description or purpose of the environment",
+                    "type": "string"
+                },
                 "modifier": {
-                    "description": "Username or ID of the user who last modified the system",
+                    "description": "Username or ID of the user who last modified the environment, overridden by the\nauthenticated principal of the request",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the environment (e.g. prod, gray)",
+                    "type": "string"
+                },
+                "order": {
+                    "description": "Order of the environment in the promotion path",
+                    "type": "integer"
+                },
+                "protected": {
+                    "description": "Protected environments can't be deleted",
+                    "type": "boolean"
+                }
+            }
+        },
+        "pkg_handler_api_v1_environment.UpdateEnvironmentRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description or purpose of the environment",
+                    "type": "string"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the environment, overridden by the\nauthenticated principal of the request",
+                    "type": "string"
+                },
+                "order": {
+                    "description": "Order of the environment in the promotion path",
+                    "type": "integer"
+                },
+                "protected": {
+                    "description": "Protected environments can't be deleted",
+                    "type": "boolean"
+                }
+            }
+        },
+        "pkg_handler_api_v1_freezewindow.CreateFreezeWindowRequest": {
+            "type": "object",
+            "required": [
+                "endAt",
+                "name",
+                "startAt"
+            ],
+            "properties": {
+                "creator": {
+                    "description": "Username or ID of the user who created the freeze window, required unless\nthe request has an authenticated principal, which overrides it",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description or purpose of the freeze window",
+                    "type": "string"
+                },
+                "endAt": {
+                    "description": "Time when the freeze ends",
+                    "type": "string"
+                },
+                "env": {
+                    "description": "Environment of the frozen system configs (e.g. prod)",
+                    "type": "string"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the freeze window, overridden by the\nauthenticated principal of the request",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the freeze window",
+                    "type": "string"
+                },
+                "startAt": {
+                    "description": "Time when the freeze starts",
                     "type": "string"
                 },
                 "tenant": {
-                    "description": "Tenant or organization that the system belongs to",
+                    "description": "Tenant of the frozen system configs",
                     "type": "string"
                 },
                 "type": {
-                    "description": "Type or category of the system (e.g. cache, message queue)",
+                    "description": "Type of the frozen system configs (e.g. cache)",
                     "type": "string"
+                }
+            }
+        },
+        "pkg_handler_api_v1_freezewindow.FreezeStatusResponse": {
+            "type": "object",
+            "properties": {
+                "frozen": {
+                    "description": "Frozen is true if the system configs of the scope can't be written",
+                    "type": "boolean"
                 },
-                "updatedAt": {
-                    "description": "Timestamp when the system was last updated",
+                "windows": {
+                    "description": "Windows are the freeze windows covering the scope now",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_elliotxx_go-web-template_pkg_domain_entity.FreezeWindow"
+                    }
+                }
+            }
+        },
+        "pkg_handler_api_v1_freezewindow.QueryFreezeWindowRequest": {
+            "type": "object",
+            "required": [
+                "page",
+                "perPage"
+            ],
+            "properties": {
+                "keyword": {
+                    "description": "Keyword is the keyword to search for.\nOptional: true",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page is the page number, starting from 1.\nRequired: true, Minimum value: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "perPage": {
+                    "description": "PerPage is the number of items per page.\nRequired: true, Minimum value: 1, Maximum value: 300",
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 1
+                }
+            }
+        },
+        "pkg_handler_api_v1_freezewindow.UpdateFreezeWindowRequest": {
+            "type": "object",
+            "required": [
+                "endAt",
+                "id",
+                "name",
+                "startAt"
+            ],
+            "properties": {
+                "description": {
+                    "description": "Description or purpose of the freeze window",
+                    "type": "string"
+                },
+                "endAt": {
+                    "description": "Time when the freeze ends",
+                    "type": "string"
+                },
+                "env": {
+                    "description": "Environment of the frozen system configs (e.g. prod)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the freeze window",
+                    "type": "integer"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the freeze window, overridden by the\nauthenticated principal of the request",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the freeze window",
+                    "type": "string"
+                },
+                "startAt": {
+                    "description": "Time when the freeze starts",
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant of the frozen system configs",
                     "type": "string"
+                },
+                "type": {
+                    "description": "Type of the frozen system configs (e.g. cache)",
+                    "type": "string"
+                }
+            }
+        },
+        "pkg_handler_api_v1_schedule.QueryScheduleRequest": {
+            "type": "object",
+            "required": [
+                "page",
+                "perPage"
+            ],
+            "properties": {
+                "page": {
+                    "description": "Page is the page number, starting from 1.\nRequired: true, Minimum value: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "perPage": {
+                    "description": "PerPage is the number of items per page.\nRequired: true, Minimum value: 1, Maximum value: 300",
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 1
                 }
             }
         },
-        "systemconfig.CreateSystemConfigRequest": {
+        "pkg_handler_api_v1_systemconfig.CreateSystemConfigRequest": {
             "type": "object",
             "required": [
                 "config",
-                "creator",
                 "env",
                 "tenant",
                 "type"
@@ -363,7 +2445,7 @@ var doc = `{
                     "type": "string"
                 },
                 "creator": {
-                    "description": "Username or ID of the user who created the system",
+                    "description": "Username or ID of the user who created the system, required unless\nthe request has an authenticated principal, which overrides it",
                     "type": "string"
                 },
                 "description": {
@@ -374,8 +2456,19 @@ var doc = `{
                     "description": "Environment where the system is deployed (e.g. prod, gray)",
                     "type": "string"
                 },
+                "freezeOverrideReason": {
+                    "description": "Reason to write the system config during a freeze window, only the\nowners of the tenant authenticated by the X-Principal header can\noverride a freeze\nOptional: true",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Arbitrary key/value labels of the system config",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "modifier": {
-                    "description": "Username or ID of the user who last modified the system",
+                    "description": "Username or ID of the user who last modified the system, overridden by the\nauthenticated principal of the request",
                     "type": "string"
                 },
                 "tenant": {
@@ -388,7 +2481,7 @@ var doc = `{
                 }
             }
         },
-        "systemconfig.QuerySystemConfigRequest": {
+        "pkg_handler_api_v1_systemconfig.QuerySystemConfigRequest": {
             "type": "object",
             "required": [
                 "page",
@@ -399,6 +2492,10 @@ var doc = `{
                     "description": "Keyword is the keyword to search for.\nOptional: true",
                     "type": "string"
                 },
+                "labelSelector": {
+                    "description": "LabelSelector is a Kubernetes-style label selector, for example\n\"team=payments,region!=us,tier in (a,b)\".\nOptional: true",
+                    "type": "string"
+                },
                 "page": {
                     "description": "Page is the page number, starting from 1.\nRequired: true, Minimum value: 1",
                     "type": "integer",
@@ -412,7 +2509,7 @@ var doc = `{
                 }
             }
         },
-        "systemconfig.UpdateSystemConfigRequest": {
+        "pkg_handler_api_v1_systemconfig.UpdateSystemConfigRequest": {
             "type": "object",
             "required": [
                 "id"
@@ -423,23 +2520,42 @@ var doc = `{
                     "type": "string"
                 },
                 "creator": {
-                    "description": "Username or ID of the user who created the system",
+                    "description": "Username or ID of the user who created the system, ignored if the\nrequest has an authenticated principal",
                     "type": "string"
                 },
                 "description": {
                     "description": "Description or purpose of the system",
                     "type": "string"
                 },
+                "effectiveAt": {
+                    "description": "Time when the update goes live, the update is scheduled instead of\nbeing applied immediately if either effectiveAt or expiresAt is set\nOptional: true",
+                    "type": "string"
+                },
                 "env": {
                     "description": "Environment where the system is deployed (e.g. prod, gray)",
                     "type": "string"
                 },
+                "expiresAt": {
+                    "description": "Time when the update is reverted\nOptional: true",
+                    "type": "string"
+                },
+                "freezeOverrideReason": {
+                    "description": "Reason to write the system config during a freeze window, only the\nowners of the tenant authenticated by the X-Principal header can\noverride a freeze\nOptional: true",
+                    "type": "string"
+                },
                 "id": {
                     "description": "Unique ID of the system",
                     "type": "integer"
                 },
+                "labels": {
+                    "description": "Arbitrary key/value labels of the system config, replaces the\nexisting labels if present",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "modifier": {
-                    "description": "Username or ID of the user who last modified the system",
+                    "description": "Username or ID of the user who last modified the system, overridden by the\nauthenticated principal of the request",
                     "type": "string"
                 },
                 "tenant": {
@@ -451,60 +2567,198 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "pkg_handler_api_v1_systemconfig.UpsertSystemConfigRequest": {
+            "type": "object",
+            "required": [
+                "config"
+            ],
+            "properties": {
+                "config": {
+                    "description": "Configuration data in JSON or YAML format",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description or purpose of the system",
+                    "type": "string"
+                },
+                "freezeOverrideReason": {
+                    "description": "Reason to write the system config during a freeze window, only the\nowners of the tenant authenticated by the X-Principal header can\noverride a freeze\nOptional: true",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Arbitrary key/value labels of the system config, replaces the\nexisting labels if present",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who writes the system, it's also the\ncreator if the system config is created. It's required unless the\nrequest has an authenticated principal, which overrides it",
+                    "type": "string"
+                }
+            }
+        },
+        "pkg_handler_api_v1_tenant.CountTenantResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pkg_handler_api_v1_tenant.CreateTenantRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "owners"
+            ],
+            "properties": {
+                "creator": {
+                    "description": "Username or ID of the user who created the tenant, required unless\nthe request has an authenticated principal, which overrides it",
+                    "type": "string"
+                },
+                "displayName": {
+                    "description": "Human readable name of the tenant",
+                    "type": "string"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the tenant, overridden by the\nauthenticated principal of the request",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the tenant referenced by system configs",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "owners": {
+                    "description": "Usernames or IDs of the users who own the tenant",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quota": {
+                    "description": "Resource limits of the tenant",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pkg_handler_api_v1_tenant.TenantQuotaRequest"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status of the tenant, defaults to active",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended"
+                    ]
+                }
+            }
+        },
+        "pkg_handler_api_v1_tenant.QueryTenantRequest": {
+            "type": "object",
+            "required": [
+                "page",
+                "perPage"
+            ],
+            "properties": {
+                "keyword": {
+                    "description": "Keyword is the keyword to search for.\nOptional: true",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page is the page number, starting from 1.\nRequired: true, Minimum value: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "perPage": {
+                    "description": "PerPage is the number of items per page.\nRequired: true, Minimum value: 1, Maximum value: 300",
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 1
+                }
+            }
+        },
+        "pkg_handler_api_v1_tenant.TenantQuotaRequest": {
+            "type": "object",
+            "properties": {
+                "maxConfigSize": {
+                    "description": "Maximum size in bytes of the content of a single system config",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "maxConfigs": {
+                    "description": "Maximum number of system configs owned by the tenant",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "maxRevisions": {
+                    "description": "Maximum number of revisions retained for a single system config",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "pkg_handler_api_v1_tenant.UpdateTenantRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "displayName": {
+                    "description": "Human readable name of the tenant",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the tenant",
+                    "type": "integer"
+                },
+                "modifier": {
+                    "description": "Username or ID of the user who last modified the tenant, overridden by the\nauthenticated principal of the request",
+                    "type": "string"
+                },
+                "owners": {
+                    "description": "Usernames or IDs of the users who own the tenant",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quota": {
+                    "description": "Resource limits of the tenant, replaces the existing quota if present",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pkg_handler_api_v1_tenant.TenantQuotaRequest"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status of the tenant, suspended tenants are read-only",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended"
+                    ]
+                }
+            }
         }
     }
 }`
 
-type swaggerInfo struct {
-	Version     string
-	Host        string
-	BasePath    string
-	Schemes     []string
-	Title       string
-	Description string
-}
-
 // SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = swaggerInfo{
-	Version:     "",
-	Host:        "",
-	BasePath:    "",
-	Schemes:     []string{},
-	Title:       "",
-	Description: "",
-}
-
-type s struct{}
-
-func (s *s) ReadDoc() string {
-	sInfo := SwaggerInfo
-	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
-
-	t, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-			return string(a)
-		},
-		"escape": func(v interface{}) string {
-			// escape tabs
-			str := strings.Replace(v.(string), "\t", "\\t", -1)
-			// replace " with \", and if that results in \\", replace that with \\\"
-			str = strings.Replace(str, "\"", "\\\"", -1)
-			return strings.Replace(str, "\\\\\"", "\\\\\\\"", -1)
-		},
-	}).Parse(doc)
-	if err != nil {
-		return doc
-	}
-
-	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, sInfo); err != nil {
-		return doc
-	}
-
-	return tpl.String()
+var SwaggerInfo = &swag.Spec{
+	Version:          "",
+	Host:             "",
+	BasePath:         "",
+	Schemes:          []string{},
+	Title:            "",
+	Description:      "",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
 }
 
 func init() {
-	swag.Register("swagger", &s{})
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
 }
@@ -0,0 +1,10 @@
+// Package assets embeds the assets which go along with the binary.
+package assets
+
+import "embed"
+
+// Migrations holds the versioned schema migrations of each database driver,
+// in the migrations/<driver>/<version>_<name>.<up|down>.sql layout.
+//
+//go:embed migrations
+var Migrations embed.FS
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `system_config_label` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `system_config_id` bigint(20) unsigned NOT NULL COMMENT '系统配置ID',
+  `label_key` varchar(63) NOT NULL COMMENT '标签键',
+  `label_value` varchar(63) NOT NULL DEFAULT '' COMMENT '标签值',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_system_config_label_key` (`system_config_id`, `label_key`),
+  KEY `idx_system_config_label_kv` (`label_key`, `label_value`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '系统配置标签表';
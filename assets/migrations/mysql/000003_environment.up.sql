@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS `environment` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `updated_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '修改时间',
+  `name` varchar(50) NOT NULL COMMENT '环境名称',
+  `description` varchar(256) DEFAULT NULL COMMENT '描述',
+  `sort_order` int(11) NOT NULL DEFAULT 0 COMMENT '晋级顺序',
+  `protected` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否受保护',
+  `creator` varchar(32) DEFAULT NULL COMMENT '创建人',
+  `modifier` varchar(32) DEFAULT NULL COMMENT '修改人',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_environment_name` (`name`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '环境表';
+
+INSERT IGNORE INTO `environment` (`name`, `description`, `sort_order`, `protected`, `creator`) VALUES
+  ('dev', 'Development environment', 10, 0, 'system'),
+  ('test', 'Test environment', 20, 0, 'system'),
+  ('stable', 'Stable environment', 30, 0, 'system'),
+  ('pre', 'Pre-release environment', 40, 1, 'system'),
+  ('gray', 'Gray release environment', 50, 1, 'system'),
+  ('prod', 'Production environment', 60, 1, 'system');
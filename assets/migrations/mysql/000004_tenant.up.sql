@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS `tenant` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `updated_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '修改时间',
+  `name` varchar(32) NOT NULL COMMENT '租户名称',
+  `display_name` varchar(128) DEFAULT NULL COMMENT '显示名称',
+  `owners` text DEFAULT NULL COMMENT '负责人',
+  `status` varchar(16) NOT NULL DEFAULT 'active' COMMENT '状态',
+  `max_configs` int(11) NOT NULL DEFAULT 0 COMMENT '最大配置数量',
+  `max_config_size` int(11) NOT NULL DEFAULT 0 COMMENT '单个配置最大字节数',
+  `max_revisions` int(11) NOT NULL DEFAULT 0 COMMENT '单个配置最大保留版本数',
+  `creator` varchar(32) DEFAULT NULL COMMENT '创建人',
+  `modifier` varchar(32) DEFAULT NULL COMMENT '修改人',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_tenant_name` (`name`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '租户表';
+
+INSERT IGNORE INTO `tenant` (`name`, `display_name`, `owners`, `status`, `creator`) VALUES
+  ('MAIN_SITE', 'Main site', 'system', 'active', 'system');
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS `system_config_schedule` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `updated_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '修改时间',
+  `system_config_id` bigint(20) unsigned NOT NULL COMMENT '系统配置ID',
+  `target` longtext NOT NULL COMMENT '目标配置',
+  `previous` longtext DEFAULT NULL COMMENT '生效前配置快照',
+  `effective_at` timestamp(3) NOT NULL COMMENT '生效时间',
+  `expires_at` timestamp(3) NULL DEFAULT NULL COMMENT '过期回滚时间',
+  `status` varchar(16) NOT NULL DEFAULT 'pending' COMMENT '状态',
+  `message` varchar(1024) DEFAULT NULL COMMENT '失败原因',
+  `creator` varchar(32) DEFAULT NULL COMMENT '创建人',
+  `applied_at` timestamp(3) NULL DEFAULT NULL COMMENT '生效执行时间',
+  `reverted_at` timestamp(3) NULL DEFAULT NULL COMMENT '回滚执行时间',
+  `lock_owner` varchar(128) DEFAULT NULL COMMENT '租约持有者',
+  `locked_until` timestamp(3) NULL DEFAULT NULL COMMENT '租约到期时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_system_config_schedule_config` (`system_config_id`),
+  KEY `idx_system_config_schedule_effective` (`status`, `effective_at`),
+  KEY `idx_system_config_schedule_expires` (`status`, `expires_at`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '系统配置定时变更表';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `system_config_revision` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `system_config_id` bigint(20) unsigned NOT NULL COMMENT '系统配置ID',
+  `revision` int(11) NOT NULL COMMENT '版本号',
+  `snapshot` longtext NOT NULL COMMENT '配置快照',
+  `reason` varchar(256) DEFAULT NULL COMMENT '变更原因',
+  `operator` varchar(32) DEFAULT NULL COMMENT '操作人',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_system_config_revision` (`system_config_id`, `revision`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '系统配置版本表';
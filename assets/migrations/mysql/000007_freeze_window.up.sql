@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `freeze_window` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `updated_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '修改时间',
+  `name` varchar(128) NOT NULL COMMENT '冻结窗口名称',
+  `tenant` varchar(32) NOT NULL DEFAULT '' COMMENT '租户，为空表示全部',
+  `env` varchar(50) NOT NULL DEFAULT '' COMMENT '环境，为空表示全部',
+  `type` varchar(32) NOT NULL DEFAULT '' COMMENT '类型，为空表示全部',
+  `start_at` timestamp(3) NOT NULL COMMENT '开始时间',
+  `end_at` timestamp(3) NOT NULL COMMENT '结束时间',
+  `description` varchar(256) DEFAULT NULL COMMENT '描述',
+  `creator` varchar(32) DEFAULT NULL COMMENT '创建人',
+  `modifier` varchar(32) DEFAULT NULL COMMENT '修改人',
+  PRIMARY KEY (`id`),
+  KEY `idx_freeze_window_period` (`start_at`, `end_at`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '冻结窗口表';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `freeze_override` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `freeze_window_id` bigint(20) unsigned NOT NULL COMMENT '冻结窗口ID',
+  `system_config_id` bigint(20) unsigned NOT NULL COMMENT '系统配置ID',
+  `action` varchar(16) NOT NULL COMMENT '操作类型',
+  `reason` varchar(256) NOT NULL COMMENT '豁免原因',
+  `operator` varchar(32) NOT NULL COMMENT '操作人',
+  PRIMARY KEY (`id`),
+  KEY `idx_freeze_override_window` (`freeze_window_id`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '冻结窗口豁免记录表';
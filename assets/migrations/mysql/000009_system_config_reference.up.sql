@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `system_config_reference` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `system_config_id` bigint(20) unsigned NOT NULL COMMENT '引用方系统配置ID',
+  `ref_tenant` varchar(32) NOT NULL COMMENT '被引用配置的租户',
+  `ref_env` varchar(50) NOT NULL COMMENT '被引用配置的环境',
+  `ref_type` varchar(32) NOT NULL COMMENT '被引用配置的类型',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_system_config_reference` (`system_config_id`, `ref_tenant`, `ref_env`, `ref_type`),
+  KEY `idx_system_config_reference_target` (`ref_tenant`, `ref_env`, `ref_type`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '系统配置引用表';
@@ -0,0 +1 @@
+ALTER TABLE `system_config` DROP COLUMN `live`;
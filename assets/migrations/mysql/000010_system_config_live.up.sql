@@ -0,0 +1,3 @@
+-- 未删除标记，已删除的记录为NULL，使唯一约束只作用于未删除的配置
+ALTER TABLE `system_config`
+  ADD COLUMN `live` tinyint(1) GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, 1, NULL)) VIRTUAL COMMENT '未删除标记，已删除的记录为NULL，不参与唯一约束' AFTER `deleted_at`;
@@ -0,0 +1 @@
+ALTER TABLE `system_config` DROP INDEX `uniq_system_config_key`;
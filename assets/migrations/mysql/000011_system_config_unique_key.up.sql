@@ -0,0 +1,32 @@
+-- 同一租户、环境和类型只能有一个未删除的配置
+-- 创建唯一约束前，保留每组重复配置中最新创建的一条，其余软删除
+-- 被删除的配置先记录一条版本快照，原因为 migration/11/duplicate-of/<保留的配置ID>
+INSERT INTO `system_config_revision` (`system_config_id`, `revision`, `snapshot`, `reason`, `operator`)
+SELECT c.`id`,
+  COALESCE((SELECT MAX(r.`revision`) FROM `system_config_revision` AS r WHERE r.`system_config_id` = c.`id`), 0) + 1,
+  JSON_OBJECT('id', c.`id`, 'tenant', c.`tenant`, 'env', c.`env`, 'type', c.`type`, 'config', c.`config`,
+    'description', c.`description`, 'creator', c.`creator`, 'modifier', c.`modifier`),
+  CONCAT('migration/11/duplicate-of/', d.`keep_id`),
+  'system'
+FROM `system_config` AS c
+JOIN (
+  SELECT `tenant`, `env`, `type`, MAX(`id`) AS `keep_id`
+  FROM `system_config`
+  WHERE `deleted_at` IS NULL
+  GROUP BY `tenant`, `env`, `type`
+  HAVING COUNT(*) > 1
+) AS d ON c.`tenant` = d.`tenant` AND c.`env` = d.`env` AND c.`type` = d.`type`
+WHERE c.`deleted_at` IS NULL AND c.`id` < d.`keep_id`;
+
+UPDATE `system_config` AS c
+JOIN (
+  SELECT `tenant`, `env`, `type`, MAX(`id`) AS `keep_id`
+  FROM `system_config`
+  WHERE `deleted_at` IS NULL
+  GROUP BY `tenant`, `env`, `type`
+  HAVING COUNT(*) > 1
+) AS d ON c.`tenant` = d.`tenant` AND c.`env` = d.`env` AND c.`type` = d.`type`
+SET c.`deleted_at` = CURRENT_TIMESTAMP(3)
+WHERE c.`deleted_at` IS NULL AND c.`id` < d.`keep_id`;
+
+ALTER TABLE `system_config` ADD UNIQUE KEY `uniq_system_config_key` (`tenant`, `env`, `type`, `live`);
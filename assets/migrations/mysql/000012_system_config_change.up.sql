@@ -0,0 +1,11 @@
+-- 系统配置变更序列表，各副本轮询以失效本地缓存
+CREATE TABLE IF NOT EXISTS `system_config_change` (
+  `seq` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '变更序号',
+  `changed_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '变更时间',
+  `system_config_id` bigint(20) unsigned NOT NULL COMMENT '系统配置ID',
+  `tenant` varchar(32) NOT NULL COMMENT '租户',
+  `env` varchar(50) NOT NULL COMMENT '环境',
+  `type` varchar(32) NOT NULL COMMENT '类型',
+  PRIMARY KEY (`seq`),
+  KEY `idx_system_config_change_changed_at` (`changed_at`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '系统配置变更序列表';
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS `idempotency_record`;
+-- 幂等请求记录表，重试的请求重放其记录的响应
+CREATE TABLE IF NOT EXISTS `idempotency_record` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `idempotency_key` varchar(255) NOT NULL COMMENT '幂等键',
+  `principal` varchar(255) NOT NULL DEFAULT '' COMMENT '请求主体',
+  `fingerprint` char(64) NOT NULL COMMENT '请求指纹',
+  `status_code` int NOT NULL DEFAULT 0 COMMENT '响应状态码，处理中为0',
+  `content_type` varchar(255) NOT NULL DEFAULT '' COMMENT '响应类型',
+  `body` mediumblob NULL COMMENT '响应内容',
+  `created_at` timestamp(3) NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+  `expires_at` timestamp(3) NOT NULL COMMENT '过期时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_idempotency_record_principal_key` (`principal`, `idempotency_key`),
+  KEY `idx_idempotency_record_expires_at` (`expires_at`)
+) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMMENT = '幂等请求记录表';
@@ -0,0 +1,22 @@
+-- 环境表
+CREATE TABLE IF NOT EXISTS "environment" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP,
+  "name" varchar(50) NOT NULL,
+  "description" varchar(256) DEFAULT NULL,
+  "sort_order" integer NOT NULL DEFAULT 0,
+  "protected" boolean NOT NULL DEFAULT false,
+  "creator" varchar(32) DEFAULT NULL,
+  "modifier" varchar(32) DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_environment_name" ON "environment" ("name");
+
+INSERT INTO "environment" ("name", "description", "sort_order", "protected", "creator") VALUES
+  ('dev', 'Development environment', 10, false, 'system'),
+  ('test', 'Test environment', 20, false, 'system'),
+  ('stable', 'Stable environment', 30, false, 'system'),
+  ('pre', 'Pre-release environment', 40, true, 'system'),
+  ('gray', 'Gray release environment', 50, true, 'system'),
+  ('prod', 'Production environment', 60, true, 'system')
+ON CONFLICT DO NOTHING;
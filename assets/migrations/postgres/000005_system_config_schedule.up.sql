@@ -0,0 +1,21 @@
+-- 系统配置定时变更表
+CREATE TABLE IF NOT EXISTS "system_config_schedule" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP,
+  "system_config_id" bigint NOT NULL,
+  "target" text NOT NULL,
+  "previous" text DEFAULT NULL,
+  "effective_at" timestamptz(3) NOT NULL,
+  "expires_at" timestamptz(3) DEFAULT NULL,
+  "status" varchar(16) NOT NULL DEFAULT 'pending',
+  "message" varchar(1024) DEFAULT NULL,
+  "creator" varchar(32) DEFAULT NULL,
+  "applied_at" timestamptz(3) DEFAULT NULL,
+  "reverted_at" timestamptz(3) DEFAULT NULL,
+  "lock_owner" varchar(128) DEFAULT NULL,
+  "locked_until" timestamptz(3) DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_system_config_schedule_config" ON "system_config_schedule" ("system_config_id");
+CREATE INDEX IF NOT EXISTS "idx_system_config_schedule_effective" ON "system_config_schedule" ("status", "effective_at");
+CREATE INDEX IF NOT EXISTS "idx_system_config_schedule_expires" ON "system_config_schedule" ("status", "expires_at");
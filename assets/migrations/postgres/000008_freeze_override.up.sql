@@ -0,0 +1,11 @@
+-- 冻结窗口豁免记录表
+CREATE TABLE IF NOT EXISTS "freeze_override" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP,
+  "freeze_window_id" bigint NOT NULL,
+  "system_config_id" bigint NOT NULL,
+  "action" varchar(16) NOT NULL,
+  "reason" varchar(256) NOT NULL,
+  "operator" varchar(32) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_freeze_override_window" ON "freeze_override" ("freeze_window_id");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "system_config_reference";
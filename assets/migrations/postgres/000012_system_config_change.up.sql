@@ -0,0 +1,10 @@
+-- 系统配置变更序列表，各副本轮询以失效本地缓存
+CREATE TABLE IF NOT EXISTS "system_config_change" (
+  "seq" bigserial PRIMARY KEY,
+  "changed_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP,
+  "system_config_id" bigint NOT NULL,
+  "tenant" varchar(32) NOT NULL,
+  "env" varchar(50) NOT NULL,
+  "type" varchar(32) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_system_config_change_changed_at" ON "system_config_change" ("changed_at");
@@ -0,0 +1 @@
+-- system_config 是基线表，保存着已有的配置数据，回滚时不删除
@@ -0,0 +1,15 @@
+-- 系统配置表
+CREATE TABLE IF NOT EXISTS `system_config` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `tenant` varchar(32) NOT NULL DEFAULT 'MAIN_SITE',
+  `env` varchar(50) NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `config` text DEFAULT NULL,
+  `description` varchar(256) DEFAULT NULL,
+  `creator` varchar(32) DEFAULT NULL,
+  `modifier` varchar(32) DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS `idx_system_config_deleted_at` ON `system_config` (`deleted_at`);
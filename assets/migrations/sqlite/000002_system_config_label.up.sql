@@ -0,0 +1,10 @@
+-- 系统配置标签表
+CREATE TABLE IF NOT EXISTS `system_config_label` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `system_config_id` integer NOT NULL,
+  `label_key` varchar(63) NOT NULL,
+  `label_value` varchar(63) NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `uniq_system_config_label_key` ON `system_config_label` (`system_config_id`, `label_key`);
+CREATE INDEX IF NOT EXISTS `idx_system_config_label_kv` ON `system_config_label` (`label_key`, `label_value`);
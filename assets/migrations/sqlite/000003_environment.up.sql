@@ -0,0 +1,21 @@
+-- 环境表
+CREATE TABLE IF NOT EXISTS `environment` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `name` varchar(50) NOT NULL,
+  `description` varchar(256) DEFAULT NULL,
+  `sort_order` integer NOT NULL DEFAULT 0,
+  `protected` boolean NOT NULL DEFAULT 0,
+  `creator` varchar(32) DEFAULT NULL,
+  `modifier` varchar(32) DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `uniq_environment_name` ON `environment` (`name`);
+
+INSERT OR IGNORE INTO `environment` (`name`, `description`, `sort_order`, `protected`, `creator`) VALUES
+  ('dev', 'Development environment', 10, 0, 'system'),
+  ('test', 'Test environment', 20, 0, 'system'),
+  ('stable', 'Stable environment', 30, 0, 'system'),
+  ('pre', 'Pre-release environment', 40, 1, 'system'),
+  ('gray', 'Gray release environment', 50, 1, 'system'),
+  ('prod', 'Production environment', 60, 1, 'system');
@@ -0,0 +1,19 @@
+-- 租户表
+CREATE TABLE IF NOT EXISTS `tenant` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `name` varchar(32) NOT NULL,
+  `display_name` varchar(128) DEFAULT NULL,
+  `owners` text DEFAULT NULL,
+  `status` varchar(16) NOT NULL DEFAULT 'active',
+  `max_configs` integer NOT NULL DEFAULT 0,
+  `max_config_size` integer NOT NULL DEFAULT 0,
+  `max_revisions` integer NOT NULL DEFAULT 0,
+  `creator` varchar(32) DEFAULT NULL,
+  `modifier` varchar(32) DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `uniq_tenant_name` ON `tenant` (`name`);
+
+INSERT OR IGNORE INTO `tenant` (`name`, `display_name`, `owners`, `status`, `creator`) VALUES
+  ('MAIN_SITE', 'Main site', 'system', 'active', 'system');
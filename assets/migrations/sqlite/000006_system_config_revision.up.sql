@@ -0,0 +1,11 @@
+-- 系统配置版本表
+CREATE TABLE IF NOT EXISTS `system_config_revision` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `system_config_id` integer NOT NULL,
+  `revision` integer NOT NULL,
+  `snapshot` text NOT NULL,
+  `reason` varchar(256) DEFAULT NULL,
+  `operator` varchar(32) DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `uniq_system_config_revision` ON `system_config_revision` (`system_config_id`, `revision`);
@@ -0,0 +1,16 @@
+-- 冻结窗口表
+CREATE TABLE IF NOT EXISTS `freeze_window` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `name` varchar(128) NOT NULL,
+  `tenant` varchar(32) NOT NULL DEFAULT '',
+  `env` varchar(50) NOT NULL DEFAULT '',
+  `type` varchar(32) NOT NULL DEFAULT '',
+  `start_at` datetime NOT NULL,
+  `end_at` datetime NOT NULL,
+  `description` varchar(256) DEFAULT NULL,
+  `creator` varchar(32) DEFAULT NULL,
+  `modifier` varchar(32) DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS `idx_freeze_window_period` ON `freeze_window` (`start_at`, `end_at`);
@@ -0,0 +1,11 @@
+-- 系统配置引用表
+CREATE TABLE IF NOT EXISTS `system_config_reference` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `system_config_id` integer NOT NULL,
+  `ref_tenant` varchar(32) NOT NULL,
+  `ref_env` varchar(50) NOT NULL,
+  `ref_type` varchar(32) NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `uniq_system_config_reference` ON `system_config_reference` (`system_config_id`, `ref_tenant`, `ref_env`, `ref_type`);
+CREATE INDEX IF NOT EXISTS `idx_system_config_reference_target` ON `system_config_reference` (`ref_tenant`, `ref_env`, `ref_type`);
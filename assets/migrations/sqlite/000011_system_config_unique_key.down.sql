@@ -0,0 +1 @@
+DROP INDEX IF EXISTS `uniq_system_config_key`;
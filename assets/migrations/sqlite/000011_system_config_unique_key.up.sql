@@ -0,0 +1,28 @@
+-- 同一租户、环境和类型只能有一个未删除的配置
+-- 创建唯一约束前，保留每组重复配置中最新创建的一条，其余软删除
+-- 被删除的配置先记录一条版本快照，原因为 migration/11/duplicate-of/<保留的配置ID>
+INSERT INTO `system_config_revision` (`system_config_id`, `revision`, `snapshot`, `reason`, `operator`)
+SELECT c.`id`,
+  (SELECT COALESCE(MAX(r.`revision`), 0) + 1 FROM `system_config_revision` AS r WHERE r.`system_config_id` = c.`id`),
+  json_object('id', c.`id`, 'tenant', c.`tenant`, 'env', c.`env`, 'type', c.`type`, 'config', c.`config`,
+    'description', c.`description`, 'creator', c.`creator`, 'modifier', c.`modifier`),
+  'migration/11/duplicate-of/' || (
+    SELECT MAX(n.`id`) FROM `system_config` AS n
+    WHERE n.`tenant` = c.`tenant` AND n.`env` = c.`env` AND n.`type` = c.`type` AND n.`deleted_at` IS NULL
+  ),
+  'system'
+FROM `system_config` AS c
+WHERE c.`deleted_at` IS NULL AND EXISTS (
+  SELECT 1 FROM `system_config` AS n
+  WHERE n.`tenant` = c.`tenant` AND n.`env` = c.`env` AND n.`type` = c.`type`
+    AND n.`deleted_at` IS NULL AND n.`id` > c.`id`
+);
+
+UPDATE `system_config` SET `deleted_at` = CURRENT_TIMESTAMP
+WHERE `deleted_at` IS NULL AND EXISTS (
+  SELECT 1 FROM `system_config` AS n
+  WHERE n.`tenant` = `system_config`.`tenant` AND n.`env` = `system_config`.`env` AND n.`type` = `system_config`.`type`
+    AND n.`deleted_at` IS NULL AND n.`id` > `system_config`.`id`
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `uniq_system_config_key` ON `system_config` (`tenant`, `env`, `type`) WHERE `deleted_at` IS NULL;
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS `idempotency_record`;
+-- 幂等请求记录表，重试的请求重放其记录的响应
+CREATE TABLE IF NOT EXISTS `idempotency_record` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `idempotency_key` varchar(255) NOT NULL,
+  `principal` varchar(255) NOT NULL DEFAULT '',
+  `fingerprint` char(64) NOT NULL,
+  `status_code` integer NOT NULL DEFAULT 0,
+  `content_type` varchar(255) NOT NULL DEFAULT '',
+  `body` blob,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `expires_at` datetime NOT NULL,
+  UNIQUE (`principal`, `idempotency_key`)
+);
+CREATE INDEX IF NOT EXISTS `idx_idempotency_record_expires_at` ON `idempotency_record` (`expires_at`);
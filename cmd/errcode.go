@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/elliotxx/go-web-template/cmd/options"
+	"github.com/elliotxx/go-web-template/pkg/util/cmdutil"
+)
+
+// NewErrcodeCommand creates the "errcode" command which documents the error
+// codes
+func NewErrcodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "errcode",
+		Short: "Document the error codes",
+	}
+
+	cmd.AddCommand(newErrcodeDocsCommand())
+
+	return cmd
+}
+
+// newErrcodeDocsCommand creates the "errcode docs" command
+func newErrcodeDocsCommand() *cobra.Command {
+	o := options.NewErrcodeOptions()
+
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate the documents of the error codes",
+		Long: `Docs generates the documents of the registered error codes:
+
+  ` + options.ErrcodeMarkdownFile + `                  the Markdown table of the codes, their statuses and messages
+  ` + options.ErrcodeOpenAPIFile + `     the OpenAPI components of the codes`,
+		Example: `  # Update the documents of the error codes
+  app errcode docs`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.GenerateDocs(cmd.OutOrStdout()))
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -45,6 +45,11 @@ func NewAppCommand() *cobra.Command {
 	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
 	cliflag.SetUsageAndHelpFunc(cmd, namedFlagSets, cols)
 
+	// Add sub commands
+	cmd.AddCommand(NewMigrateCommand())
+	cmd.AddCommand(NewScaffoldCommand())
+	cmd.AddCommand(NewErrcodeCommand())
+
 	return cmd
 }
 
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/term"
+
+	"github.com/elliotxx/go-web-template/cmd/options"
+	"github.com/elliotxx/go-web-template/pkg/util/cmdutil"
+)
+
+// NewMigrateCommand creates the "migrate" command which applies and reverts
+// the versioned schema migrations embedded in the binary
+func NewMigrateCommand() *cobra.Command {
+	o := options.NewAppOptions()
+
+	cmd := &cobra.Command{
+		Use:   "migrate up|down|status|to <version>",
+		Short: "Migrate the database schema",
+		Long: `Migrate applies and reverts the versioned schema migrations embedded in the binary.
+
+  up              apply all pending migrations
+  down            revert the last applied migration
+  status          list the migrations and whether they are applied
+  to <version>    apply or revert migrations until the database is at the version, 0 reverts all`,
+		Args:          cobra.RangeArgs(1, 2),
+		ValidArgs:     []string{options.MigrateUp, options.MigrateDown, options.MigrateStatus, options.MigrateTo},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(args))
+			cmdutil.CheckErr(o.ValidateMigrate())
+			cmdutil.CheckErr(o.Migrate(cmd.OutOrStdout(), args[0], args[1:]))
+		},
+	}
+
+	// Add flags of each option to migrate command
+	fs := cmd.Flags()
+	namedFlagSets := o.MigrateFlags()
+	for _, f := range namedFlagSets.FlagSets {
+		fs.AddFlagSet(f)
+	}
+
+	// Group options by flag set
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, namedFlagSets, cols)
+
+	return cmd
+}
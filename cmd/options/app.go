@@ -30,19 +30,25 @@ var envLoader = &multiconfig.EnvironmentLoader{
 
 // AppOptions runs a App server.
 type AppOptions struct {
-	Generic  *GenericOptions  `json:"generic,omitempty" yaml:"generic,omitempty"`
-	Logging  *LoggingOptions  `json:"logging,omitempty" yaml:"logging,omitempty"`
-	Network  *NetworkOptions  `json:"network,omitempty" yaml:"network,omitempty"`
-	Database *DatabaseOptions `json:"database,omitempty" yaml:"database,omitempty"`
+	Generic   *GenericOptions   `json:"generic,omitempty" yaml:"generic,omitempty"`
+	Logging   *LoggingOptions   `json:"logging,omitempty" yaml:"logging,omitempty"`
+	Network   *NetworkOptions   `json:"network,omitempty" yaml:"network,omitempty"`
+	Database  *DatabaseOptions  `json:"database,omitempty" yaml:"database,omitempty"`
+	Scheduler *SchedulerOptions `json:"scheduler,omitempty" yaml:"scheduler,omitempty"`
+	Cache     *CacheOptions     `json:"cache,omitempty" yaml:"cache,omitempty"`
+	Locale    *LocaleOptions    `json:"locale,omitempty" yaml:"locale,omitempty"`
 }
 
 // NewAppOptions creates a new AppOptions object with default parameters
 func NewAppOptions() *AppOptions {
 	return &AppOptions{
-		Generic:  NewGenericOptions(),
-		Logging:  NewLoggingOptions(),
-		Network:  NewNetworkOptions(),
-		Database: NewDatabaseOptions(),
+		Generic:   NewGenericOptions(),
+		Logging:   NewLoggingOptions(),
+		Network:   NewNetworkOptions(),
+		Database:  NewDatabaseOptions(),
+		Scheduler: NewSchedulerOptions(),
+		Cache:     NewCacheOptions(),
+		Locale:    NewLocaleOptions(),
 	}
 }
 
@@ -53,6 +59,9 @@ func (o *AppOptions) Flags() (fss flag.NamedFlagSets) {
 	o.Network.AddFlags(fss.FlagSet("network"))
 	o.Generic.AddFlags(fss.FlagSet("generic"))
 	o.Database.AddFlags(fss.FlagSet("database"))
+	o.Scheduler.AddFlags(fss.FlagSet("scheduler"))
+	o.Cache.AddFlags(fss.FlagSet("cache"))
+	o.Locale.AddFlags(fss.FlagSet("locale"))
 	return fss
 }
 
@@ -86,6 +95,9 @@ func (o *AppOptions) Validate() (err error) {
 	if !o.Generic.DumpVersion && !o.Generic.DumpEnvs {
 		err = multierror.Append(err, multierror.Flatten(o.Logging.Validate()))
 		err = multierror.Append(err, multierror.Flatten(o.Network.Validate()))
+		err = multierror.Append(err, multierror.Flatten(o.Scheduler.Validate()))
+		err = multierror.Append(err, multierror.Flatten(o.Cache.Validate()))
+		err = multierror.Append(err, multierror.Flatten(o.Locale.Validate()))
 		// err = multierror.Append(err, multierror.Flatten(o.Database.Validate()))
 	}
 
@@ -97,6 +109,10 @@ func (o *AppOptions) Config() *server.Config {
 	o.Generic.ApplyTo(cfg)
 	o.Database.ApplyTo(cfg)
 	o.Logging.ApplyTo(cfg)
+	o.Network.ApplyTo(cfg)
+	o.Scheduler.ApplyTo(cfg)
+	o.Cache.ApplyTo(cfg)
+	o.Locale.ApplyTo(cfg)
 	return cfg
 }
 
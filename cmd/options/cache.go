@@ -0,0 +1,86 @@
+package options
+
+import (
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/cache"
+	"github.com/elliotxx/go-web-template/pkg/server"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+)
+
+var _ types.Options = &CacheOptions{}
+
+// CacheOptions is a system config cache options struct
+type CacheOptions struct {
+	CacheSize         int           `json:"cacheSize,omitempty" yaml:"cacheSize,omitempty"`
+	CacheTTL          time.Duration `json:"cacheTTL,omitempty" yaml:"cacheTTL,omitempty"`
+	CacheNegativeTTL  time.Duration `json:"cacheNegativeTTL,omitempty" yaml:"cacheNegativeTTL,omitempty"`
+	CachePollInterval time.Duration `json:"cachePollInterval,omitempty" yaml:"cachePollInterval,omitempty"`
+}
+
+// NewCacheOptions returns a CacheOptions instance with the default values,
+// the cache is disabled by default
+func NewCacheOptions() *CacheOptions {
+	return &CacheOptions{
+		CacheTTL:          cache.DefaultTTL,
+		CacheNegativeTTL:  cache.DefaultNegativeTTL,
+		CachePollInterval: cache.DefaultPollInterval,
+	}
+}
+
+// Validate checks CacheOptions and return a slice of found error(s)
+func (o *CacheOptions) Validate() error {
+	if o == nil {
+		return errors.Errorf("options is nil")
+	}
+
+	var err *multierror.Error
+
+	if o.CacheSize < 0 {
+		err = multierror.Append(err, errors.Errorf("--cache-size must not be negative"))
+	}
+
+	if o.CacheSize > 0 {
+		if o.CacheTTL <= 0 {
+			err = multierror.Append(err, errors.Errorf("--cache-ttl must be greater than 0"))
+		}
+		if o.CacheNegativeTTL <= 0 {
+			err = multierror.Append(err, errors.Errorf("--cache-negative-ttl must be greater than 0"))
+		}
+		if o.CachePollInterval <= 0 {
+			err = multierror.Append(err, errors.Errorf("--cache-poll-interval must be greater than 0"))
+		}
+	}
+
+	return err.ErrorOrNil()
+}
+
+// ApplyTo apply cache options to the server config
+func (o *CacheOptions) ApplyTo(config *server.Config) {
+	config.CacheSize = o.CacheSize
+	config.CacheTTL = o.CacheTTL
+	config.CacheNegativeTTL = o.CacheNegativeTTL
+	config.CachePollInterval = o.CachePollInterval
+}
+
+// AddFlags adds flags for a specific Option to the specified FlagSet
+func (o *CacheOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.IntVar(&o.CacheSize, "cache-size", o.CacheSize,
+		"The maximum number of system config lookups cached in this replica, 0 disables the cache")
+
+	fs.DurationVar(&o.CacheTTL, "cache-ttl", o.CacheTTL,
+		"How long a found system config is cached")
+
+	fs.DurationVar(&o.CacheNegativeTTL, "cache-negative-ttl", o.CacheNegativeTTL,
+		"How long a missing system config is cached")
+
+	fs.DurationVar(&o.CachePollInterval, "cache-poll-interval", o.CachePollInterval,
+		"The interval between two polls of the system config changes made by other replicas")
+}
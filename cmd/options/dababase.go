@@ -1,72 +1,339 @@
 package options
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"expvar"
+	"net"
+	"net/url"
 	"os"
+	"path"
 	"strconv"
-	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/elliotxx/go-web-template/assets"
 	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/gormlog"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/memory"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/migration"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
 	"github.com/elliotxx/go-web-template/pkg/server"
+	"github.com/glebarez/sqlite"
 	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+)
+
+const (
+	DBDriverMySQL    = "mysql"
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+	// DBDriverMemory keeps the system configs in memory and the other
+	// tables in an in-memory sqlite database, all data is lost on exit
+	DBDriverMemory = "memory"
 )
 
 var (
-	ErrDBHostNotSpecified               = errors.New("--db-host must be specified")
-	ErrDBNameNotSpecified               = errors.New("--db-name must be specified")
-	ErrDBUserNotSpecified               = errors.New("--db-user must be specified")
-	ErrDBPortNotSpecified               = errors.New("--db-port must be specified")
-	_                     types.Options = &DatabaseOptions{}
+	ErrDBDriverNotSupported               = errors.New("--db-driver must be one of mysql, postgres, sqlite and memory")
+	ErrDBHostNotSpecified                 = errors.New("--db-host must be specified")
+	ErrDBNameNotSpecified                 = errors.New("--db-name must be specified")
+	ErrDBUserNotSpecified                 = errors.New("--db-user must be specified")
+	ErrDBPortNotSpecified                 = errors.New("--db-port must be specified")
+	_                       types.Options = &DatabaseOptions{}
 )
 
+// mysqlTLSConfigName is the name the TLS config is registered with in
+// go-sql-driver/mysql.
+const mysqlTLSConfigName = "app"
+
+// defaultDBPorts are the ports used when --db-port isn't specified.
+var defaultDBPorts = map[string]int{
+	DBDriverMySQL:    3306,
+	DBDriverPostgres: 5432,
+}
+
 // DatabaseOptions is a Database options struct
 type DatabaseOptions struct {
+	// DBDriver is one of mysql, postgres and sqlite
+	DBDriver string `json:"dbDriver,omitempty" yaml:"dbDriver,omitempty"`
+	// DBName is the database name, or the database file path for sqlite
 	DBName     string `json:"dbName,omitempty" yaml:"dbName,omitempty"`
 	DBUser     string `json:"dbUser,omitempty" yaml:"dbUser,omitempty"`
 	DBPassword string `json:"dbPassword,omitempty" yaml:"dbPassword,omitempty"`
 	DBHost     string `json:"dbHost,omitempty" yaml:"dbHost,omitempty"`
 	DBPort     int    `json:"dbPort,omitempty" yaml:"dbPort,omitempty"`
-	// AutoMigrate will attempt to automatically migrate all tables
-	AutoMigrate bool   `json:"autoMigrate,omitempty" yaml:"autoMigrate,omitempty"`
-	MigrateFile string `json:"migrateFile,omitempty" yaml:"migrateFile,omitempty"`
+	// DBCharset and DBCollation are the character set and collation of the
+	// mysql connection, the collation defaults to the one of the charset
+	DBCharset   string `json:"dbCharset,omitempty" yaml:"dbCharset,omitempty"`
+	DBCollation string `json:"dbCollation,omitempty" yaml:"dbCollation,omitempty"`
+	// DBTimeZone is the time zone of the session, used to parse and format
+	// the times
+	DBTimeZone string `json:"dbTimeZone,omitempty" yaml:"dbTimeZone,omitempty"`
+	// DBTLS enables TLS to the database, the server certificate is
+	// verified against DBTLSCA, or the system roots if it's empty, unless
+	// DBTLSSkipVerify is set
+	DBTLS           bool   `json:"dbTLS,omitempty" yaml:"dbTLS,omitempty"`
+	DBTLSCA         string `json:"dbTLSCA,omitempty" yaml:"dbTLSCA,omitempty"`
+	DBTLSSkipVerify bool   `json:"dbTLSSkipVerify,omitempty" yaml:"dbTLSSkipVerify,omitempty"`
+	// Connection pool of the database, zero means unlimited
+	DBMaxOpenConns    int           `json:"dbMaxOpenConns,omitempty" yaml:"dbMaxOpenConns,omitempty"`
+	DBMaxIdleConns    int           `json:"dbMaxIdleConns,omitempty" yaml:"dbMaxIdleConns,omitempty"`
+	DBConnMaxLifetime time.Duration `json:"dbConnMaxLifetime,omitempty" yaml:"dbConnMaxLifetime,omitempty"`
+	DBConnMaxIdleTime time.Duration `json:"dbConnMaxIdleTime,omitempty" yaml:"dbConnMaxIdleTime,omitempty"`
+	// Timeouts of establishing a connection and of the I/O on it, the read
+	// and write timeouts only apply to mysql
+	DBDialTimeout  time.Duration `json:"dbDialTimeout,omitempty" yaml:"dbDialTimeout,omitempty"`
+	DBReadTimeout  time.Duration `json:"dbReadTimeout,omitempty" yaml:"dbReadTimeout,omitempty"`
+	DBWriteTimeout time.Duration `json:"dbWriteTimeout,omitempty" yaml:"dbWriteTimeout,omitempty"`
+	// DBReplicas are the DSNs of the read replicas of the database, in the
+	// format of the driver, the read-only queries of the system configs are
+	// spread over them
+	DBReplicas []string `json:"dbReplicas,omitempty" yaml:"dbReplicas,omitempty"`
+	// DBReadYourWritesWindow is how long a caller writing with the
+	// X-Read-Your-Writes header reads from the primary afterwards
+	DBReadYourWritesWindow time.Duration `json:"dbReadYourWritesWindow,omitempty" yaml:"dbReadYourWritesWindow,omitempty"`
+	// DBLogLevel is the level of the SQL logs, one of silent, error, warn and
+	// info, independent of the log level of the application
+	DBLogLevel string `json:"dbLogLevel,omitempty" yaml:"dbLogLevel,omitempty"`
+	// DBSlowThreshold is the cost time over which the statements are logged
+	// at warn level as slow SQL, zero disables it
+	DBSlowThreshold time.Duration `json:"dbSlowThreshold,omitempty" yaml:"dbSlowThreshold,omitempty"`
+	// AutoMigrate applies the pending migrations when the server starts,
+	// the same as running "app migrate up" before
+	AutoMigrate bool `json:"autoMigrate,omitempty" yaml:"autoMigrate,omitempty"`
 }
 
 // NewDatabaseOptions returns a DatabaseOptions instance with the default values
 func NewDatabaseOptions() *DatabaseOptions {
 	return &DatabaseOptions{
-		DBHost:      "127.0.0.1",
-		DBPort:      3306,
-		AutoMigrate: false,
+		DBDriver:               DBDriverMySQL,
+		DBHost:                 "127.0.0.1",
+		DBCharset:              "utf8mb4",
+		DBTimeZone:             "Asia/Shanghai",
+		DBMaxOpenConns:         100,
+		DBMaxIdleConns:         10,
+		DBConnMaxLifetime:      time.Hour,
+		DBConnMaxIdleTime:      10 * time.Minute,
+		DBDialTimeout:          5 * time.Second,
+		DBReadTimeout:          30 * time.Second,
+		DBWriteTimeout:         30 * time.Second,
+		DBReadYourWritesWindow: 5 * time.Second,
+		DBLogLevel:             "warn",
+		DBSlowThreshold:        200 * time.Millisecond,
+		AutoMigrate:            false,
 	}
 }
 
 // InstallDB uses the run options to generate and open a db session.
 func (o *DatabaseOptions) InstallDB() (*gorm.DB, error) {
-	// Generate go-sql-driver.mysql config to format DSN
+	dialector, err := o.dialector()
+	if err != nil {
+		return nil, err
+	}
+
+	logLevel, err := gormlog.ParseLevel(o.DBLogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the SQL logs through the logger of the request, the logger is
+	// also a plugin recording the SQL without the bound parameters
+	sqlLogger := gormlog.New(logLevel, o.DBSlowThreshold)
+	cfg := &gorm.Config{
+		Logger:  sqlLogger,
+		Plugins: map[string]gorm.Plugin{sqlLogger.Name(): sqlLogger},
+		// Translate the unique key violations to gorm.ErrDuplicatedKey
+		TranslateError: true,
+	}
+	db, err := o.open(dialector, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.DBReplicas) == 0 {
+		return db, nil
+	}
+
+	// Register the read replicas to the primary
+	replicas := make([]*gorm.DB, 0, len(o.DBReplicas))
+	for i, dsn := range o.DBReplicas {
+		replica, err := o.open(o.replicaDialector(dsn), cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open replica #%d", i)
+		}
+		replicas = append(replicas, replica)
+	}
+	if err = db.Use(persistence.NewReplicaSet(replicas...)); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// open opens the database with the sized connection pool.
+func (o *DatabaseOptions) open(dialector gorm.Dialector, cfg *gorm.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Size the connection pool
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(o.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(o.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(o.DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(o.DBConnMaxIdleTime)
+
+	return db, nil
+}
+
+// dialector returns the gorm dialector of the driver with the DSN built
+// from the options.
+func (o *DatabaseOptions) dialector() (gorm.Dialector, error) {
+	switch o.DBDriver {
+	case DBDriverMySQL:
+		dsn, err := o.mysqlDSN()
+		if err != nil {
+			return nil, err
+		}
+		return mysql.Open(dsn), nil
+	case DBDriverPostgres:
+		return postgres.Open(o.postgresDSN()), nil
+	case DBDriverSQLite:
+		return sqlite.Open(o.sqliteDSN()), nil
+	case DBDriverMemory:
+		// The memdb VFS shares the database among the connections
+		return sqlite.Open("file:/appdb?vfs=memdb&_pragma=busy_timeout(5000)"), nil
+	default:
+		return nil, ErrDBDriverNotSupported
+	}
+}
+
+// replicaDialector returns the gorm dialector of the driver with the DSN
+// of a replica.
+func (o *DatabaseOptions) replicaDialector(dsn string) gorm.Dialector {
+	if o.DBDriver == DBDriverPostgres {
+		return postgres.Open(dsn)
+	}
+	return mysql.Open(dsn)
+}
+
+// mysqlDSN formats the DSN with go-sql-driver/mysql config.
+func (o *DatabaseOptions) mysqlDSN() (string, error) {
+	loc, err := time.LoadLocation(o.DBTimeZone)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid --db-timezone %q", o.DBTimeZone)
+	}
+
 	config := gomysql.NewConfig()
 	config.User = o.DBUser
 	config.Passwd = o.DBPassword
-	config.Addr = o.DBHost + ":" + strconv.Itoa(o.DBPort)
+	config.Addr = net.JoinHostPort(o.DBHost, strconv.Itoa(o.port()))
 	config.DBName = o.DBName
 	config.Net = "tcp"
 	config.ParseTime = true
-	config.InterpolateParams = true
+	config.Loc = loc
+	config.Collation = o.DBCollation
+	config.Timeout = o.DBDialTimeout
+	config.ReadTimeout = o.DBReadTimeout
+	config.WriteTimeout = o.DBWriteTimeout
 	config.Params = map[string]string{
-		"charset": "utf8",
-		"loc":     "Asia/Shanghai",
+		"charset": o.DBCharset,
 	}
-	dsn := config.FormatDSN()
-	// silence log output
-	cfg := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+
+	if o.DBTLS {
+		tlsConfig, err := o.tlsConfig()
+		if err != nil {
+			return "", err
+		}
+		if err = gomysql.RegisterTLSConfig(mysqlTLSConfigName, tlsConfig); err != nil {
+			return "", err
+		}
+		config.TLSConfig = mysqlTLSConfigName
 	}
-	return gorm.Open(mysql.Open(dsn), cfg) // todo: add db connection check to healthz check
+
+	return config.FormatDSN(), nil
+}
+
+// postgresDSN formats the DSN as a URL, so that the password doesn't need
+// to be quoted.
+func (o *DatabaseOptions) postgresDSN() string {
+	query := url.Values{
+		"sslmode":  []string{"disable"},
+		"TimeZone": []string{o.DBTimeZone},
+	}
+	if o.DBDialTimeout > 0 {
+		// connect_timeout is in seconds, round up to keep it non-zero
+		query.Set("connect_timeout", strconv.Itoa(int((o.DBDialTimeout+time.Second-1)/time.Second)))
+	}
+	if o.DBTLS {
+		switch {
+		case o.DBTLSSkipVerify:
+			query.Set("sslmode", "require")
+		case o.DBTLSCA != "":
+			query.Set("sslmode", "verify-full")
+			query.Set("sslrootcert", o.DBTLSCA)
+		default:
+			query.Set("sslmode", "verify-full")
+		}
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(o.DBUser, o.DBPassword),
+		Host:     net.JoinHostPort(o.DBHost, strconv.Itoa(o.port())),
+		Path:     "/" + o.DBName,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
+
+// tlsConfig returns the TLS config verifying the server certificate
+// against the CA file if it's specified.
+func (o *DatabaseOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         o.DBHost,
+		InsecureSkipVerify: o.DBTLSSkipVerify, //nolint:gosec
+	}
+	if o.DBTLSCA == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(o.DBTLSCA)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read --db-tls-ca")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Errorf("no certificates found in --db-tls-ca %s", o.DBTLSCA)
+	}
+	tlsConfig.RootCAs = pool
+
+	return tlsConfig, nil
+}
+
+// sqliteDSN formats the DSN of the database file, writers wait for the lock
+// instead of failing with SQLITE_BUSY.
+func (o *DatabaseOptions) sqliteDSN() string {
+	return o.DBName + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+}
+
+// port returns the specified port or the default port of the driver.
+func (o *DatabaseOptions) port() int {
+	if o.DBPort != 0 {
+		return o.DBPort
+	}
+	return defaultDBPorts[o.DBDriver]
 }
 
 // Validate checks DatabaseOptions and return a slice of found error(s)
@@ -75,24 +342,66 @@ func (o *DatabaseOptions) Validate() error {
 		return errors.Errorf("options is nil")
 	}
 
-	if o.AutoMigrate && len(o.MigrateFile) == 0 {
-		return errors.Errorf("when --auto-migrate is true, --migrate-file must be specified")
+	var err *multierror.Error
+	switch o.DBDriver {
+	case DBDriverMySQL, DBDriverPostgres:
+		if len(o.DBHost) == 0 {
+			err = multierror.Append(err, ErrDBHostNotSpecified)
+		}
+		if len(o.DBName) == 0 {
+			err = multierror.Append(err, ErrDBNameNotSpecified)
+		}
+		if len(o.DBUser) == 0 {
+			err = multierror.Append(err, ErrDBUserNotSpecified)
+		}
+		if o.port() <= 0 {
+			err = multierror.Append(err, ErrDBPortNotSpecified)
+		}
+		if _, e := time.LoadLocation(o.DBTimeZone); e != nil {
+			err = multierror.Append(err, errors.Errorf("--db-timezone %q is invalid", o.DBTimeZone))
+		}
+		if !o.DBTLS && (o.DBTLSCA != "" || o.DBTLSSkipVerify) {
+			err = multierror.Append(err, errors.Errorf("--db-tls-ca and --db-tls-skip-verify require --db-tls"))
+		}
+	case DBDriverSQLite:
+		// The database is a local file, only its path is needed
+		if len(o.DBName) == 0 {
+			err = multierror.Append(err, ErrDBNameNotSpecified)
+		}
+		if len(o.DBReplicas) > 0 {
+			err = multierror.Append(err, errors.Errorf("--db-replicas isn't supported by sqlite"))
+		}
+	case DBDriverMemory:
+		if len(o.DBReplicas) > 0 {
+			err = multierror.Append(err, errors.Errorf("--db-replicas isn't supported by memory"))
+		}
+	default:
+		return ErrDBDriverNotSupported
 	}
 
-	if len(o.DBHost) == 0 {
-		return ErrDBHostNotSpecified
+	if o.DBMaxOpenConns < 0 || o.DBMaxIdleConns < 0 {
+		err = multierror.Append(err, errors.Errorf("--db-max-open-conns and --db-max-idle-conns must not be negative"))
+	}
+	if o.DBMaxOpenConns > 0 && o.DBMaxIdleConns > o.DBMaxOpenConns {
+		err = multierror.Append(err, errors.Errorf("--db-max-idle-conns must not be greater than --db-max-open-conns"))
+	}
+	if o.DBConnMaxLifetime < 0 || o.DBConnMaxIdleTime < 0 {
+		err = multierror.Append(err, errors.Errorf("--db-conn-max-lifetime and --db-conn-max-idle-time must not be negative"))
+	}
+	if o.DBDialTimeout < 0 || o.DBReadTimeout < 0 || o.DBWriteTimeout < 0 {
+		err = multierror.Append(err, errors.Errorf("--db-dial-timeout, --db-read-timeout and --db-write-timeout must not be negative"))
 	}
-	if len(o.DBName) == 0 {
-		return ErrDBNameNotSpecified
+	if _, e := gormlog.ParseLevel(o.DBLogLevel); e != nil {
+		err = multierror.Append(err, errors.Errorf("--db-log-level %q is invalid, must be one of silent, error, warn and info", o.DBLogLevel))
 	}
-	if len(o.DBUser) == 0 {
-		return ErrDBUserNotSpecified
+	if o.DBSlowThreshold < 0 {
+		err = multierror.Append(err, errors.Errorf("--db-slow-threshold must not be negative"))
 	}
-	if o.DBPort == 0 {
-		return ErrDBPortNotSpecified
+	if o.DBReadYourWritesWindow < 0 {
+		err = multierror.Append(err, errors.Errorf("--db-read-your-writes-window must not be negative"))
 	}
 
-	return nil
+	return err.ErrorOrNil()
 }
 
 // ApplyTo apply database options to the server config
@@ -102,43 +411,105 @@ func (o *DatabaseOptions) ApplyTo(config *server.Config) {
 		logrus.Fatalf("Failed to apply database options to server.Config as: %+v", err)
 	}
 	config.DB = d
+	config.ReadYourWritesWindow = o.DBReadYourWritesWindow
 
-	// AutoMigrate will attempt to automatically migrate all tables
-	if o.AutoMigrate {
-		logrus.Debugf("AutoMigrate will attempt to automatically migrate all tables from [%s]", o.MigrateFile)
-		// Read all content by migrate file
-		migrateSQL, err := os.ReadFile(o.MigrateFile)
+	// Publish the connection pool statistics of the primary and the
+	// replicas, you can visit http://localhost/debug/vars to view them
+	statsDB.Store(d)
+	if expvar.Get("dbStats") == nil {
+		expvar.Publish("dbStats", expvar.Func(func() any {
+			return dbStats(statsDB.Load())
+		}))
+	}
+
+	// The system configs are kept by the in-memory repository, which
+	// doesn't enforce the rules kept in the other tables
+	if o.DBDriver == DBDriverMemory {
+		config.SystemConfigs = memory.NewSystemConfigRepository()
+	}
+
+	// AutoMigrate applies the pending migrations embedded in the binary,
+	// the in-memory database always starts empty
+	if o.AutoMigrate || o.DBDriver == DBDriverMemory {
+		migrator, err := o.Migrator(d)
+		if err != nil {
+			logrus.Fatalf("Failed to load migrations: %+v", err)
+		}
+
+		applied, err := migrator.Up(context.Background())
 		if err != nil {
-			logrus.Fatalf("Failed to read migrate file: %+v", err)
+			logrus.Fatalf("Failed to migrate database: %+v", err)
+		}
+		for _, m := range applied {
+			logrus.Infof("Applied migration %s", m)
 		}
+	}
+}
 
-		// Split multiple SQL statements into individual statements
-		stmts := strings.Split(string(migrateSQL), ";")
+// statsDB is the database published as the dbStats expvar, an expvar can
+// only be published once, so it follows the database applied last.
+var statsDB atomic.Pointer[gorm.DB]
 
-		// Iterate over all statements and execute them
-		for _, stmt := range stmts {
-			// Ignore empty statements
-			if len(strings.TrimSpace(stmt)) == 0 {
-				continue
-			}
+// dbStats returns the connection pool statistics of the primary and of the
+// replicas of the database.
+func dbStats(db *gorm.DB) map[string]any {
+	if db == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil
+	}
 
-			// Use gorm.Exec() function to execute SQL statement
-			if err = config.DB.Exec(stmt).Error; err != nil {
-				logrus.Warnf("Failed to exec migrate sql: %+v", err)
-			}
-		}
+	stats := map[string]any{"primary": sqlDB.Stats()}
+	if replicas := persistence.ReplicasOf(db); replicas != nil {
+		stats["replicas"] = replicas.Stats()
 	}
+	return stats
+}
+
+// Migrator returns the migrator of the migrations of the driver.
+func (o *DatabaseOptions) Migrator(db *gorm.DB) (*migration.Migrator, error) {
+	// The in-memory database is a sqlite database
+	driver := o.DBDriver
+	if driver == DBDriverMemory {
+		driver = DBDriverSQLite
+	}
+
+	migrations, err := migration.Load(assets.Migrations, path.Join("migrations", driver))
+	if err != nil {
+		return nil, err
+	}
+
+	return migration.New(db, migrations), nil
 }
 
 // AddFlags adds flags for a specific Option to the specified FlagSet
 func (o *DatabaseOptions) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&o.DBName, "db-name", o.DBName, "the database name")
+	fs.StringVar(&o.DBDriver, "db-driver", o.DBDriver, "the database driver, one of mysql, postgres, sqlite and memory")
+	fs.StringVar(&o.DBName, "db-name", o.DBName, "the database name, or the database file path for sqlite")
 	fs.StringVar(&o.DBUser, "db-user", o.DBUser, "the user name used to access database")
 	fs.StringVar(&o.DBPassword, "db-pwd", o.DBPassword, "the user password used to access database")
 	fs.StringVar(&o.DBHost, "db-host", o.DBHost, "database host")
-	fs.IntVar(&o.DBPort, "db-port", o.DBPort, "database port")
-	fs.BoolVar(&o.AutoMigrate, "auto-migrate", o.AutoMigrate, "Whether to enable automatic migration")
-	fs.StringVar(&o.MigrateFile, "migrate-file", o.MigrateFile, "The migrate sql file")
+	fs.IntVar(&o.DBPort, "db-port", o.DBPort, "database port, defaults to 3306 for mysql and 5432 for postgres")
+	fs.StringVar(&o.DBCharset, "db-charset", o.DBCharset, "the character set of the mysql connection")
+	fs.StringVar(&o.DBCollation, "db-collation", o.DBCollation, "the collation of the mysql connection, defaults to the one of the charset")
+	fs.StringVar(&o.DBTimeZone, "db-timezone", o.DBTimeZone, "the time zone of the database session")
+	fs.BoolVar(&o.DBTLS, "db-tls", o.DBTLS, "Whether to connect to the database with TLS")
+	fs.StringVar(&o.DBTLSCA, "db-tls-ca", o.DBTLSCA, "the CA file to verify the database certificate, defaults to the system roots")
+	fs.BoolVar(&o.DBTLSSkipVerify, "db-tls-skip-verify", o.DBTLSSkipVerify, "Whether to skip verifying the database certificate")
+	fs.IntVar(&o.DBMaxOpenConns, "db-max-open-conns", o.DBMaxOpenConns, "the maximum number of open connections to the database, 0 means unlimited")
+	fs.IntVar(&o.DBMaxIdleConns, "db-max-idle-conns", o.DBMaxIdleConns, "the maximum number of idle connections in the pool")
+	fs.DurationVar(&o.DBConnMaxLifetime, "db-conn-max-lifetime", o.DBConnMaxLifetime, "the maximum time a connection may be reused, 0 means forever")
+	fs.DurationVar(&o.DBConnMaxIdleTime, "db-conn-max-idle-time", o.DBConnMaxIdleTime, "the maximum time a connection may be idle, 0 means forever")
+	fs.DurationVar(&o.DBDialTimeout, "db-dial-timeout", o.DBDialTimeout, "the timeout of establishing a connection")
+	fs.DurationVar(&o.DBReadTimeout, "db-read-timeout", o.DBReadTimeout, "the I/O read timeout of mysql connections")
+	fs.DurationVar(&o.DBWriteTimeout, "db-write-timeout", o.DBWriteTimeout, "the I/O write timeout of mysql connections")
+	fs.StringSliceVar(&o.DBReplicas, "db-replicas", o.DBReplicas, "the DSNs of the read replicas, the read-only queries of the system configs are spread over them")
+	fs.DurationVar(&o.DBReadYourWritesWindow, "db-read-your-writes-window", o.DBReadYourWritesWindow, "how long a caller writing with the X-Read-Your-Writes header reads from the primary afterwards")
+	fs.StringVar(&o.DBLogLevel, "db-log-level", o.DBLogLevel, "the level of the SQL logs, one of silent, error, warn and info")
+	fs.DurationVar(&o.DBSlowThreshold, "db-slow-threshold", o.DBSlowThreshold, "the cost time over which the statements are logged as slow SQL, 0 disables it")
+	fs.BoolVar(&o.AutoMigrate, "auto-migrate", o.AutoMigrate, "Whether to apply the pending migrations when the server starts")
 }
 
 // MarshalJSON is custom marshalling function for masking sensitive field values
@@ -146,5 +517,9 @@ func (o DatabaseOptions) MarshalJSON() ([]byte, error) {
 	type tempOptions DatabaseOptions
 	o2 := tempOptions(o)
 	o2.DBPassword = types.MaskString
+	if len(o2.DBReplicas) > 0 {
+		// The DSNs of the replicas contain the password too
+		o2.DBReplicas = []string{types.MaskString}
+	}
 	return json.Marshal(&o2)
 }
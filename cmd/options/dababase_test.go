@@ -0,0 +1,227 @@
+package options
+
+import (
+	"database/sql"
+	"encoding/json"
+	"expvar"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/elliotxx/go-web-template/pkg/server"
+	"github.com/glebarez/sqlite"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestDatabaseOptions(t *testing.T) {
+	newOptions := func(driver string) *DatabaseOptions {
+		o := NewDatabaseOptions()
+		o.DBDriver = driver
+		o.DBName = "app"
+		o.DBUser = "root"
+		return o
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			driver string
+			modify func(o *DatabaseOptions)
+			errMsg string
+		}{
+			"mysql defaults": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) {},
+			},
+			"postgres defaults": {
+				driver: DBDriverPostgres,
+				modify: func(o *DatabaseOptions) {},
+			},
+			"unknown driver": {
+				driver: "oracle",
+				modify: func(o *DatabaseOptions) {},
+				errMsg: ErrDBDriverNotSupported.Error(),
+			},
+			"invalid time zone": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBTimeZone = "Mars/Olympus" },
+				errMsg: `--db-timezone "Mars/Olympus" is invalid`,
+			},
+			"tls ca without tls": {
+				driver: DBDriverPostgres,
+				modify: func(o *DatabaseOptions) { o.DBTLSCA = "ca.pem" },
+				errMsg: "--db-tls-ca and --db-tls-skip-verify require --db-tls",
+			},
+			"tls skip verify without tls": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBTLSSkipVerify = true },
+				errMsg: "--db-tls-ca and --db-tls-skip-verify require --db-tls",
+			},
+			"negative pool size": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBMaxIdleConns = -1 },
+				errMsg: "--db-max-open-conns and --db-max-idle-conns must not be negative",
+			},
+			"more idle than open connections": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBMaxOpenConns, o.DBMaxIdleConns = 5, 10 },
+				errMsg: "--db-max-idle-conns must not be greater than --db-max-open-conns",
+			},
+			"idle connections with unlimited open connections": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBMaxOpenConns, o.DBMaxIdleConns = 0, 10 },
+			},
+			"negative connection lifetime": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBConnMaxIdleTime = -time.Second },
+				errMsg: "--db-conn-max-lifetime and --db-conn-max-idle-time must not be negative",
+			},
+			"negative timeout": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBReadTimeout = -time.Second },
+				errMsg: "--db-dial-timeout, --db-read-timeout and --db-write-timeout must not be negative",
+			},
+			"invalid log level": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBLogLevel = "debug" },
+				errMsg: `--db-log-level "debug" is invalid`,
+			},
+			"negative slow threshold": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBSlowThreshold = -time.Second },
+				errMsg: "--db-slow-threshold must not be negative",
+			},
+			"negative read your writes window": {
+				driver: DBDriverMySQL,
+				modify: func(o *DatabaseOptions) { o.DBReadYourWritesWindow = -time.Second },
+				errMsg: "--db-read-your-writes-window must not be negative",
+			},
+			"sqlite replicas": {
+				driver: DBDriverSQLite,
+				modify: func(o *DatabaseOptions) { o.DBReplicas = []string{"replica.db"} },
+				errMsg: "--db-replicas isn't supported by sqlite",
+			},
+			"memory replicas": {
+				driver: DBDriverMemory,
+				modify: func(o *DatabaseOptions) { o.DBReplicas = []string{"replica"} },
+				errMsg: "--db-replicas isn't supported by memory",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				o := newOptions(tc.driver)
+				tc.modify(o)
+				err := o.Validate()
+				if tc.errMsg == "" {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorContains(t, err, tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("MySQL DSN", func(t *testing.T) {
+		o := newOptions(DBDriverMySQL)
+		o.DBPassword = "p@ss:word"
+		o.DBCollation = "utf8mb4_unicode_ci"
+		dsn, err := o.mysqlDSN()
+		require.NoError(t, err)
+
+		config, err := gomysql.ParseDSN(dsn)
+		require.NoError(t, err)
+		require.Equal(t, "p@ss:word", config.Passwd)
+		require.Equal(t, "127.0.0.1:3306", config.Addr)
+		require.Equal(t, "utf8mb4_unicode_ci", config.Collation)
+		require.Equal(t, "utf8mb4", config.Params["charset"])
+		require.Equal(t, "Asia/Shanghai", config.Loc.String())
+		require.Equal(t, 5*time.Second, config.Timeout)
+		require.Equal(t, 30*time.Second, config.ReadTimeout)
+		require.Equal(t, 30*time.Second, config.WriteTimeout)
+		require.False(t, config.InterpolateParams)
+		require.Empty(t, config.TLSConfig)
+
+		o.DBTLS, o.DBTLSSkipVerify = true, true
+		dsn, err = o.mysqlDSN()
+		require.NoError(t, err)
+		config, err = gomysql.ParseDSN(dsn)
+		require.NoError(t, err)
+		require.Equal(t, mysqlTLSConfigName, config.TLSConfig)
+
+		o.DBTLSCA = "missing-ca.pem"
+		_, err = o.mysqlDSN()
+		require.ErrorContains(t, err, "failed to read --db-tls-ca")
+	})
+
+	t.Run("Postgres DSN", func(t *testing.T) {
+		o := newOptions(DBDriverPostgres)
+		o.DBDialTimeout = 1500 * time.Millisecond
+		dsn, err := url.Parse(o.postgresDSN())
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1:5432", dsn.Host)
+		require.Equal(t, "/app", dsn.Path)
+		require.Equal(t, "disable", dsn.Query().Get("sslmode"))
+		require.Equal(t, "2", dsn.Query().Get("connect_timeout"))
+
+		o.DBTLS, o.DBTLSCA = true, "ca.pem"
+		dsn, err = url.Parse(o.postgresDSN())
+		require.NoError(t, err)
+		require.Equal(t, "verify-full", dsn.Query().Get("sslmode"))
+		require.Equal(t, "ca.pem", dsn.Query().Get("sslrootcert"))
+
+		o.DBTLSCA, o.DBTLSSkipVerify = "", true
+		dsn, err = url.Parse(o.postgresDSN())
+		require.NoError(t, err)
+		require.Equal(t, "require", dsn.Query().Get("sslmode"))
+	})
+
+	t.Run("Memory driver", func(t *testing.T) {
+		config := &server.Config{}
+		newOptions(DBDriverMemory).ApplyTo(config)
+		sqlDB, err := config.DB.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		// The system configs are kept in memory, the other tables are migrated
+		require.NotNil(t, config.SystemConfigs)
+		require.True(t, config.DB.Migrator().HasTable("tenant"))
+	})
+
+	t.Run("Publish the pool statistics", func(t *testing.T) {
+		o := newOptions(DBDriverMemory)
+		o.DBMaxOpenConns = 7
+		config := &server.Config{}
+		o.ApplyTo(config)
+		sqlDB, err := config.DB.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		// The expvar follows the database applied last
+		var published struct {
+			Primary  sql.DBStats    `json:"primary"`
+			Replicas map[string]any `json:"replicas"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(expvar.Get("dbStats").String()), &published))
+		require.Equal(t, 7, published.Primary.MaxOpenConnections)
+		require.Nil(t, published.Replicas)
+	})
+
+	t.Run("Pool statistics of the replicas", func(t *testing.T) {
+		open := func(name string) *gorm.DB {
+			db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name)), &gorm.Config{Logger: logger.Discard})
+			require.NoError(t, err)
+			t.Cleanup(func() { persistence.CloseDB(t, db) })
+			return db
+		}
+		primary := open("primary.db")
+		require.NoError(t, primary.Use(persistence.NewReplicaSet(open("replica.db"))))
+
+		stats := dbStats(primary)
+		require.IsType(t, sql.DBStats{}, stats["primary"])
+		require.Contains(t, stats["replicas"], "replica #0")
+		require.Nil(t, dbStats(nil))
+	})
+}
@@ -0,0 +1,91 @@
+package options
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+)
+
+var _ types.Options = &ErrcodeOptions{}
+
+const (
+	// ErrcodeMarkdownFile is the Markdown table of the error codes
+	ErrcodeMarkdownFile = "docs/errcode.md"
+	// ErrcodeOpenAPIFile is the OpenAPI components of the error codes
+	ErrcodeOpenAPIFile = "api/openapispec/errcode.yaml"
+)
+
+// ErrcodeOptions is an error code documentation options struct
+type ErrcodeOptions struct {
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+}
+
+// NewErrcodeOptions returns an ErrcodeOptions instance with the default values
+func NewErrcodeOptions() *ErrcodeOptions {
+	return &ErrcodeOptions{
+		Dir: ".",
+	}
+}
+
+// Validate checks ErrcodeOptions and return a slice of found error(s)
+func (o *ErrcodeOptions) Validate() error {
+	if o == nil {
+		return errors.Errorf("options is nil")
+	}
+
+	var err *multierror.Error
+
+	if o.Dir == "" {
+		err = multierror.Append(err, errors.Errorf("--dir must not be empty"))
+	}
+
+	return err.ErrorOrNil()
+}
+
+// AddFlags adds flags for a specific Option to the specified FlagSet
+func (o *ErrcodeOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.Dir, "dir", o.Dir,
+		"The root directory of the module to generate the documents into")
+}
+
+// GenerateDocs generates the Markdown and the OpenAPI documents of the
+// error codes and writes the generated files to out
+func (o *ErrcodeOptions) GenerateDocs(out io.Writer) error {
+	for file, write := range map[string]func(io.Writer) error{
+		ErrcodeMarkdownFile: errcode.WriteMarkdown,
+		ErrcodeOpenAPIFile:  errcode.WriteOpenAPI,
+	} {
+		if err := writeDoc(filepath.Join(o.Dir, file), write); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Generated %s\n", file)
+	}
+	return nil
+}
+
+// writeDoc writes the document to the file
+func writeDoc(file string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to write %s", file)
+	}
+	return f.Close()
+}
@@ -0,0 +1,64 @@
+package options
+
+import (
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/server"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+	"golang.org/x/text/language"
+)
+
+var _ types.Options = &LocaleOptions{}
+
+// LocaleOptions is a locale options struct of the error code messages
+type LocaleOptions struct {
+	// DefaultLocale is the locale of the messages for the requests accepting
+	// none of the supported locales
+	DefaultLocale string `json:"defaultLocale,omitempty" yaml:"defaultLocale,omitempty"`
+	// MessagesDirectory holds the <locale>.yaml files overriding the
+	// embedded messages of the error codes
+	MessagesDirectory string `json:"messagesDirectory,omitempty" yaml:"messagesDirectory,omitempty"`
+}
+
+// NewLocaleOptions returns a LocaleOptions instance with the default values
+func NewLocaleOptions() *LocaleOptions {
+	return &LocaleOptions{
+		DefaultLocale: errcode.DefaultLocale,
+	}
+}
+
+// Validate checks LocaleOptions and return a slice of found error(s)
+func (o *LocaleOptions) Validate() error {
+	if o == nil {
+		return errors.Errorf("options is nil")
+	}
+
+	var err *multierror.Error
+
+	if _, e := language.Parse(o.DefaultLocale); e != nil {
+		err = multierror.Append(err, errors.Errorf("invalid --default-locale %s", o.DefaultLocale))
+	}
+
+	return err.ErrorOrNil()
+}
+
+// ApplyTo apply locale options to the server config
+func (o *LocaleOptions) ApplyTo(config *server.Config) {
+	config.DefaultLocale = o.DefaultLocale
+	config.MessagesDirectory = o.MessagesDirectory
+}
+
+// AddFlags adds flags for a specific Option to the specified FlagSet
+func (o *LocaleOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.DefaultLocale, "default-locale", o.DefaultLocale,
+		"The locale of the error messages for the requests whose Accept-Language matches none of the supported locales")
+
+	fs.StringVar(&o.MessagesDirectory, "messages-directory", o.MessagesDirectory,
+		"The directory of the <locale>.yaml files overriding the error messages or adding locales, e.g. en-US.yaml")
+}
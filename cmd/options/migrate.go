@@ -0,0 +1,121 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"k8s.io/component-base/cli/flag"
+)
+
+// Migrate actions of the "app migrate" command
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+	MigrateTo     = "to"
+)
+
+// MigrateFlags returns the flags used by the migrate command by section name
+func (o *AppOptions) MigrateFlags() (fss flag.NamedFlagSets) {
+	o.Logging.AddFlags(fss.FlagSet("logging"))
+	o.Generic.AddFlags(fss.FlagSet("generic"))
+	o.Database.AddFlags(fss.FlagSet("database"))
+	return fss
+}
+
+// ValidateMigrate checks the options used by the migrate command
+func (o *AppOptions) ValidateMigrate() (err error) {
+	if o == nil {
+		return errors.Errorf("options is nil")
+	}
+
+	err = multierror.Append(err, multierror.Flatten(o.Logging.Validate()))
+	err = multierror.Append(err, multierror.Flatten(o.Database.Validate()))
+	return multierror.Flatten(err).(*multierror.Error).ErrorOrNil()
+}
+
+// Migrate runs the migrate action against the database and writes the
+// result to out, the "to" action takes the target version as argument.
+func (o *AppOptions) Migrate(out io.Writer, action string, args []string) error {
+	// Init logrus configuration by options
+	if err := o.Logging.InitLogging(types.ProjectName); err != nil {
+		return err
+	}
+
+	db, err := o.Database.InstallDB()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+	migrator, err := o.Database.Migrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch action {
+	case MigrateUp:
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(out, "Applied %s\n", m)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "No pending migrations")
+		}
+		return err
+	case MigrateDown:
+		reverted, err := migrator.Down(ctx)
+		if reverted != nil {
+			fmt.Fprintf(out, "Reverted %s\n", reverted)
+		} else if err == nil {
+			fmt.Fprintln(out, "No applied migrations")
+		}
+		return err
+	case MigrateTo:
+		if len(args) != 1 {
+			return errors.Errorf("migrate to requires exactly one version")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid version %q", args[0])
+		}
+		changed, err := migrator.To(ctx, version)
+		for _, m := range changed {
+			if m.Version > version {
+				fmt.Fprintf(out, "Reverted %s\n", m)
+			} else {
+				fmt.Fprintf(out, "Applied %s\n", m)
+			}
+		}
+		return err
+	case MigrateStatus:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			status, appliedAt := "pending", ""
+			if s.Applied {
+				status, appliedAt = "applied", s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				status = "modified"
+			}
+			if s.Missing {
+				status = "missing"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return errors.Errorf("unknown migrate action %q, expected one of up, down, status and to", action)
+	}
+}
@@ -1,10 +1,13 @@
 package options
 
 import (
+	"net/netip"
 	"time"
 
 	"github.com/elliotxx/errors"
 	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/server"
 	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/pflag"
 )
@@ -16,6 +19,15 @@ type NetworkOptions struct {
 	Port                  int           `json:"port,omitempty" yaml:"port,omitempty"`
 	CorsAllowedOriginList []string      `json:"corsAllowedOriginList,omitempty" yaml:"corsAllowedOriginList,omitempty"`
 	RequestTimeout        time.Duration `json:"requestTimeout,omitempty" yaml:"requestTimeout,omitempty"`
+	// ErrorFormat is the default format of the error responses, envelope
+	// or problem
+	ErrorFormat string `json:"errorFormat,omitempty" yaml:"errorFormat,omitempty"`
+	// IdempotencyTTL is how long the responses of the requests with the
+	// Idempotency-Key header are replayed, 0 disables the replays
+	IdempotencyTTL time.Duration `json:"idempotencyTTL,omitempty" yaml:"idempotencyTTL,omitempty"`
+	// TrustedProxies are the IPs or CIDRs of the proxies setting the
+	// X-Principal header, the header is ignored if it's empty
+	TrustedProxies []string `json:"trustedProxies,omitempty" yaml:"trustedProxies,omitempty"`
 }
 
 // NewNetworkOptions returns a NetworkOptions instance with the default values
@@ -24,6 +36,9 @@ func NewNetworkOptions() *NetworkOptions {
 		Port:                  80,
 		CorsAllowedOriginList: []string{},
 		RequestTimeout:        30 * time.Second,
+		ErrorFormat:           string(handler.ErrorFormatEnvelope),
+		IdempotencyTTL:        24 * time.Hour,
+		TrustedProxies:        []string{},
 	}
 }
 
@@ -43,9 +58,28 @@ func (o *NetworkOptions) Validate() error {
 		err = multierror.Append(err, errors.Errorf("--port must be greater than 0"))
 	}
 
+	if _, e := handler.ParseErrorFormat(o.ErrorFormat); e != nil {
+		err = multierror.Append(err, errors.Wrap(e, "invalid --error-format"))
+	}
+
+	if o.IdempotencyTTL < 0 {
+		err = multierror.Append(err, errors.Errorf("--idempotency-ttl must not be negative"))
+	}
+
+	if _, e := parsePrefixes(o.TrustedProxies); e != nil {
+		err = multierror.Append(err, errors.Wrap(e, "invalid --trusted-proxies"))
+	}
+
 	return err.ErrorOrNil()
 }
 
+// ApplyTo apply network options to the server config
+func (o *NetworkOptions) ApplyTo(config *server.Config) {
+	config.ErrorFormat, _ = handler.ParseErrorFormat(o.ErrorFormat)
+	config.IdempotencyTTL = o.IdempotencyTTL
+	config.TrustedProxies, _ = parsePrefixes(o.TrustedProxies)
+}
+
 // AddFlags adds flags for a specific Option to the specified FlagSet
 func (o *NetworkOptions) AddFlags(fs *pflag.FlagSet) {
 	if o == nil {
@@ -58,5 +92,32 @@ func (o *NetworkOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&o.RequestTimeout, "request-timeout", o.RequestTimeout,
 		"An optional field indicating the duration a handler must keep a request open before timing it out")
 
+	fs.StringVar(&o.ErrorFormat, "error-format", o.ErrorFormat,
+		"The default format of the error responses, envelope or problem (RFC 7807), the requests accepting application/problem+json get the problem anyway")
+
+	fs.DurationVar(&o.IdempotencyTTL, "idempotency-ttl", o.IdempotencyTTL,
+		"How long the responses of the POST, PUT, PATCH and DELETE requests with the Idempotency-Key header are replayed to their retries, 0 disables the replays")
+
+	fs.StringSliceVar(&o.TrustedProxies, "trusted-proxies", o.TrustedProxies,
+		"List of IPs or CIDRs of the proxies authenticating the callers and setting the X-Principal header, comma separated, the header of the other requests is ignored")
+
 	fs.IntVarP(&o.Port, "port", "p", o.Port, "Port")
 }
+
+// parsePrefixes parses the IPs or CIDRs, an IP is the prefix of its single
+// address.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, errors.Errorf("%q is neither an IP nor a CIDR", value)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,85 @@
+package options
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/scaffold"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+var _ types.Options = &ScaffoldOptions{}
+
+// ScaffoldOptions is the options struct of the scaffold command
+type ScaffoldOptions struct {
+	Dir    string
+	Fields []string
+	Force  bool
+}
+
+// NewScaffoldOptions returns a ScaffoldOptions instance with the default values
+func NewScaffoldOptions() *ScaffoldOptions {
+	return &ScaffoldOptions{
+		Dir: ".",
+	}
+}
+
+// Validate checks ScaffoldOptions and return a slice of found error(s)
+func (o *ScaffoldOptions) Validate() error {
+	if o == nil {
+		return errors.Errorf("options is nil")
+	}
+
+	var err *multierror.Error
+
+	if o.Dir == "" {
+		err = multierror.Append(err, errors.Errorf("--dir must be specified"))
+	}
+
+	if len(o.Fields) == 0 {
+		err = multierror.Append(err, errors.Errorf("--fields must be specified"))
+	}
+
+	return err.ErrorOrNil()
+}
+
+// AddFlags adds flags for a specific Option to the specified FlagSet
+func (o *ScaffoldOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.Dir, "dir", o.Dir,
+		"The root directory of the module to generate the resource into")
+
+	fs.StringSliceVar(&o.Fields, "fields", o.Fields,
+		"The fields of the resource in the name:type[:required] format, separated by commas. "+
+			"The types are string, text, int, int64, uint, float64, bool and time")
+
+	fs.BoolVar(&o.Force, "force", o.Force,
+		"Overwrite the existing files of the resource")
+}
+
+// ScaffoldResource generates the resource of the name and writes the
+// generated files to out
+func (o *ScaffoldOptions) ScaffoldResource(out io.Writer, name string) error {
+	resource, err := scaffold.NewResource(name, o.Fields)
+	if err != nil {
+		return err
+	}
+
+	g := &scaffold.Generator{Dir: o.Dir, Force: o.Force}
+	written, err := g.Generate(resource)
+	for _, path := range written {
+		fmt.Fprintf(out, "Generated %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "Run \"make gen-api-docs\" to update the api documents, and \"app migrate up\" to create the table")
+	return nil
+}
@@ -0,0 +1,74 @@
+package options
+
+import (
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/cmd/options/types"
+	"github.com/elliotxx/go-web-template/pkg/scheduler"
+	"github.com/elliotxx/go-web-template/pkg/server"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/pflag"
+)
+
+var _ types.Options = &SchedulerOptions{}
+
+// SchedulerOptions is a Scheduler options struct
+type SchedulerOptions struct {
+	EnableScheduler  bool          `json:"enableScheduler" yaml:"enableScheduler"`
+	ScheduleInterval time.Duration `json:"scheduleInterval,omitempty" yaml:"scheduleInterval,omitempty"`
+	ScheduleLease    time.Duration `json:"scheduleLease,omitempty" yaml:"scheduleLease,omitempty"`
+}
+
+// NewSchedulerOptions returns a SchedulerOptions instance with the default values
+func NewSchedulerOptions() *SchedulerOptions {
+	return &SchedulerOptions{
+		EnableScheduler:  true,
+		ScheduleInterval: scheduler.DefaultInterval,
+		ScheduleLease:    scheduler.DefaultLease,
+	}
+}
+
+// Validate checks SchedulerOptions and return a slice of found error(s)
+func (o *SchedulerOptions) Validate() error {
+	if o == nil {
+		return errors.Errorf("options is nil")
+	}
+
+	var err *multierror.Error
+
+	if o.ScheduleInterval <= 0 {
+		err = multierror.Append(err, errors.Errorf("--schedule-interval must be greater than 0"))
+	}
+
+	// The lease must outlive a scan, otherwise another replica may claim a
+	// change which is still being processed
+	if o.ScheduleLease < o.ScheduleInterval {
+		err = multierror.Append(err, errors.Errorf("--schedule-lease must not be less than --schedule-interval"))
+	}
+
+	return err.ErrorOrNil()
+}
+
+// ApplyTo apply scheduler options to the server config
+func (o *SchedulerOptions) ApplyTo(config *server.Config) {
+	config.EnableScheduler = o.EnableScheduler
+	config.ScheduleInterval = o.ScheduleInterval
+	config.ScheduleLease = o.ScheduleLease
+}
+
+// AddFlags adds flags for a specific Option to the specified FlagSet
+func (o *SchedulerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.BoolVar(&o.EnableScheduler, "enable-scheduler", o.EnableScheduler,
+		"Whether to apply and revert the scheduled system config changes in this replica")
+
+	fs.DurationVar(&o.ScheduleInterval, "schedule-interval", o.ScheduleInterval,
+		"The interval between two scans of the due scheduled changes")
+
+	fs.DurationVar(&o.ScheduleLease, "schedule-lease", o.ScheduleLease,
+		"The duration a scheduled change is leased to a replica while it's being processed")
+}
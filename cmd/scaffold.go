@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/elliotxx/go-web-template/cmd/options"
+	"github.com/elliotxx/go-web-template/pkg/util/cmdutil"
+)
+
+// NewScaffoldCommand creates the "scaffold" command which generates the code
+// of new domain resources
+func NewScaffoldCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Generate the code of new domain resources",
+	}
+
+	cmd.AddCommand(newScaffoldResourceCommand())
+
+	return cmd
+}
+
+// newScaffoldResourceCommand creates the "scaffold resource" command
+func newScaffoldResourceCommand() *cobra.Command {
+	o := options.NewScaffoldOptions()
+
+	cmd := &cobra.Command{
+		Use:   "resource <Name> --fields name:type[:required],...",
+		Short: "Generate a CRUD resource",
+		Long: `Resource generates a CRUD resource following the layout of the existing resources:
+
+  pkg/domain/entity                   the entity and its validation
+  pkg/domain/repository               the repository interface
+  pkg/infrastructure/persistence      the model, the repository and its tests
+  pkg/handler/api/v1/<name>           the handler with swag annotations, its requests and responses
+  assets/migrations/<driver>          the migrations creating the table of every driver
+  pkg/route/route.go                  the routes of the handler`,
+		Example: `  # Generate the price rule resource
+  app scaffold resource PriceRule --fields name:string:required,description:text,discount:float64,enabled:bool,expiresAt:time`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.ScaffoldResource(cmd.OutOrStdout(), args[0]))
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrEnvNotFound is returned when an environment is not defined.
+	ErrEnvNotFound = errors.New("environment is not defined")
+	// ErrEnvProtected is returned when deleting a protected environment.
+	ErrEnvProtected = errors.New("environment is protected")
+	// ErrEnvInUse is returned when deleting an environment which is still
+	// referenced by system configs.
+	ErrEnvInUse = errors.New("environment is in use")
+)
+
+// Environment represents a managed environment where systems are deployed.
+type Environment struct {
+	// Unique ID of the environment
+	ID uint `yaml:"id" json:"id"`
+	// Name of the environment (e.g. prod, gray), which is immutable
+	Name Env `yaml:"name" json:"name"`
+	// Description or purpose of the environment
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Order of the environment in the promotion path, environments with a
+	// lower order are promoted to environments with a higher order
+	Order int `yaml:"order" json:"order"`
+	// Protected environments can't be deleted
+	Protected bool `yaml:"protected" json:"protected"`
+	// Username or ID of the user who created the environment
+	Creator string `yaml:"creator,omitempty" json:"creator,omitempty"`
+	// Username or ID of the user who last modified the environment
+	Modifier string `yaml:"modifier,omitempty" json:"modifier,omitempty"`
+	// Timestamp when the environment was created
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+	// Timestamp when the environment was last updated
+	UpdatedAt time.Time `yaml:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// Validate checks if the environment is valid.
+// It returns an error if the environment is not valid.
+func (e *Environment) Validate() error {
+	if _, err := ParseEnv(string(e.Name)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrConfigFrozen is returned when writing a system config covered by an
+// active freeze window without an override.
+var ErrConfigFrozen = errors.New("system config is frozen")
+
+// These constants represent the write actions recorded by a freeze override.
+const (
+	FreezeActionCreate = "create"
+	FreezeActionUpdate = "update"
+	FreezeActionDelete = "delete"
+)
+
+// FreezeWindow represents a period of time during which the system configs
+// in its scope can't be written. An empty tenant, env or type matches all
+// system configs.
+type FreezeWindow struct {
+	// Unique ID of the freeze window
+	ID uint `yaml:"id" json:"id"`
+	// Name of the freeze window (e.g. 2023 spring festival)
+	Name string `yaml:"name" json:"name"`
+	// Tenant of the frozen system configs, empty means all tenants
+	Tenant string `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+	// Environment of the frozen system configs, empty means all environments
+	Env Env `yaml:"env,omitempty" json:"env,omitempty"`
+	// Type of the frozen system configs, empty means all types
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// Time when the freeze starts
+	StartAt time.Time `yaml:"startAt" json:"startAt"`
+	// Time when the freeze ends
+	EndAt time.Time `yaml:"endAt" json:"endAt"`
+	// Description or purpose of the freeze window
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Username or ID of the user who created the freeze window
+	Creator string `yaml:"creator,omitempty" json:"creator,omitempty"`
+	// Username or ID of the user who last modified the freeze window
+	Modifier string `yaml:"modifier,omitempty" json:"modifier,omitempty"`
+	// Timestamp when the freeze window was created
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+	// Timestamp when the freeze window was last updated
+	UpdatedAt time.Time `yaml:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// Validate checks if the freeze window is valid.
+// It returns an error if the freeze window is not valid.
+func (w *FreezeWindow) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("freeze window must have a name")
+	}
+
+	if w.Env != "" {
+		if _, err := ParseEnv(string(w.Env)); err != nil {
+			return err
+		}
+	}
+
+	if !w.EndAt.After(w.StartAt) {
+		return fmt.Errorf("end time %s must be after start time %s",
+			w.EndAt.Format(time.RFC3339), w.StartAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Covers returns true if the system configs of the given scope are frozen by
+// the window at the given time.
+func (w *FreezeWindow) Covers(tenant string, env Env, typ string, at time.Time) bool {
+	if w.Tenant != "" && w.Tenant != tenant {
+		return false
+	}
+	if w.Env != "" && w.Env != env {
+		return false
+	}
+	if w.Type != "" && w.Type != typ {
+		return false
+	}
+
+	return !at.Before(w.StartAt) && at.Before(w.EndAt)
+}
+
+// FreezeOverride records a write to a frozen system config which has been
+// allowed by an admin.
+type FreezeOverride struct {
+	// Unique ID of the override
+	ID uint `yaml:"id" json:"id"`
+	// ID of the bypassed freeze window
+	FreezeWindowID uint `yaml:"freezeWindowID" json:"freezeWindowID"`
+	// ID of the written system config
+	SystemConfigID uint `yaml:"systemConfigID" json:"systemConfigID"`
+	// Write action on the system config (create, update or delete)
+	Action string `yaml:"action" json:"action"`
+	// Why the freeze had to be bypassed
+	Reason string `yaml:"reason" json:"reason"`
+	// Username or ID of the admin who bypassed the freeze
+	Operator string `yaml:"operator" json:"operator"`
+	// Timestamp when the override was recorded
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+}
+
+// Validate checks if the override is valid.
+// It returns an error if the override is not valid.
+func (o *FreezeOverride) Validate() error {
+	if o.Reason == "" {
+		return fmt.Errorf("freeze override must have a reason")
+	}
+
+	if o.Operator == "" {
+		return fmt.Errorf("freeze override must have an operator")
+	}
+
+	return nil
+}
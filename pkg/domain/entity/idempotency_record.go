@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIdempotencyKeyInUse is returned when recording a request whose
+// idempotency key is already recorded.
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is in use")
+
+// IdempotencyRecord keeps the response of a mutating request sent with an
+// Idempotency-Key header, so that the retries of the request are answered
+// with the same response instead of repeating its side effects.
+type IdempotencyRecord struct {
+	// Key is the Idempotency-Key header of the request
+	Key string `yaml:"key" json:"key"`
+	// Principal making the request, the same key of different principals
+	// identifies different requests
+	Principal string `yaml:"principal" json:"principal"`
+	// Fingerprint of the method, the URL and the body of the request
+	Fingerprint string `yaml:"fingerprint" json:"fingerprint"`
+	// StatusCode of the response, zero while the request is in progress
+	StatusCode int `yaml:"statusCode,omitempty" json:"statusCode,omitempty"`
+	// ContentType of the response
+	ContentType string `yaml:"contentType,omitempty" json:"contentType,omitempty"`
+	// Body of the response
+	Body []byte `yaml:"body,omitempty" json:"body,omitempty"`
+	// Timestamp when the request was recorded
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+	// Timestamp after which the key may identify another request
+	ExpiresAt time.Time `yaml:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+}
+
+// Completed returns true if the response of the request is recorded.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Expired returns true if the record has expired at the time.
+func (r *IdempotencyRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrScheduleNotCancellable is returned when cancelling a scheduled
+	// change which has nothing left to do or is being processed.
+	ErrScheduleNotCancellable = errors.New("scheduled change can't be cancelled")
+	// ErrScheduleLeaseLost is returned when completing a scheduled change
+	// whose lease has been taken over by another owner.
+	ErrScheduleLeaseLost = errors.New("scheduled change lease is lost")
+)
+
+// ScheduleStatus represents the status of a scheduled change.
+type ScheduleStatus string
+
+// These constants represent the possible status of a scheduled change.
+const (
+	// ScheduleStatusPending represents a change waiting for its effective time.
+	ScheduleStatusPending ScheduleStatus = "pending"
+
+	// ScheduleStatusApplied represents a change which has been applied, it's
+	// reverted at its expiry time if there is one.
+	ScheduleStatusApplied ScheduleStatus = "applied"
+
+	// ScheduleStatusReverted represents a change which has been reverted.
+	ScheduleStatusReverted ScheduleStatus = "reverted"
+
+	// ScheduleStatusCancelled represents a change which has been cancelled
+	// before being applied.
+	ScheduleStatusCancelled ScheduleStatus = "cancelled"
+
+	// ScheduleStatusFailed represents a change which failed to be applied
+	// or reverted.
+	ScheduleStatusFailed ScheduleStatus = "failed"
+)
+
+// ScheduledChange represents a change of a system config which goes live at
+// a given time and is optionally reverted at another time.
+type ScheduledChange struct {
+	// Unique ID of the scheduled change
+	ID uint `yaml:"id" json:"id"`
+	// ID of the system config to change
+	SystemConfigID uint `yaml:"systemConfigID" json:"systemConfigID"`
+	// Values applied to the system config, empty fields are left unchanged
+	Target *SystemConfig `yaml:"target" json:"target"`
+	// Snapshot of the system config taken when the change was applied,
+	// which is restored when the change expires
+	Previous *SystemConfig `yaml:"previous,omitempty" json:"previous,omitempty"`
+	// Time when the change goes live
+	EffectiveAt time.Time `yaml:"effectiveAt" json:"effectiveAt"`
+	// Time when the change is reverted, nil means the change is permanent
+	ExpiresAt *time.Time `yaml:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	// Status of the scheduled change
+	Status ScheduleStatus `yaml:"status" json:"status"`
+	// Reason of the last failure
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	// Username or ID of the user who submitted the change
+	Creator string `yaml:"creator,omitempty" json:"creator,omitempty"`
+	// Timestamp when the change was applied
+	AppliedAt *time.Time `yaml:"appliedAt,omitempty" json:"appliedAt,omitempty"`
+	// Timestamp when the change was reverted
+	RevertedAt *time.Time `yaml:"revertedAt,omitempty" json:"revertedAt,omitempty"`
+	// Timestamp when the change was submitted
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+	// Timestamp when the change was last updated
+	UpdatedAt time.Time `yaml:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// Validate checks if the scheduled change is valid.
+// It returns an error if the scheduled change is not valid.
+func (s *ScheduledChange) Validate() error {
+	if s.SystemConfigID == 0 {
+		return fmt.Errorf("scheduled change must specify a system config")
+	}
+
+	if s.Target == nil {
+		return fmt.Errorf("scheduled change must specify the target values")
+	}
+
+	if s.EffectiveAt.IsZero() {
+		return fmt.Errorf("scheduled change must specify the effective time")
+	}
+
+	if s.ExpiresAt != nil && !s.ExpiresAt.After(s.EffectiveAt) {
+		return fmt.Errorf("expiry time %s must be after effective time %s",
+			s.ExpiresAt.Format(time.RFC3339), s.EffectiveAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Due returns true if the change has to be applied or reverted at the
+// given time.
+func (s *ScheduledChange) Due(now time.Time) bool {
+	switch s.Status {
+	case ScheduleStatusPending:
+		return !s.EffectiveAt.After(now)
+	case ScheduleStatusApplied:
+		return s.ExpiresAt != nil && !s.ExpiresAt.After(now)
+	default:
+		return false
+	}
+}
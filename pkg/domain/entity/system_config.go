@@ -1,8 +1,27 @@
 package entity
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
+)
+
+var (
+	// ErrReferenceCycle is returned when the references between system
+	// configs form a cycle.
+	ErrReferenceCycle = errors.New("system config references form a cycle")
+	// ErrReferenceNotResolved is returned when a reference points to a
+	// system config or a path which doesn't exist.
+	ErrReferenceNotResolved = errors.New("system config reference can't be resolved")
+	// ErrConfigReferenced is returned when deleting a system config which
+	// is referenced by other system configs.
+	ErrConfigReferenced = errors.New("system config is referenced by other system configs")
+	// ErrConfigConflict is returned when a system config with the same
+	// tenant, env and type already exists.
+	ErrConfigConflict = errors.New("system config with the same tenant, env and type already exists")
 )
 
 // SystemConfig represents the configuration of a system.
@@ -19,6 +38,9 @@ type SystemConfig struct {
 	Config string `yaml:"config,omitempty" json:"config,omitempty"`
 	// Description or purpose of the system
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Arbitrary key/value labels used to organize and select the system
+	// config (e.g. team=payments, region=eu)
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
 	// Username or ID of the user who created the system
 	Creator string `yaml:"creator,omitempty" json:"creator,omitempty"`
 	// Username or ID of the user who last modified the system
@@ -36,13 +58,19 @@ func (s *SystemConfig) Validate() error {
 		return err
 	}
 
+	if err := labelutil.Validate(s.Labels); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// Env represents the environment.
+// Env represents the name of an environment.
 type Env string
 
-// These constants represent the possible environment.
+// These constants represent the built-in environments, they are seeded into
+// the environment table by the migration and more environments can be
+// managed at runtime.
 const (
 	// EnvPre represents the pre environment.
 	EnvPre Env = "pre"
@@ -63,25 +91,24 @@ const (
 	EnvStable Env = "stable"
 )
 
+// MaxEnvLength is the maximum length of an environment name.
+const MaxEnvLength = 50
+
+// envRegexp matches a valid environment name, which consists of lowercase
+// alphanumeric characters or '-', and starts and ends with an alphanumeric
+// character.
+var envRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
 // ParseEnv parses a string into a Env.
-// If the string is not a valid Env, it returns an error.
+// If the string is not a well-formed environment name, it returns an error.
+// Whether the environment is defined is checked against the environment
+// repository.
 func ParseEnv(str string) (Env, error) {
-	switch str {
-	case "pre":
-		return EnvPre, nil
-	case "gray":
-		return EnvGray, nil
-	case "prod":
-		return EnvProd, nil
-	case "dev":
-		return EnvDev, nil
-	case "test":
-		return EnvTest, nil
-	case "stable":
-		return EnvStable, nil
-	default:
+	if len(str) > MaxEnvLength || !envRegexp.MatchString(str) {
 		return Env(""), fmt.Errorf("invalid environment: %q", str)
 	}
+
+	return Env(str), nil
 }
 
 // MustParseEnv parses a string into a Env.
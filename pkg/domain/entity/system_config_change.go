@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// SystemConfigChange records that a system config has been created, updated
+// or deleted. The changes are numbered by an increasing sequence, so that
+// the replicas caching system configs can catch up with the changes made by
+// the other replicas.
+type SystemConfigChange struct {
+	// Sequence number of the change, increasing with every change
+	Seq uint64 `yaml:"seq" json:"seq"`
+	// ID of the changed system config
+	SystemConfigID uint `yaml:"systemConfigID" json:"systemConfigID"`
+	// Tenant of the changed system config
+	Tenant string `yaml:"tenant" json:"tenant"`
+	// Env of the changed system config
+	Env Env `yaml:"env" json:"env"`
+	// Type of the changed system config
+	Type string `yaml:"type" json:"type"`
+	// Timestamp when the change was made
+	ChangedAt time.Time `yaml:"changedAt,omitempty" json:"changedAt,omitempty"`
+}
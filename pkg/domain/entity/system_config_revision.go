@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// SystemConfigRevision represents a snapshot of a system config taken after
+// it has been changed.
+type SystemConfigRevision struct {
+	// Unique ID of the revision
+	ID uint `yaml:"id" json:"id"`
+	// ID of the system config
+	SystemConfigID uint `yaml:"systemConfigID" json:"systemConfigID"`
+	// Revision number, increasing from 1 for each system config
+	Revision int `yaml:"revision" json:"revision"`
+	// Snapshot of the system config
+	SystemConfig *SystemConfig `yaml:"systemConfig" json:"systemConfig"`
+	// Why the revision was made (e.g. schedule/12/apply)
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+	// Username or ID of the user who made the change
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty"`
+	// Timestamp when the revision was recorded
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+}
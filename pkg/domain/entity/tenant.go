@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrTenantNotFound is returned when a tenant is not defined.
+	ErrTenantNotFound = errors.New("tenant is not defined")
+	// ErrTenantSuspended is returned when writing to a suspended tenant.
+	ErrTenantSuspended = errors.New("tenant is suspended")
+	// ErrTenantInUse is returned when deleting a tenant which still owns
+	// system configs.
+	ErrTenantInUse = errors.New("tenant is in use")
+	// ErrConfigQuotaExceeded is returned when a tenant reaches its maximum
+	// number of system configs.
+	ErrConfigQuotaExceeded = errors.New("tenant config quota exceeded")
+	// ErrConfigSizeExceeded is returned when a system config is larger than
+	// the tenant allows.
+	ErrConfigSizeExceeded = errors.New("tenant config size quota exceeded")
+)
+
+// MaxTenantNameLength is the maximum length of a tenant name.
+const MaxTenantNameLength = 32
+
+// TenantStatus represents the status of a tenant.
+type TenantStatus string
+
+// These constants represent the possible tenant status.
+const (
+	// TenantStatusActive represents a tenant which can be read and written.
+	TenantStatusActive TenantStatus = "active"
+
+	// TenantStatusSuspended represents a read-only tenant.
+	TenantStatusSuspended TenantStatus = "suspended"
+)
+
+// ParseTenantStatus parses a string into a TenantStatus.
+// If the string is not a valid TenantStatus, it returns an error.
+func ParseTenantStatus(str string) (TenantStatus, error) {
+	switch str {
+	case "active":
+		return TenantStatusActive, nil
+	case "suspended":
+		return TenantStatusSuspended, nil
+	default:
+		return TenantStatus(""), fmt.Errorf("invalid tenant status: %q", str)
+	}
+}
+
+// TenantQuota represents the resource limits of a tenant, a zero value
+// means unlimited.
+type TenantQuota struct {
+	// Maximum number of system configs owned by the tenant
+	MaxConfigs int `yaml:"maxConfigs" json:"maxConfigs"`
+	// Maximum size in bytes of the content of a single system config
+	MaxConfigSize int `yaml:"maxConfigSize" json:"maxConfigSize"`
+	// Maximum number of revisions retained for a single system config
+	MaxRevisions int `yaml:"maxRevisions" json:"maxRevisions"`
+}
+
+// Tenant represents a tenant or organization which owns system configs.
+type Tenant struct {
+	// Unique ID of the tenant
+	ID uint `yaml:"id" json:"id"`
+	// Name of the tenant referenced by system configs, which is immutable
+	Name string `yaml:"name" json:"name"`
+	// Human readable name of the tenant
+	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	// Usernames or IDs of the users who own the tenant
+	Owners []string `yaml:"owners,omitempty" json:"owners,omitempty"`
+	// Status of the tenant, suspended tenants are read-only
+	Status TenantStatus `yaml:"status" json:"status"`
+	// Resource limits of the tenant
+	Quota TenantQuota `yaml:"quota" json:"quota"`
+	// Username or ID of the user who created the tenant
+	Creator string `yaml:"creator,omitempty" json:"creator,omitempty"`
+	// Username or ID of the user who last modified the tenant
+	Modifier string `yaml:"modifier,omitempty" json:"modifier,omitempty"`
+	// Timestamp when the tenant was created
+	CreatedAt time.Time `yaml:"createdAt,omitempty" json:"createdAt,omitempty"`
+	// Timestamp when the tenant was last updated
+	UpdatedAt time.Time `yaml:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// Validate checks if the tenant is valid.
+// It returns an error if the tenant is not valid.
+func (t *Tenant) Validate() error {
+	if len(t.Name) == 0 || len(t.Name) > MaxTenantNameLength {
+		return fmt.Errorf("tenant name must be 1 to %d characters", MaxTenantNameLength)
+	}
+
+	if len(t.Owners) == 0 {
+		return fmt.Errorf("tenant %q must have at least one owner", t.Name)
+	}
+
+	if _, err := ParseTenantStatus(string(t.Status)); err != nil {
+		return err
+	}
+
+	if t.Quota.MaxConfigs < 0 || t.Quota.MaxConfigSize < 0 || t.Quota.MaxRevisions < 0 {
+		return fmt.Errorf("tenant %q quotas must not be negative", t.Name)
+	}
+
+	return nil
+}
+
+// CheckWritable returns ErrTenantSuspended if the tenant is read-only.
+func (t *Tenant) CheckWritable() error {
+	if t.Status == TenantStatusSuspended {
+		return fmt.Errorf("%w: %q is read-only", ErrTenantSuspended, t.Name)
+	}
+
+	return nil
+}
+
+// HasOwner returns true if the user is an owner of the tenant.
+func (t *Tenant) HasOwner(user string) bool {
+	for _, owner := range t.Owners {
+		if owner == user {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckConfigQuota returns ErrConfigQuotaExceeded if the tenant already
+// owns the maximum number of system configs.
+func (t *Tenant) CheckConfigQuota(total int) error {
+	if t.Quota.MaxConfigs > 0 && total >= t.Quota.MaxConfigs {
+		return fmt.Errorf("%w: %q allows at most %d configs", ErrConfigQuotaExceeded, t.Name, t.Quota.MaxConfigs)
+	}
+
+	return nil
+}
+
+// CheckConfigSize returns ErrConfigSizeExceeded if the content of the
+// system config is larger than the tenant allows.
+func (t *Tenant) CheckConfigSize(s *SystemConfig) error {
+	if t.Quota.MaxConfigSize > 0 && len(s.Config) > t.Quota.MaxConfigSize {
+		return fmt.Errorf("%w: config is %d bytes, %q allows at most %d bytes",
+			ErrConfigSizeExceeded, len(s.Config), t.Name, t.Quota.MaxConfigSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// EnvironmentRepository is an interface that defines the repository
+// operations for environments.
+// It follows the principles of domain-driven design (DDD).
+type EnvironmentRepository interface {
+	// Create creates a new environment.
+	Create(ctx context.Context, environment *entity.Environment) error
+	// Delete deletes an environment by its ID.
+	Delete(ctx context.Context, id uint) error
+	// Update updates an existing environment.
+	Update(ctx context.Context, environment *entity.Environment) error
+	// Get retrieves an environment by its ID.
+	Get(ctx context.Context, id uint) (*entity.Environment, error)
+	// GetByName retrieves an environment by its name.
+	GetByName(ctx context.Context, name entity.Env) (*entity.Environment, error)
+	// List returns all environments ordered by their order.
+	List(ctx context.Context) ([]*entity.Environment, error)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// FreezeWindowRepository is an interface that defines the repository
+// operations for freeze windows.
+// It follows the principles of domain-driven design (DDD).
+type FreezeWindowRepository interface {
+	// Create creates a new freeze window.
+	Create(ctx context.Context, window *entity.FreezeWindow) error
+	// Delete deletes a freeze window by its ID.
+	Delete(ctx context.Context, id uint) error
+	// Update updates an existing freeze window.
+	Update(ctx context.Context, window *entity.FreezeWindow) error
+	// Get retrieves a freeze window by its ID.
+	Get(ctx context.Context, id uint) (*entity.FreezeWindow, error)
+	// Find returns a list of freeze windows, latest start first.
+	Find(ctx context.Context, query Query) ([]*entity.FreezeWindow, error)
+	// FindActive returns the freeze windows covering the given scope at
+	// the given time.
+	FindActive(ctx context.Context, tenant string, env entity.Env, typ string, at time.Time) ([]*entity.FreezeWindow, error)
+	// FindOverrides returns the overrides recorded for a freeze window,
+	// latest first.
+	FindOverrides(ctx context.Context, windowID uint, query Query) ([]*entity.FreezeOverride, error)
+}
+
+// freezeOverrideKey is the context key of the freeze override.
+type freezeOverrideKey struct{}
+
+// WithFreezeOverride returns a context which allows the system config
+// repository to write frozen system configs. The overrides are recorded
+// with the reason and the operator of the given override.
+//
+// Example:
+//
+//	ctx = repository.WithFreezeOverride(ctx, &entity.FreezeOverride{Reason: "hotfix", Operator: "alice"})
+func WithFreezeOverride(ctx context.Context, override *entity.FreezeOverride) context.Context {
+	return context.WithValue(ctx, freezeOverrideKey{}, override)
+}
+
+// FreezeOverrideFrom returns the freeze override of the context, or nil if
+// there is none.
+func FreezeOverrideFrom(ctx context.Context) *entity.FreezeOverride {
+	override, _ := ctx.Value(freezeOverrideKey{}).(*entity.FreezeOverride)
+	return override
+}
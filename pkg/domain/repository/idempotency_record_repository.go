@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// IdempotencyRecordRepository is an interface that defines the repository
+// operations for the responses of the requests sent with an idempotency key.
+// It follows the principles of domain-driven design (DDD).
+type IdempotencyRecordRepository interface {
+	// Create records a request in progress, it returns
+	// entity.ErrIdempotencyKeyInUse if the key of the principal is recorded.
+	Create(ctx context.Context, record *entity.IdempotencyRecord) error
+	// Get retrieves the record of the key of the principal.
+	Get(ctx context.Context, principal, key string) (*entity.IdempotencyRecord, error)
+	// Replace records a request in progress in place of the record of its
+	// key expired at the time, it returns entity.ErrIdempotencyKeyInUse if
+	// the record isn't expired, e.g. another request has just replaced it.
+	Replace(ctx context.Context, record *entity.IdempotencyRecord, now time.Time) error
+	// Extend moves the expiration of the request in progress of the key of
+	// the principal to the time, it returns gorm.ErrRecordNotFound if no
+	// request of the key is in progress.
+	Extend(ctx context.Context, principal, key string, expiresAt time.Time) error
+	// Complete records the response of the request in progress, and moves
+	// its expiration from the lease of the request to the one of the
+	// response.
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+	// Delete removes the record of the key of the principal.
+	Delete(ctx context.Context, principal, key string) error
+	// DeleteExpired removes the records expired at the time and returns the
+	// number of removed records.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import "context"
+
+// Repository is an interface that defines the CRUD operations shared by the
+// resources, E is the entity of the resource. The resources with more
+// operations define their own interfaces instead.
+// It follows the principles of domain-driven design (DDD).
+type Repository[E any] interface {
+	// Create creates a new entity.
+	Create(ctx context.Context, entity *E) error
+	// Delete deletes an entity by its ID.
+	Delete(ctx context.Context, id uint) error
+	// Update updates an existing entity, the zero fields are left unchanged.
+	Update(ctx context.Context, entity *E) error
+	// Get retrieves an entity by its ID.
+	Get(ctx context.Context, id uint) (*E, error)
+	// Find returns the entities matching the query.
+	Find(ctx context.Context, query Query) ([]*E, error)
+	// Count returns the total of entities matching the query.
+	Count(ctx context.Context, query Query) (int, error)
+}
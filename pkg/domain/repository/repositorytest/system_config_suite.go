@@ -0,0 +1,239 @@
+// Package repositorytest provides the conformance tests of the repository
+// interfaces, so that every implementation is tested for the same behavior.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// SuiteTenant is the tenant of the system configs created by the suites,
+// it must be defined and writable without quotas.
+const SuiteTenant = "MAIN_SITE"
+
+// SystemConfigFactory returns an empty repository for a test. The prod and
+// gray environments and the SuiteTenant must be defined in it.
+type SystemConfigFactory func(t *testing.T) repository.SystemConfigRepository
+
+// RunSystemConfigSuite tests the behavior of the repository.SystemConfigRepository
+// implementation created by the factory: CRUD, not found errors, unique
+// keys, soft delete, pagination and filtering.
+//
+// Example:
+//
+//	func TestSystemConfigSuite(t *testing.T) {
+//		repositorytest.RunSystemConfigSuite(t, func(t *testing.T) repository.SystemConfigRepository {
+//			return memory.NewSystemConfigRepository()
+//		})
+//	}
+func RunSystemConfigSuite(t *testing.T, factory SystemConfigFactory) {
+	ctx := context.Background()
+
+	t.Run("Create and get", func(t *testing.T) {
+		repo := factory(t)
+		config := newSystemConfig(entity.EnvProd, "cache", `{"db": 3}`)
+		require.NoError(t, repo.Create(ctx, config))
+		require.NotZero(t, config.ID)
+		require.False(t, config.CreatedAt.IsZero())
+
+		actual, err := repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, config.ID, actual.ID)
+		require.Equal(t, SuiteTenant, actual.Tenant)
+		require.Equal(t, entity.EnvProd, actual.Env)
+		require.Equal(t, "cache", actual.Type)
+		require.Equal(t, `{"db": 3}`, actual.Config)
+		require.Equal(t, "redis cache", actual.Description)
+		require.Equal(t, map[string]string{"team": "payments"}, actual.Labels)
+		require.Equal(t, "alice", actual.Creator)
+
+		actual, err = repo.GetByKey(ctx, SuiteTenant, entity.EnvProd, "cache")
+		require.NoError(t, err)
+		require.Equal(t, config.ID, actual.ID)
+	})
+
+	t.Run("Create invalid", func(t *testing.T) {
+		repo := factory(t)
+		config := newSystemConfig("", "cache", "{}")
+		require.Error(t, repo.Create(ctx, config))
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := factory(t)
+		_, err := repo.Get(ctx, 1000)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.GetByKey(ctx, SuiteTenant, entity.EnvProd, "missing")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		err = repo.Update(ctx, &entity.SystemConfig{ID: 1000, Env: entity.EnvProd, Config: "{}"})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		err = repo.Delete(ctx, 1000)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Unique key", func(t *testing.T) {
+		repo := factory(t)
+		require.NoError(t, repo.Create(ctx, newSystemConfig(entity.EnvProd, "cache", "{}")))
+		err := repo.Create(ctx, newSystemConfig(entity.EnvProd, "cache", "{}"))
+		require.ErrorIs(t, err, entity.ErrConfigConflict)
+
+		// The same type in another env is another key
+		gray := newSystemConfig(entity.EnvGray, "cache", "{}")
+		require.NoError(t, repo.Create(ctx, gray))
+		gray.Env = entity.EnvProd
+		err = repo.Update(ctx, gray)
+		require.ErrorIs(t, err, entity.ErrConfigConflict)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := factory(t)
+		config := newSystemConfig(entity.EnvProd, "cache", "{}")
+		require.NoError(t, repo.Create(ctx, config))
+
+		config.Config = `{"db": 4}`
+		config.Description = "redis cache of payments"
+		config.Modifier = "bob"
+		config.Labels = map[string]string{"team": "search", "region": "eu"}
+		require.NoError(t, repo.Update(ctx, config))
+		actual, err := repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, `{"db": 4}`, actual.Config)
+		require.Equal(t, "redis cache of payments", actual.Description)
+		require.Equal(t, "bob", actual.Modifier)
+		require.Equal(t, map[string]string{"team": "search", "region": "eu"}, actual.Labels)
+
+		// Nil labels are left unchanged
+		config.Labels = nil
+		config.Config = `{"db": 5}`
+		require.NoError(t, repo.Update(ctx, config))
+		actual, err = repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, `{"db": 5}`, actual.Config)
+		require.Equal(t, map[string]string{"team": "search", "region": "eu"}, actual.Labels)
+	})
+
+	t.Run("Soft delete", func(t *testing.T) {
+		repo := factory(t)
+		config := newSystemConfig(entity.EnvProd, "cache", "{}")
+		require.NoError(t, repo.Create(ctx, config))
+		require.NoError(t, repo.Delete(ctx, config.ID))
+
+		_, err := repo.Get(ctx, config.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.GetByKey(ctx, SuiteTenant, entity.EnvProd, "cache")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.ErrorIs(t, repo.Delete(ctx, config.ID), gorm.ErrRecordNotFound)
+		total, err := repo.Count(ctx, repository.Query{})
+		require.NoError(t, err)
+		require.Zero(t, total)
+
+		// The key of a deleted system config can be used again
+		recreated := newSystemConfig(entity.EnvProd, "cache", "{}")
+		require.NoError(t, repo.Create(ctx, recreated))
+		require.NotEqual(t, config.ID, recreated.ID)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		repo := factory(t)
+		var ids []uint
+		for i := 0; i < 5; i++ {
+			config := newSystemConfig(entity.EnvProd, fmt.Sprintf("type-%d", i), "{}")
+			require.NoError(t, repo.Create(ctx, config))
+			ids = append(ids, config.ID)
+		}
+
+		for _, tc := range []struct {
+			name          string
+			offset, limit int
+			expected      []uint
+		}{
+			{name: "first page", offset: 0, limit: 2, expected: ids[:2]},
+			{name: "last page", offset: 4, limit: 2, expected: ids[4:]},
+			{name: "beyond the last page", offset: 10, limit: 2, expected: nil},
+			{name: "zero limit", offset: 0, limit: 0, expected: nil},
+			{name: "no limit", offset: 1, limit: -1, expected: ids[1:]},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				actual, err := repo.Find(ctx, repository.Query{Offset: tc.offset, Limit: tc.limit})
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, configIDs(actual))
+			})
+		}
+
+		// Count ignores the offset and limit
+		total, err := repo.Count(ctx, repository.Query{Offset: 4, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, 5, total)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		repo := factory(t)
+		redis := newSystemConfig(entity.EnvProd, "redis", `{"nodes": ["10.0.0.1:6379"]}`)
+		require.NoError(t, repo.Create(ctx, redis))
+		kafka := newSystemConfig(entity.EnvProd, "kafka", `{"brokers": ["10.0.0.2:9092"]}`)
+		kafka.Labels = map[string]string{"team": "search"}
+		require.NoError(t, repo.Create(ctx, kafka))
+
+		actual, err := repo.Find(ctx, repository.Query{Limit: 10, Keyword: "6379"})
+		require.NoError(t, err)
+		require.Equal(t, []uint{redis.ID}, configIDs(actual))
+		total, err := repo.Count(ctx, repository.Query{Keyword: "10.0.0."})
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+
+		actual, err = repo.Find(ctx, repository.Query{Limit: 10, LabelSelector: labelutil.MustParse("team=search")})
+		require.NoError(t, err)
+		require.Equal(t, []uint{kafka.ID}, configIDs(actual))
+		total, err = repo.Count(ctx, repository.Query{LabelSelector: labelutil.MustParse("team!=search")})
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+	})
+
+	t.Run("Find dependents", func(t *testing.T) {
+		repo := factory(t)
+		redis := newSystemConfig(entity.EnvProd, "redis", `{"db": 3}`)
+		require.NoError(t, repo.Create(ctx, redis))
+		app := newSystemConfig(entity.EnvProd, "app", "db: ${ref:MAIN_SITE/prod/redis#/db}")
+		require.NoError(t, repo.Create(ctx, app))
+		gray := newSystemConfig(entity.EnvGray, "app", "db: ${ref:MAIN_SITE/gray/redis#/db}")
+		require.NoError(t, repo.Create(ctx, gray))
+
+		actual, err := repo.FindDependents(ctx, SuiteTenant, entity.EnvProd, "redis")
+		require.NoError(t, err)
+		require.Equal(t, []uint{app.ID}, configIDs(actual))
+
+		// A deleted system config no longer depends on others
+		require.NoError(t, repo.Delete(ctx, app.ID))
+		actual, err = repo.FindDependents(ctx, SuiteTenant, entity.EnvProd, "redis")
+		require.NoError(t, err)
+		require.Empty(t, actual)
+	})
+}
+
+// newSystemConfig returns a system config of the SuiteTenant.
+func newSystemConfig(env entity.Env, typ, content string) *entity.SystemConfig {
+	return &entity.SystemConfig{
+		Tenant:      SuiteTenant,
+		Env:         env,
+		Type:        typ,
+		Config:      content,
+		Description: "redis cache",
+		Labels:      map[string]string{"team": "payments"},
+		Creator:     "alice",
+	}
+}
+
+// configIDs returns the IDs of the system configs in order.
+func configIDs(configs []*entity.SystemConfig) []uint {
+	var ids []uint
+	for _, config := range configs {
+		ids = append(ids, config.ID)
+	}
+	return ids
+}
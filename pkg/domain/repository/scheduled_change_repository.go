@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// ScheduledChangeRepository is an interface that defines the repository
+// operations for scheduled changes of system configs.
+// It follows the principles of domain-driven design (DDD).
+type ScheduledChangeRepository interface {
+	// Create creates a new scheduled change.
+	Create(ctx context.Context, change *entity.ScheduledChange) error
+	// Get retrieves a scheduled change by its ID.
+	Get(ctx context.Context, id uint) (*entity.ScheduledChange, error)
+	// FindPending returns the scheduled changes which are waiting to be
+	// applied or reverted, ordered by their effective time.
+	FindPending(ctx context.Context, query Query) ([]*entity.ScheduledChange, error)
+	// FindDue returns at most limit unclaimed scheduled changes which have
+	// to be applied or reverted at the given time.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledChange, error)
+	// Claim leases the scheduled change to the owner until the given time
+	// if its status is unchanged and it isn't leased by another owner at
+	// now. It returns false if the change couldn't be claimed.
+	Claim(ctx context.Context, change *entity.ScheduledChange, owner string, now, until time.Time) (bool, error)
+	// Complete saves the result of processing a claimed scheduled change
+	// and releases the lease held by the owner. It returns
+	// entity.ErrScheduleLeaseLost if the owner doesn't hold the lease
+	// anymore.
+	Complete(ctx context.Context, change *entity.ScheduledChange, owner string) error
+	// Cancel cancels a scheduled change which is waiting to be applied,
+	// an applied change is reverted by the scheduler instead.
+	Cancel(ctx context.Context, id uint) error
+}
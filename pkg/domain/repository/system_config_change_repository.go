@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// SystemConfigChangeRepository is an interface that defines the repository
+// operations for the change sequence of system configs. The changes are
+// recorded by the SystemConfigRepository along with the changes themselves.
+// It follows the principles of domain-driven design (DDD).
+type SystemConfigChangeRepository interface {
+	// FindSince returns at most limit changes after the sequence number,
+	// oldest first.
+	FindSince(ctx context.Context, seq uint64, limit int) ([]*entity.SystemConfigChange, error)
+	// LatestSeq returns the sequence number of the latest change, or zero if
+	// there is no change.
+	LatestSeq(ctx context.Context) (uint64, error)
+	// DeleteBefore removes the changes made before the time and returns the
+	// number of removed changes.
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
@@ -18,8 +18,14 @@ type SystemConfigRepository interface {
 	Update(ctx context.Context, systemConfig *entity.SystemConfig) error
 	// Get retrieves a system config by its ID.
 	Get(ctx context.Context, id uint) (*entity.SystemConfig, error)
+	// GetByKey retrieves a system config by its tenant, env and type.
+	GetByKey(ctx context.Context, tenant string, env entity.Env, typ string) (*entity.SystemConfig, error)
 	// Find returns a list of specified system config.
 	Find(ctx context.Context, query Query) ([]*entity.SystemConfig, error)
-	// Count returns the total of system configs.
-	Count(ctx context.Context) (int, error)
+	// Count returns the total of system configs matching the query,
+	// the offset and limit of the query are ignored.
+	Count(ctx context.Context, query Query) (int, error)
+	// FindDependents returns the system configs whose content references
+	// the system config of the given tenant, env and type.
+	FindDependents(ctx context.Context, tenant string, env entity.Env, typ string) ([]*entity.SystemConfig, error)
 }
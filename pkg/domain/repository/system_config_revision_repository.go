@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// SystemConfigRevisionRepository is an interface that defines the repository
+// operations for revisions of system configs.
+// It follows the principles of domain-driven design (DDD).
+type SystemConfigRevisionRepository interface {
+	// Create records a new revision of a system config, the revision number
+	// is assigned by the repository.
+	Create(ctx context.Context, revision *entity.SystemConfigRevision) error
+	// Find returns the revisions of a system config, latest first.
+	Find(ctx context.Context, systemConfigID uint, query Query) ([]*entity.SystemConfigRevision, error)
+	// Prune removes the oldest revisions of a system config so that at most
+	// retain revisions are kept. A zero retain keeps all revisions.
+	Prune(ctx context.Context, systemConfigID uint, retain int) error
+}
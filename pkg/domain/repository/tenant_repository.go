@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// TenantRepository is an interface that defines the repository
+// operations for tenants.
+// It follows the principles of domain-driven design (DDD).
+type TenantRepository interface {
+	// Create creates a new tenant.
+	Create(ctx context.Context, tenant *entity.Tenant) error
+	// Delete deletes a tenant by its ID.
+	Delete(ctx context.Context, id uint) error
+	// Update updates an existing tenant.
+	Update(ctx context.Context, tenant *entity.Tenant) error
+	// Get retrieves a tenant by its ID.
+	Get(ctx context.Context, id uint) (*entity.Tenant, error)
+	// GetByName retrieves a tenant by its name.
+	GetByName(ctx context.Context, name string) (*entity.Tenant, error)
+	// Find returns a list of specified tenants.
+	Find(ctx context.Context, query Query) ([]*entity.Tenant, error)
+	// Count returns the total of tenants matching the query.
+	Count(ctx context.Context, query Query) (int, error)
+}
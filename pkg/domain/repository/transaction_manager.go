@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+// TransactionManager runs a unit of work in a transaction spanning the
+// repositories. The repositories called with the context given to the
+// function take part in the transaction.
+// It follows the principles of domain-driven design (DDD).
+type TransactionManager interface {
+	// Transaction runs the function in a transaction, which is committed if
+	// the function returns nil and rolled back otherwise. A transaction
+	// started within another one is nested in it, rolling it back leaves the
+	// outer transaction intact. The function may be run again if the
+	// transaction failed on a deadlock or a serialization failure, so it must
+	// not have side effects outside the repositories.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,5 +1,11 @@
 package repository
 
+import (
+	"context"
+
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
+)
+
 // Query represents the query criteria for a database access.
 type Query struct {
 	// Offset is the number of items to skip.
@@ -8,4 +14,27 @@ type Query struct {
 	Limit int
 	// Keyword is the keyword to search for.
 	Keyword string
+	// LabelSelector restricts the result to items whose labels match it.
+	LabelSelector labelutil.Selector
+}
+
+// readPrimaryKey is the context key of reading from the primary database.
+type readPrimaryKey struct{}
+
+// WithReadPrimary returns a context whose read-only queries go to the
+// primary database instead of the read replicas, so that the caller reads
+// its own writes despite the replication lag.
+//
+// Example:
+//
+//	ctx = repository.WithReadPrimary(ctx)
+func WithReadPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPrimaryKey{}, true)
+}
+
+// ReadPrimaryFrom returns true if the read-only queries of the context must
+// go to the primary database.
+func ReadPrimaryFrom(ctx context.Context) bool {
+	primary, _ := ctx.Value(readPrimaryKey{}).(bool)
+	return primary
 }
@@ -1,14 +1,18 @@
 package errcode
 
 import (
-	"net/http"
 	"strings"
+	"time"
 
 	"github.com/elliotxx/errors"
 )
 
-func NewErrorCode(code string, message string) errors.ErrorCode {
-	mustSetCodeIfNotPresent(code)
+// NewErrorCode registers the error code with its metadata, the message is
+// used when the code has no message in the locales of the catalog. It
+// panics if the code is registered twice or lacks a message in any locale.
+func NewErrorCode(code string, message string, opts ...Option) errors.ErrorCode {
+	mustSetCodeIfNotPresent(code, opts...)
+	mustHaveMessages(code)
 	return errors.NewErrorCode(code, message)
 }
 
@@ -31,22 +35,28 @@ func InvalidErrorScope(code string) bool {
 	return c == InvalidScope || c == ""
 }
 
-// StatusCode returns the http status code of the error
+// code returns the error code of the error
+func code(err error) string {
+	switch e := err.(type) {
+	case errors.DetailError:
+		return e.GetCode()
+	case errors.ErrorCode:
+		return e.GetCode()
+	}
+	return ""
+}
+
+// StatusCode returns the http status code of the error, by the registry of
+// the error codes, or else by the scope of the error
 func StatusCode(err error) int {
-	switch Scope(err) {
-	case Scope(Success):
-		return http.StatusOK
-	case Scope(NotFound):
-		return http.StatusNotFound
-	case Scope(ServerError), Scope(InternalError):
-		return http.StatusInternalServerError
-	case Scope(InvalidParams):
-		return http.StatusBadRequest
-	case Scope(AccessPermissionError):
-		return http.StatusUnauthorized
-	case Scope(TooManyRequests):
-		return http.StatusTooManyRequests
-	default:
-		return http.StatusInternalServerError
+	if m, ok := codes[code(err)]; ok {
+		return m.Status
 	}
+	return scopeStatus(Scope(err))
+}
+
+// RetryAfter returns the delay before sending the request failing with the
+// error again, it's 0 if the error code has none
+func RetryAfter(err error) time.Duration {
+	return time.Duration(codes[code(err)].RetryAfter) * time.Second
 }
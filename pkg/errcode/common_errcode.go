@@ -1,10 +1,17 @@
 package errcode
 
+import (
+	"net/http"
+	"time"
+)
+
 var (
 	Success                    = NewErrorCode("00000", "成功")
 	ClientError                = NewErrorCode("A0001", "用户端错误")
 	NotFound                   = NewErrorCode("A0100", "不存在")
 	AccessPermissionError      = NewErrorCode("A0200", "访问权限异常")
+	TenantSuspended            = NewErrorCode("A0201", "租户已停用，仅允许读操作", WithStatus(http.StatusForbidden))
+	ConfigFrozen               = NewErrorCode("A0202", "配置处于冻结窗口，禁止写操作", WithStatus(http.StatusForbidden))
 	AbnormalUserOperation      = NewErrorCode("A0300", "用户操作异常")
 	InvalidParams              = NewErrorCode("A0400", "无效的用户输入")
 	BlankRequiredParams        = NewErrorCode("A0401", "请求必填参数为空")
@@ -12,11 +19,14 @@ var (
 	MalformedParams            = NewErrorCode("A0403", "参数格式不匹配")
 	ErrDeserializedParams      = NewErrorCode("A0404", "请求参数反序列化失败")
 	SensitiveWordsParams       = NewErrorCode("A0405", "请求参数包含违禁敏感词")
+	TenantConfigQuotaExceeded  = NewErrorCode("A0406", "租户配置数量超出配额")
+	TenantConfigSizeExceeded   = NewErrorCode("A0407", "配置内容大小超出租户配额")
+	ConfigConflict             = NewErrorCode("A0408", "相同租户、环境和类型的配置已存在", WithStatus(http.StatusConflict))
 	ServerError                = NewErrorCode("A0500", "用户请求服务异常")
-	TooManyRequests            = NewErrorCode("A0501", "请求次数超出限制")
-	ConcurrentExceedLimit      = NewErrorCode("A0502", "请求并发数超出限制")
-	WaitUserOperation          = NewErrorCode("A0503", "用户操作请等待")
-	RepeatedRequest            = NewErrorCode("A0504", "用户重复请求")
+	TooManyRequests            = NewErrorCode("A0501", "请求次数超出限制", WithStatus(http.StatusTooManyRequests), WithRetryAfter(time.Second))
+	ConcurrentExceedLimit      = NewErrorCode("A0502", "请求并发数超出限制", WithStatus(http.StatusTooManyRequests), WithRetryAfter(time.Second))
+	WaitUserOperation          = NewErrorCode("A0503", "用户操作请等待", WithStatus(http.StatusTooManyRequests), WithRetryAfter(time.Second))
+	RepeatedRequest            = NewErrorCode("A0504", "用户重复请求", WithStatus(http.StatusConflict))
 	AbnormalUserResources      = NewErrorCode("A0600", "用户资源异常")
 	AbnormalUserVersion        = NewErrorCode("A0700", "用户当前版本异常")
 	MismatchUserVersion        = NewErrorCode("A0701", "用户安装版本与系统不匹配")
@@ -28,14 +38,14 @@ var (
 	TooHighAPIVersion          = NewErrorCode("A0707", "用户API请求版本过高")
 	InternalError              = NewErrorCode("B0001", "系统执行出错")
 	InvalidStartupParams       = NewErrorCode("B0002", "系统启动参数错误")
-	SystemTimeout              = NewErrorCode("B0100", "系统执行超时")
+	SystemTimeout              = NewErrorCode("B0100", "系统执行超时", Retryable())
 	SystemResourceError        = NewErrorCode("B0200", "系统资源异常")
 	ReadDiskFailed             = NewErrorCode("B0201", "系统读取磁盘文件失败")
 	ThirdPartyServiceError     = NewErrorCode("C0001", "调用第三方服务出错")
 	MiddlewareServiceError     = NewErrorCode("C0100", "中间件服务出错")
-	MiddlewareServiceTimeout   = NewErrorCode("C0101", "中间件执行超时")
+	MiddlewareServiceTimeout   = NewErrorCode("C0101", "中间件执行超时", Retryable())
 	DatabaseServiceError       = NewErrorCode("C0200", "数据库服务出错")
-	DatabaseServiceTimeout     = NewErrorCode("C0201", "数据库服务超时")
+	DatabaseServiceTimeout     = NewErrorCode("C0201", "数据库服务超时", Retryable())
 	NotificationServiceError   = NewErrorCode("C0300", "通知服务出错")
-	NotificationServiceTimeout = NewErrorCode("C0301", "通知服务超时")
+	NotificationServiceTimeout = NewErrorCode("C0301", "通知服务超时", Retryable())
 )
@@ -0,0 +1,103 @@
+package errcode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteMarkdown writes the table of the registered error codes in Markdown,
+// every code is anchored by its lowercase code, e.g. #a0400, which is the
+// fragment of the type URIs of the problem details.
+func WriteMarkdown(w io.Writer) error {
+	locales := Locales()
+
+	var b strings.Builder
+	b.WriteString("# Error codes\n\n")
+	b.WriteString("<!-- Code generated by \"app errcode docs\". DO NOT EDIT. -->\n\n")
+	b.WriteString("The failed responses carry one of these error codes in `code`. The first character is the source of\n")
+	b.WriteString("the error: `A` the client, `B` the server and `C` a third party service. The requests failing with a\n")
+	b.WriteString("retryable code may succeed when sent again, after the seconds in the `Retry-After` header if any.\n")
+	b.WriteString("The codes are also served by `GET /api/v1/errcodes`.\n\n")
+
+	b.WriteString("| Code | Status | Retryable | Retry-After |")
+	for _, locale := range locales {
+		fmt.Fprintf(&b, " %s |", locale)
+	}
+	b.WriteString("\n| --- | --- | --- | --- |")
+	for range locales {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+
+	for _, m := range All() {
+		retryAfter := ""
+		if m.RetryAfter > 0 {
+			retryAfter = fmt.Sprintf("%ds", m.RetryAfter)
+		}
+		fmt.Fprintf(&b, "| <a id=\"%s\"></a>`%s` | %d | %t | %s |", strings.ToLower(m.Code), m.Code, m.Status, m.Retryable, retryAfter)
+		for _, locale := range locales {
+			fmt.Fprintf(&b, " %s |", strings.ReplaceAll(m.Messages[locale], "|", "\\|"))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteOpenAPI writes the OpenAPI components of the registered error codes
+// in YAML: the ErrorCode schema enumerating the codes, and the ErrorCodeMeta
+// schema of the items of GET /api/v1/errcodes. The metadata of every code is
+// listed in the x-error-codes extension of ErrorCode.
+func WriteOpenAPI(w io.Writer) error {
+	all := All()
+	enum := make([]string, len(all))
+	descriptions := make([]string, len(all))
+	for i, m := range all {
+		enum[i] = m.Code
+		descriptions[i] = m.Messages["en-US"]
+	}
+
+	components := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"ErrorCode": map[string]any{
+					"type":                "string",
+					"description":         "The error code of a failed response, see docs/errcode.md",
+					"enum":                enum,
+					"x-enum-descriptions": descriptions,
+					"x-error-codes":       all,
+				},
+				"ErrorCodeMeta": map[string]any{
+					"type":     "object",
+					"required": []string{"code", "scope", "status", "messages", "retryable"},
+					"properties": map[string]any{
+						"code":   map[string]any{"$ref": "#/components/schemas/ErrorCode"},
+						"scope":  map[string]any{"type": "string", "example": "A04"},
+						"status": map[string]any{"type": "integer", "example": 400},
+						"messages": map[string]any{
+							"type":                 "object",
+							"additionalProperties": map[string]any{"type": "string"},
+							"example":              map[string]string{"en-US": "Invalid user input"},
+						},
+						"retryable":  map[string]any{"type": "boolean"},
+						"retryAfter": map[string]any{"type": "integer", "description": "The seconds to wait before retrying"},
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := io.WriteString(w, "# Code generated by \"app errcode docs\". DO NOT EDIT.\n"); err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(components); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,262 @@
+package errcode
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the locale of the messages for the requests accepting
+// none of the supported locales, unless changed by SetDefaultLocale.
+const DefaultLocale = "zh-CN"
+
+// localeFS holds the messages of the error codes in <locale>.yaml files,
+// e.g. en-US.yaml, which map the codes to their messages.
+//
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+// coder is an error with an error code, i.e. errors.ErrorCode or
+// errors.DetailError.
+type coder interface {
+	GetCode() string
+	GetMsg() string
+}
+
+// catalog keeps the messages of the error codes by locale.
+type catalog struct {
+	mu sync.RWMutex
+	// messages maps the locales to the messages of the codes
+	messages map[string]map[string]string
+	// locales are the supported locales, the default one first
+	locales []string
+	matcher language.Matcher
+}
+
+var messages = mustNewCatalog()
+
+// mustNewCatalog returns the catalog of the embedded messages, it panics if
+// they can't be loaded.
+func mustNewCatalog() *catalog {
+	m, err := loadMessages(localeFS, "locales")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load the embedded messages of the error codes: %v", err))
+	}
+	c := &catalog{}
+	if err := c.set(m, DefaultLocale); err != nil {
+		panic(err.Error())
+	}
+	return c
+}
+
+// set replaces the messages and the default locale of the catalog.
+func (c *catalog) set(m map[string]map[string]string, defaultLocale string) error {
+	if _, ok := m[defaultLocale]; !ok {
+		return fmt.Errorf("the default locale %s has no messages", defaultLocale)
+	}
+
+	// The matcher falls back to the first locale
+	locales := []string{defaultLocale}
+	for locale := range m {
+		if locale != defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+	tags := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		tags[i] = language.MustParse(locale)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = m
+	c.locales = locales
+	c.matcher = language.NewMatcher(tags)
+	return nil
+}
+
+// message returns the message of the code in the locale preferred by the
+// Accept-Language header, falling back to the closest supported locale and
+// then to the default locale.
+func (c *catalog) message(code, acceptLanguage string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// An invalid header still yields the tags parsed before the error
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		index = 0
+	}
+	if msg, ok := c.messages[c.locales[index]][code]; ok {
+		return msg, true
+	}
+	msg, ok := c.messages[c.locales[0]][code]
+	return msg, ok
+}
+
+// all returns the messages of the code by locale.
+func (c *catalog) all(code string) map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]string, len(c.locales))
+	for _, locale := range c.locales {
+		if msg, ok := c.messages[locale][code]; ok {
+			result[locale] = msg
+		}
+	}
+	return result
+}
+
+// missing returns the codes lacking a message in any locale of the messages.
+func missing(m map[string]map[string]string, codes []string) []string {
+	var result []string
+	for locale, localeMessages := range m {
+		for _, code := range codes {
+			if _, ok := localeMessages[code]; !ok {
+				result = append(result, code+" in "+locale)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// loadMessages reads the <locale>.yaml files of the directory.
+func loadMessages(fsys fs.FS, dir string) (map[string]map[string]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]map[string]string{}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		tag, err := language.Parse(strings.TrimSuffix(entry.Name(), ext))
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale of %s: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		localeMessages := map[string]string{}
+		if err := yaml.Unmarshal(data, &localeMessages); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		m[tag.String()] = localeMessages
+	}
+
+	return m, nil
+}
+
+// registeredCodes returns the registered error codes.
+func registeredCodes() []string {
+	result := make([]string, 0, len(codes))
+	for code := range codes {
+		result = append(result, code)
+	}
+	return result
+}
+
+// mustHaveMessages panics if the code has no message in any locale.
+func mustHaveMessages(code string) {
+	messages.mu.RLock()
+	defer messages.mu.RUnlock()
+	if lacking := missing(messages.messages, []string{code}); len(lacking) > 0 {
+		panic(fmt.Sprintf("The error code %s has no message, please add it to %s", code, strings.Join(lacking, ", ")))
+	}
+}
+
+// LoadMessages overrides the embedded messages of the error codes by the
+// <locale>.yaml files of the directory, e.g. en-US.yaml, and adds the
+// locales which aren't embedded. Every registered code must have a message
+// in every locale, otherwise the messages are left unchanged.
+func LoadMessages(dir string) error {
+	overrides, err := loadMessages(os.DirFS(dir), ".")
+	if err != nil {
+		return fmt.Errorf("failed to load the messages of the error codes: %w", err)
+	}
+
+	messages.mu.RLock()
+	m := make(map[string]map[string]string, len(messages.messages)+len(overrides))
+	for locale, localeMessages := range messages.messages {
+		m[locale] = make(map[string]string, len(localeMessages))
+		for code, msg := range localeMessages {
+			m[locale][code] = msg
+		}
+	}
+	defaultLocale := messages.locales[0]
+	messages.mu.RUnlock()
+
+	for locale, localeMessages := range overrides {
+		if m[locale] == nil {
+			m[locale] = map[string]string{}
+		}
+		for code, msg := range localeMessages {
+			m[locale][code] = msg
+		}
+	}
+	if lacking := missing(m, registeredCodes()); len(lacking) > 0 {
+		return fmt.Errorf("the error codes have no message: %s", strings.Join(lacking, ", "))
+	}
+
+	return messages.set(m, defaultLocale)
+}
+
+// SetDefaultLocale sets the locale of the messages for the requests
+// accepting none of the supported locales.
+func SetDefaultLocale(locale string) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("invalid locale %s: %w", locale, err)
+	}
+
+	messages.mu.RLock()
+	m := messages.messages
+	messages.mu.RUnlock()
+
+	return messages.set(m, tag.String())
+}
+
+// Locales returns the supported locales, the default one first.
+func Locales() []string {
+	messages.mu.RLock()
+	defer messages.mu.RUnlock()
+	return append([]string(nil), messages.locales...)
+}
+
+// Message returns the message of the error code in the locale preferred by
+// the Accept-Language header, e.g. "en-GB,en;q=0.9,zh;q=0.8". The message
+// falls back to the closest supported locale, then to the default locale.
+// It returns false if the code has no message.
+//
+// Example:
+//
+//	msg, ok := errcode.Message("A0400", c.GetHeader("Accept-Language"))
+func Message(code, acceptLanguage string) (string, bool) {
+	return messages.message(code, acceptLanguage)
+}
+
+// LocalizedMsg returns the message of the error in the locale preferred by
+// the Accept-Language header, or its own message if its code has none.
+func LocalizedMsg(e coder, acceptLanguage string) string {
+	if msg, ok := Message(e.GetCode(), acceptLanguage); ok {
+		return msg
+	}
+	return e.GetMsg()
+}
@@ -0,0 +1,67 @@
+package errcode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage(t *testing.T) {
+	t.Run("Every code has messages in every locale", func(t *testing.T) {
+		require.Equal(t, []string{"zh-CN", "en-US"}, Locales())
+		require.Empty(t, missing(messages.messages, registeredCodes()))
+	})
+
+	t.Run("Fallback chain", func(t *testing.T) {
+		for acceptLanguage, want := range map[string]string{
+			"":                         "无效的用户输入",
+			"en-US":                    "Invalid user input",
+			"en-GB,en;q=0.9":           "Invalid user input",
+			"fr-FR,en;q=0.5,zh;q=0.8":  "无效的用户输入",
+			"fr-FR,zh;q=0.5,en;q=0.8":  "Invalid user input",
+			"fr-FR":                    "无效的用户输入",
+			"not a language;q=invalid": "无效的用户输入",
+		} {
+			msg, ok := Message(InvalidParams.GetCode(), acceptLanguage)
+			require.True(t, ok, acceptLanguage)
+			require.Equal(t, want, msg, acceptLanguage)
+		}
+
+		_, ok := Message("Z9999", "en-US")
+		require.False(t, ok)
+		require.Equal(t, "Invalid user input", LocalizedMsg(InvalidParams.Causef("for test"), "en"))
+	})
+
+	t.Run("Load messages", func(t *testing.T) {
+		t.Cleanup(func() {
+			messages = mustNewCatalog()
+		})
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "en-US.yaml"), []byte(`"A0400": "Bad input"`), 0o644))
+		require.NoError(t, LoadMessages(dir))
+		msg, _ := Message(InvalidParams.GetCode(), "en")
+		require.Equal(t, "Bad input", msg)
+		msg, _ = Message(NotFound.GetCode(), "en")
+		require.Equal(t, "Not found", msg)
+
+		// A new locale must cover every code
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "ja-JP.yaml"), []byte(`"A0400": "無効な入力"`), 0o644))
+		require.ErrorContains(t, LoadMessages(dir), "A0100 in ja-JP")
+		require.Equal(t, []string{"zh-CN", "en-US"}, Locales())
+
+		require.NoError(t, SetDefaultLocale("en-US"))
+		msg, _ = Message(NotFound.GetCode(), "fr")
+		require.Equal(t, "Not found", msg)
+		require.Error(t, SetDefaultLocale("ja-JP"))
+	})
+
+	t.Run("Register a code without messages", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewErrorCode("Z9999", "for test")
+		})
+		delete(codes, "Z9999")
+	})
+}
@@ -1,14 +1,118 @@
 package errcode
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
 
 const InvalidScope = "999"
 
-var codes = map[string]struct{}{}
+// Meta is the metadata of a registered error code.
+type Meta struct {
+	// Code is the error code, e.g. A0400
+	Code string `json:"code" yaml:"code"`
+	// Scope is the first three characters of the code, e.g. A04
+	Scope string `json:"scope" yaml:"scope"`
+	// Status is the HTTP status code of the responses failing with the code
+	Status int `json:"status" yaml:"status"`
+	// Messages maps the locales to the messages of the code
+	Messages map[string]string `json:"messages" yaml:"messages"`
+	// Retryable is true if the failed request may succeed when sent again
+	Retryable bool `json:"retryable" yaml:"retryable"`
+	// RetryAfter is the number of seconds to wait before sending the failed
+	// request again, answered in the Retry-After header
+	RetryAfter int `json:"retryAfter,omitempty" yaml:"retryAfter,omitempty"`
+}
+
+// Option configures the metadata of an error code at its registration.
+type Option func(m *Meta)
+
+// Retryable marks the requests failing with the error code as worth sending
+// again, e.g. on a timeout.
+func Retryable() Option {
+	return func(m *Meta) {
+		m.Retryable = true
+	}
+}
+
+// WithStatus answers the requests failing with the error code with the HTTP
+// status code instead of the one of its scope.
+func WithStatus(status int) Option {
+	return func(m *Meta) {
+		m.Status = status
+	}
+}
+
+// WithRetryAfter marks the requests failing with the error code as
+// retryable after the delay, which is answered in the Retry-After header
+// rounded up to seconds.
+func WithRetryAfter(delay time.Duration) Option {
+	return func(m *Meta) {
+		m.Retryable = true
+		m.RetryAfter = int((delay + time.Second - 1) / time.Second)
+	}
+}
+
+// scopeStatuses maps the scopes of the error codes to the HTTP status codes,
+// unless the codes have their own. The scopes missing here are internal
+// server errors.
+var scopeStatuses = map[string]int{
+	"000": http.StatusOK,
+	"A01": http.StatusNotFound,
+	"A02": http.StatusUnauthorized,
+	"A04": http.StatusBadRequest,
+	"A05": http.StatusInternalServerError,
+	"B00": http.StatusInternalServerError,
+}
 
-func mustSetCodeIfNotPresent(code string) {
+// scopeStatus returns the HTTP status code of the scope.
+func scopeStatus(scope string) int {
+	if status, ok := scopeStatuses[scope]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// codes is the registry of the error codes
+var codes = map[string]Meta{}
+
+func mustSetCodeIfNotPresent(code string, opts ...Option) {
 	if _, ok := codes[code]; ok {
 		panic(fmt.Sprintf("The error code %s already exists, please change one", code))
 	}
-	codes[code] = struct{}{}
+
+	m := Meta{Code: code, Scope: InvalidScope}
+	if len(code) == 5 {
+		m.Scope = code[0:3]
+	}
+	m.Status = scopeStatus(m.Scope)
+	for _, opt := range opts {
+		opt(&m)
+	}
+	codes[code] = m
+}
+
+// Lookup returns the metadata of the registered error code.
+func Lookup(code string) (Meta, bool) {
+	m, ok := codes[code]
+	if !ok {
+		return Meta{}, false
+	}
+	m.Messages = messages.all(code)
+	return m, true
+}
+
+// All returns the metadata of the registered error codes sorted by code.
+func All() []Meta {
+	result := make([]Meta, 0, len(codes))
+	for code := range codes {
+		m, _ := Lookup(code)
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
 }
@@ -0,0 +1,135 @@
+package errcode
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("Lookup", func(t *testing.T) {
+		m, ok := Lookup(TooManyRequests.GetCode())
+		require.True(t, ok)
+		require.Equal(t, Meta{
+			Code:       "A0501",
+			Scope:      "A05",
+			Status:     http.StatusTooManyRequests,
+			Messages:   map[string]string{"zh-CN": "请求次数超出限制", "en-US": "Too many requests"},
+			Retryable:  true,
+			RetryAfter: 1,
+		}, m)
+
+		_, ok = Lookup("Z9999")
+		require.False(t, ok)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		all := All()
+		require.Len(t, all, len(codes))
+		require.Equal(t, "00000", all[0].Code)
+		for i := 1; i < len(all); i++ {
+			require.Less(t, all[i-1].Code, all[i].Code)
+		}
+	})
+
+	t.Run("Status code of every registered code", func(t *testing.T) {
+		want := map[errors.ErrorCode]int{
+			Success:                    http.StatusOK,
+			ClientError:                http.StatusInternalServerError,
+			NotFound:                   http.StatusNotFound,
+			AccessPermissionError:      http.StatusUnauthorized,
+			TenantSuspended:            http.StatusForbidden,
+			ConfigFrozen:               http.StatusForbidden,
+			AbnormalUserOperation:      http.StatusInternalServerError,
+			InvalidParams:              http.StatusBadRequest,
+			BlankRequiredParams:        http.StatusBadRequest,
+			ExceedRangeParams:          http.StatusBadRequest,
+			MalformedParams:            http.StatusBadRequest,
+			ErrDeserializedParams:      http.StatusBadRequest,
+			SensitiveWordsParams:       http.StatusBadRequest,
+			TenantConfigQuotaExceeded:  http.StatusBadRequest,
+			TenantConfigSizeExceeded:   http.StatusBadRequest,
+			ConfigConflict:             http.StatusConflict,
+			ServerError:                http.StatusInternalServerError,
+			TooManyRequests:            http.StatusTooManyRequests,
+			ConcurrentExceedLimit:      http.StatusTooManyRequests,
+			WaitUserOperation:          http.StatusTooManyRequests,
+			RepeatedRequest:            http.StatusConflict,
+			AbnormalUserResources:      http.StatusInternalServerError,
+			AbnormalUserVersion:        http.StatusInternalServerError,
+			MismatchUserVersion:        http.StatusInternalServerError,
+			TooLowUserVersion:          http.StatusInternalServerError,
+			TooHighUserVersion:         http.StatusInternalServerError,
+			ExpiredUserVersion:         http.StatusInternalServerError,
+			MismatchAPIVersion:         http.StatusInternalServerError,
+			TooLowAPIVersion:           http.StatusInternalServerError,
+			TooHighAPIVersion:          http.StatusInternalServerError,
+			InternalError:              http.StatusInternalServerError,
+			InvalidStartupParams:       http.StatusInternalServerError,
+			SystemTimeout:              http.StatusInternalServerError,
+			SystemResourceError:        http.StatusInternalServerError,
+			ReadDiskFailed:             http.StatusInternalServerError,
+			ThirdPartyServiceError:     http.StatusInternalServerError,
+			MiddlewareServiceError:     http.StatusInternalServerError,
+			MiddlewareServiceTimeout:   http.StatusInternalServerError,
+			DatabaseServiceError:       http.StatusInternalServerError,
+			DatabaseServiceTimeout:     http.StatusInternalServerError,
+			NotificationServiceError:   http.StatusInternalServerError,
+			NotificationServiceTimeout: http.StatusInternalServerError,
+		}
+		require.Len(t, want, len(codes), "add the status of the new error code")
+		for e, status := range want {
+			require.Equal(t, status, StatusCode(e), e.GetCode())
+			require.Equal(t, status, StatusCode(e.Causef("for test")), e.GetCode())
+		}
+	})
+
+	t.Run("Status code by scope", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, StatusCode(errors.NewErrorCode("A0499", "unregistered")))
+		require.Equal(t, http.StatusInternalServerError, StatusCode(errors.NewErrorCode("A0599", "unregistered")))
+		require.Equal(t, http.StatusInternalServerError, StatusCode(errors.New("for test")))
+	})
+
+	t.Run("Retry after", func(t *testing.T) {
+		require.Equal(t, time.Second, RetryAfter(TooManyRequests.Causef("for test")))
+		require.Zero(t, RetryAfter(RepeatedRequest))
+		require.Zero(t, RetryAfter(errors.New("for test")))
+	})
+}
+
+func TestDocs(t *testing.T) {
+	var markdown bytes.Buffer
+	require.NoError(t, WriteMarkdown(&markdown))
+	require.Contains(t, markdown.String(), "| Code | Status | Retryable | Retry-After | zh-CN | en-US |\n")
+	require.Contains(t, markdown.String(), "| <a id=\"a0400\"></a>`A0400` | 400 | false |  | 无效的用户输入 | Invalid user input |\n")
+
+	var openAPI bytes.Buffer
+	require.NoError(t, WriteOpenAPI(&openAPI))
+	var spec struct {
+		Components struct {
+			Schemas map[string]struct {
+				Enum       []string `yaml:"enum"`
+				ErrorCodes []Meta   `yaml:"x-error-codes"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal(openAPI.Bytes(), &spec))
+	require.Len(t, spec.Components.Schemas["ErrorCode"].Enum, len(codes))
+	require.Equal(t, All(), spec.Components.Schemas["ErrorCode"].ErrorCodes)
+	require.Contains(t, spec.Components.Schemas, "ErrorCodeMeta")
+
+	// The committed documents must be regenerated by "make gen-errcode-docs"
+	committed, err := os.ReadFile(filepath.Join("..", "..", "docs", "errcode.md"))
+	require.NoError(t, err)
+	require.Equal(t, markdown.String(), string(committed), "run make gen-errcode-docs")
+	committed, err = os.ReadFile(filepath.Join("..", "..", "api", "openapispec", "errcode.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, openAPI.String(), string(committed), "run make gen-errcode-docs")
+}
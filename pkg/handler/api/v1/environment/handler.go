@@ -0,0 +1,194 @@
+package environment
+
+import (
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/crud"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	repo repository.EnvironmentRepository
+}
+
+func NewHandler(repo repository.EnvironmentRepository) *Handler {
+	return &Handler{
+		repo: repo,
+	}
+}
+
+// Routes returns the handlers of the environment routes.
+func (h *Handler) Routes() crud.Routes {
+	return crud.Routes{
+		Create: handler.WrapT(h.CreateEnvironment),
+		Delete: handler.WrapT(h.DeleteEnvironment),
+		Update: handler.WrapT(h.UpdateEnvironment),
+		Get:    handler.WrapT(h.GetEnvironment),
+		List:   handler.WrapT(h.ListEnvironments),
+	}
+}
+
+// @Summary      Create environment
+// @Description  Create a new environment
+// @Accept       json
+// @Produce      json
+// @Param        environment  body      CreateEnvironmentRequest                   true  "Created environment"
+// @Success      200          {object}  handler.Response{data=entity.Environment}  "Success"
+// @Failure      400          {object}  handler.Response                           "Bad Request"
+// @Failure      401          {object}  handler.Response                           "Unauthorized"
+// @Failure      429          {object}  handler.Response                           "Too Many Requests"
+// @Failure      404          {object}  handler.Response                           "Not Found"
+// @Failure      500          {object}  handler.Response                           "Internal Server Error"
+// @Router       /api/v1/environment [post]
+func (h *Handler) CreateEnvironment(c *gin.Context, log logrus.FieldLogger, req *CreateEnvironmentRequest) (*entity.Environment, error) {
+	// Convert request payload to domain model
+	var environment entity.Environment
+	if err := copier.Copy(&environment, req); err != nil {
+		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
+	}
+	creator, err := handler.RequireOperator(c.Request.Context(), req.Creator, "creator")
+	if err != nil {
+		return nil, err
+	}
+	environment.Creator = creator
+	environment.Modifier = handler.Operator(c.Request.Context(), req.Modifier)
+	if err := environment.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to create environment")
+	}
+
+	// Create environment with repository
+	err = h.repo.Create(c.Request.Context(), &environment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to creating environment with repository")
+	}
+
+	// Return created environment
+	return &environment, nil
+}
+
+// @Summary      Delete environment
+// @Description  Delete specified environment by ID, protected environments and environments in use can't be deleted
+// @Produce      json
+// @Param        id   path      int               true  "Environment ID"
+// @Success      200  {object}  handler.Response  "Success"
+// @Failure      400  {object}  handler.Response  "Bad Request"
+// @Failure      401  {object}  handler.Response  "Unauthorized"
+// @Failure      429  {object}  handler.Response  "Too Many Requests"
+// @Failure      404  {object}  handler.Response  "Not Found"
+// @Failure      500  {object}  handler.Response  "Internal Server Error"
+// @Router       /api/v1/environment/{id} [delete]
+func (h *Handler) DeleteEnvironment(c *gin.Context, log logrus.FieldLogger, req *DeleteEnvironmentRequest) (any, error) {
+	// Delete environment with repository
+	err := h.repo.Delete(c.Request.Context(), req.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, errcode.NotFound.Causewf(err, "failed to delete environment")
+		case errors.Is(err, entity.ErrEnvProtected), errors.Is(err, entity.ErrEnvInUse):
+			return nil, errcode.InvalidParams.Causewf(err, "failed to delete environment")
+		}
+		return nil, errors.Wrap(err, "failed to deleting environment with repository")
+	}
+
+	// Return deleted environment
+	return nil, nil
+}
+
+// @Summary      Update environment
+// @Description  Update the specified environment, the name of an environment can't be changed
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                                        true  "Environment ID"
+// @Param        environment  body      UpdateEnvironmentRequest                   true  "Updated environment"
+// @Success      200          {object}  handler.Response{data=entity.Environment}  "Success"
+// @Failure      400          {object}  handler.Response                           "Bad Request"
+// @Failure      401          {object}  handler.Response                           "Unauthorized"
+// @Failure      429          {object}  handler.Response                           "Too Many Requests"
+// @Failure      404          {object}  handler.Response                           "Not Found"
+// @Failure      500          {object}  handler.Response                           "Internal Server Error"
+// @Router       /api/v1/environment/{id} [put]
+func (h *Handler) UpdateEnvironment(c *gin.Context, log logrus.FieldLogger, req *UpdateEnvironmentRequest) (*entity.Environment, error) {
+	// Get the existed environment by id
+	updatedEntity, err := h.repo.Get(c.Request.Context(), req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to update environment")
+		}
+		return nil, errcode.InvalidParams.Cause(err)
+	}
+
+	// Overwrite the specified values in request to existed entity
+	if req.Description != "" {
+		updatedEntity.Description = req.Description
+	}
+	if req.Order != nil {
+		updatedEntity.Order = *req.Order
+	}
+	if req.Protected != nil {
+		updatedEntity.Protected = *req.Protected
+	}
+	if modifier := handler.Operator(c.Request.Context(), req.Modifier); modifier != "" {
+		updatedEntity.Modifier = modifier
+	}
+
+	// Update environment with repository
+	err = h.repo.Update(c.Request.Context(), updatedEntity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to updating environment with repository")
+	}
+
+	// Return updated environment
+	return updatedEntity, nil
+}
+
+// @Summary      Get environment
+// @Description  Get environment information by environment ID
+// @Produce      json
+// @Param        id   path      int                                        true  "Environment ID"
+// @Success      200  {object}  handler.Response{data=entity.Environment}  "Success"
+// @Failure      400  {object}  handler.Response                           "Bad Request"
+// @Failure      401  {object}  handler.Response                           "Unauthorized"
+// @Failure      429  {object}  handler.Response                           "Too Many Requests"
+// @Failure      404  {object}  handler.Response                           "Not Found"
+// @Failure      500  {object}  handler.Response                           "Internal Server Error"
+// @Router       /api/v1/environment/{id} [get]
+func (h *Handler) GetEnvironment(c *gin.Context, log logrus.FieldLogger, req *GetEnvironmentRequest) (*entity.Environment, error) {
+	// Get environment with repository
+	existedEntity, err := h.repo.Get(c.Request.Context(), req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to get environment")
+		}
+		return nil, errors.Wrap(err, "failed to get environment with repository")
+	}
+
+	// Return environment
+	return existedEntity, nil
+}
+
+// @Summary      List environments
+// @Description  List all environments ordered by their order in the promotion path
+// @Produce      json
+// @Success      200  {object}  handler.Response{data=[]entity.Environment}  "Success"
+// @Failure      400  {object}  handler.Response                             "Bad Request"
+// @Failure      401  {object}  handler.Response                             "Unauthorized"
+// @Failure      429  {object}  handler.Response                             "Too Many Requests"
+// @Failure      404  {object}  handler.Response                             "Not Found"
+// @Failure      500  {object}  handler.Response                             "Internal Server Error"
+// @Router       /api/v1/environments [get]
+func (h *Handler) ListEnvironments(c *gin.Context, log logrus.FieldLogger, _ *ListEnvironmentsRequest) ([]*entity.Environment, error) {
+	// List environments with repository
+	dataEntities, err := h.repo.List(c.Request.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list environments with repository")
+	}
+
+	// Return all environments
+	return dataEntities, nil
+}
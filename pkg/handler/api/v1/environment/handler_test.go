@@ -0,0 +1,102 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler/crud"
+	"github.com/elliotxx/go-web-template/pkg/handler/handlertest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	db := handlertest.NewDB(t)
+	engine := handlertest.NewEngine()
+	crud.Mount(engine, "environment", NewHandler(persistence.NewEnvironmentRepository(db)).Routes())
+
+	var staging entity.Environment
+	t.Run("Create", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/environment", "alice",
+			`{"name": "staging", "description": "Staging environment", "order": 35, "creator": "mallory"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &staging)
+		require.NotZero(t, staging.ID)
+		require.Equal(t, entity.Env("staging"), staging.Name)
+		require.Equal(t, 35, staging.Order)
+		require.Equal(t, "alice", staging.Creator)
+	})
+
+	t.Run("Create without creator", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/environment", "", `{"name": "qa"}`)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.InvalidParams.GetCode(), resp.Code)
+	})
+
+	t.Run("Get and list", func(t *testing.T) {
+		var got entity.Environment
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/environment/%d", staging.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &got)
+		require.Equal(t, staging.Name, got.Name)
+
+		resp = handlertest.Do(t, engine, http.MethodGet, "/environment/100", "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		require.Equal(t, errcode.NotFound.GetCode(), resp.Code)
+
+		var environments []*entity.Environment
+		resp = handlertest.Do(t, engine, http.MethodGet, "/environments", "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &environments)
+		require.Len(t, environments, 7)
+	})
+
+	t.Run("Update the environment of the path", func(t *testing.T) {
+		// The ID of the body is ignored
+		var updated entity.Environment
+		resp := handlertest.Do(t, engine, http.MethodPut, fmt.Sprintf("/environment/%d", staging.ID), "bob",
+			`{"id": 1, "description": "Shared staging", "protected": true}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &updated)
+		require.Equal(t, staging.ID, updated.ID)
+		require.Equal(t, "Shared staging", updated.Description)
+		require.Equal(t, 35, updated.Order)
+		require.True(t, updated.Protected)
+		require.Equal(t, "bob", updated.Modifier)
+
+		var dev entity.Environment
+		resp = handlertest.Do(t, engine, http.MethodGet, "/environment/1", "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &dev)
+		require.Equal(t, "Development environment", dev.Description)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, "/environment/100", "bob", `{"description": "missing"}`)
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		// The protected environments and the ones in use are kept
+		resp := handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/environment/%d", staging.ID), "", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.InvalidParams.GetCode(), resp.Code)
+
+		require.NoError(t, persistence.NewSystemConfigRepository(db).Create(context.Background(), &entity.SystemConfig{
+			Tenant: "MAIN_SITE", Env: "dev", Type: "db", Config: "{}", Creator: "alice",
+		}))
+		resp = handlertest.Do(t, engine, http.MethodDelete, "/environment/1", "", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, fmt.Sprintf("/environment/%d", staging.ID), "bob", `{"protected": false}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/environment/%d", staging.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp = handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/environment/%d", staging.ID), "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/environment/%d", staging.ID), "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+}
@@ -0,0 +1,54 @@
+package environment
+
+// CreateEnvironmentRequest represents the create request structure for
+// an environment.
+type CreateEnvironmentRequest struct {
+	// Name of the environment (e.g. prod, gray)
+	Name string `json:"name" binding:"required"`
+	// Description or purpose of the environment
+	Description string `json:"description"`
+	// Order of the environment in the promotion path
+	Order int `json:"order"`
+	// Protected environments can't be deleted
+	Protected bool `json:"protected"`
+	// Username or ID of the user who created the environment, required unless
+	// the request has an authenticated principal, which overrides it
+	Creator string `json:"creator"`
+	// Username or ID of the user who last modified the environment, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+}
+
+// UpdateEnvironmentRequest represents the update request structure for
+// an environment. The name of an environment can't be changed.
+type UpdateEnvironmentRequest struct {
+	// Unique ID of the environment, in the path
+	ID uint `json:"-" uri:"id" binding:"required"`
+	// Description or purpose of the environment
+	Description string `json:"description"`
+	// Order of the environment in the promotion path
+	Order *int `json:"order"`
+	// Protected environments can't be deleted
+	Protected *bool `json:"protected"`
+	// Username or ID of the user who last modified the environment, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+}
+
+// GetEnvironmentRequest represents the get request structure for
+// an environment.
+type GetEnvironmentRequest struct {
+	// Unique ID of the environment
+	ID uint `uri:"id" binding:"required"`
+}
+
+// DeleteEnvironmentRequest represents the delete request structure for
+// an environment.
+type DeleteEnvironmentRequest struct {
+	// Unique ID of the environment
+	ID uint `uri:"id" binding:"required"`
+}
+
+// ListEnvironmentsRequest represents the list request structure for
+// environments, which has no parameters.
+type ListEnvironmentsRequest struct{}
@@ -0,0 +1,28 @@
+package errcodes
+
+import (
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type Handler struct{}
+
+// ListErrorCodes is served by handler.WrapT, whose response is documented as
+// handler.Response.
+var _ handler.HandlerTypedFunc[ListErrorCodesRequest, []errcode.Meta] = (*Handler)(nil).ListErrorCodes
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// @Summary      List error codes
+// @Description  List the registered error codes with their HTTP statuses, messages by locale and whether they're retryable
+// @Produce      json
+// @Success      200  {object}  handler.Response{data=[]errcode.Meta}  "Success"
+// @Failure      500  {object}  handler.Response                       "Internal Server Error"
+// @Router       /api/v1/errcodes [get]
+func (h *Handler) ListErrorCodes(c *gin.Context, log logrus.FieldLogger, _ *ListErrorCodesRequest) ([]errcode.Meta, error) {
+	return errcode.All(), nil
+}
@@ -0,0 +1,4 @@
+package errcodes
+
+// ListErrorCodesRequest represents the request to list the error codes
+type ListErrorCodesRequest struct{}
@@ -0,0 +1,301 @@
+package freezewindow
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/util/kdump"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	repo repository.FreezeWindowRepository
+}
+
+func NewHandler(repo repository.FreezeWindowRepository) *Handler {
+	return &Handler{
+		repo: repo,
+	}
+}
+
+// @Summary      Create freeze window
+// @Description  Create a new freeze window blocking writes to the system configs in its scope
+// @Accept       json
+// @Produce      json
+// @Param        window  body      CreateFreezeWindowRequest  true  "Created freeze window"
+// @Success      200     {object}  entity.FreezeWindow        "Success"
+// @Failure      400     {object}  errors.DetailError         "Bad Request"
+// @Failure      401     {object}  errors.DetailError         "Unauthorized"
+// @Failure      429     {object}  errors.DetailError         "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError         "Not Found"
+// @Failure      500     {object}  errors.DetailError         "Internal Server Error"
+// @Router       /api/v1/freezewindow [post]
+func (h *Handler) CreateFreezeWindow(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	var requestPayload CreateFreezeWindowRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Convert request payload to domain model
+	var window entity.FreezeWindow
+	if err := copier.Copy(&window, &requestPayload); err != nil {
+		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
+	}
+	creator, err := handler.RequireOperator(c.Request.Context(), requestPayload.Creator, "creator")
+	if err != nil {
+		return nil, err
+	}
+	window.Creator = creator
+	window.Modifier = handler.Operator(c.Request.Context(), requestPayload.Modifier)
+	if err := window.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to create freeze window")
+	}
+
+	// Create freeze window with repository
+	err = h.repo.Create(c.Request.Context(), &window)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to creating freeze window with repository")
+	}
+
+	// Return created freeze window
+	return window, nil
+}
+
+// @Summary      Delete freeze window
+// @Description  Delete specified freeze window by ID
+// @Produce      json
+// @Param        id   path      int                 true  "Freeze window ID"
+// @Success      200  {object}  nil                 "Success"
+// @Failure      400  {object}  errors.DetailError  "Bad Request"
+// @Failure      401  {object}  errors.DetailError  "Unauthorized"
+// @Failure      429  {object}  errors.DetailError  "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError  "Not Found"
+// @Failure      500  {object}  errors.DetailError  "Internal Server Error"
+// @Router       /api/v1/freezewindow/{id} [delete]
+func (h *Handler) DeleteFreezeWindow(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse freeze window id")
+	}
+
+	// Delete freeze window with repository
+	err = h.repo.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to delete freeze window")
+		}
+		return nil, errors.Wrap(err, "failed to deleting freeze window with repository")
+	}
+
+	return nil, nil
+}
+
+// @Summary      Update freeze window
+// @Description  Update the scope and the period of the specified freeze window
+// @Accept       json
+// @Produce      json
+// @Param        window  body      UpdateFreezeWindowRequest  true  "Updated freeze window"
+// @Success      200     {object}  entity.FreezeWindow        "Success"
+// @Failure      400     {object}  errors.DetailError         "Bad Request"
+// @Failure      401     {object}  errors.DetailError         "Unauthorized"
+// @Failure      429     {object}  errors.DetailError         "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError         "Not Found"
+// @Failure      500     {object}  errors.DetailError         "Internal Server Error"
+// @Router       /api/v1/freezewindow [put]
+func (h *Handler) UpdateFreezeWindow(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	var requestPayload UpdateFreezeWindowRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Get the existed freeze window by id
+	updatedEntity, err := h.repo.Get(c.Request.Context(), requestPayload.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to update freeze window")
+		}
+		return nil, errcode.InvalidParams.Cause(err)
+	}
+
+	// Overwrite the scope and the period of the existed entity
+	updatedEntity.Name = requestPayload.Name
+	updatedEntity.Tenant = requestPayload.Tenant
+	updatedEntity.Env = entity.Env(requestPayload.Env)
+	updatedEntity.Type = requestPayload.Type
+	updatedEntity.StartAt = requestPayload.StartAt
+	updatedEntity.EndAt = requestPayload.EndAt
+	updatedEntity.Description = requestPayload.Description
+	if modifier := handler.Operator(c.Request.Context(), requestPayload.Modifier); modifier != "" {
+		updatedEntity.Modifier = modifier
+	}
+	if err = updatedEntity.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to update freeze window")
+	}
+
+	// Update freeze window with repository
+	err = h.repo.Update(c.Request.Context(), updatedEntity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to updating freeze window with repository")
+	}
+
+	// Return updated freeze window
+	return updatedEntity, nil
+}
+
+// @Summary      Get freeze window
+// @Description  Get freeze window information by ID
+// @Produce      json
+// @Param        id   path      int                  true  "Freeze window ID"
+// @Success      200  {object}  entity.FreezeWindow  "Success"
+// @Failure      400  {object}  errors.DetailError   "Bad Request"
+// @Failure      401  {object}  errors.DetailError   "Unauthorized"
+// @Failure      429  {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError   "Not Found"
+// @Failure      500  {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/freezewindow/{id} [get]
+func (h *Handler) GetFreezeWindow(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	// Get freeze window with repository
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse freeze window id")
+	}
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to get freeze window")
+		}
+		return nil, errors.Wrap(err, "failed to get freeze window with repository")
+	}
+
+	// Return freeze window
+	return existedEntity, nil
+}
+
+// @Summary      Find freeze windows
+// @Description  Find freeze windows with query, latest start first
+// @Accept       json
+// @Produce      json
+// @Param        query  body      QueryFreezeWindowRequest  true  "query body"
+// @Success      200    {array}   entity.FreezeWindow       "Success"
+// @Failure      400    {object}  errors.DetailError        "Bad Request"
+// @Failure      401    {object}  errors.DetailError        "Unauthorized"
+// @Failure      429    {object}  errors.DetailError        "Too Many Requests"
+// @Failure      404    {object}  errors.DetailError        "Not Found"
+// @Failure      500    {object}  errors.DetailError        "Internal Server Error"
+// @Router       /api/v1/freezewindows [get]
+func (h *Handler) FindFreezeWindows(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	var requestPayload QueryFreezeWindowRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Find freeze windows with repository
+	dataEntities, err := h.repo.Find(c.Request.Context(), repository.Query{
+		Offset:  (requestPayload.Page - 1) * requestPayload.PerPage,
+		Limit:   requestPayload.PerPage,
+		Keyword: requestPayload.Keyword,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find freeze windows with repository")
+	}
+
+	// Return found freeze windows
+	return dataEntities, nil
+}
+
+// @Summary      Find freeze overrides
+// @Description  Find the recorded writes which bypassed the freeze window, latest first
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                       true  "Freeze window ID"
+// @Param        query  body      QueryFreezeWindowRequest  true  "query body"
+// @Success      200    {array}   entity.FreezeOverride     "Success"
+// @Failure      400    {object}  errors.DetailError        "Bad Request"
+// @Failure      401    {object}  errors.DetailError        "Unauthorized"
+// @Failure      429    {object}  errors.DetailError        "Too Many Requests"
+// @Failure      404    {object}  errors.DetailError        "Not Found"
+// @Failure      500    {object}  errors.DetailError        "Internal Server Error"
+// @Router       /api/v1/freezewindow/{id}/overrides [get]
+func (h *Handler) FindFreezeOverrides(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse freeze window id")
+	}
+
+	var requestPayload QueryFreezeWindowRequest
+	if err = c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request params id: %s, payload: %v", paramID, kdump.FormatN(requestPayload))
+
+	// Find freeze overrides with repository
+	dataEntities, err := h.repo.FindOverrides(c.Request.Context(), uint(id), repository.Query{
+		Offset: (requestPayload.Page - 1) * requestPayload.PerPage,
+		Limit:  requestPayload.PerPage,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find freeze overrides with repository")
+	}
+
+	// Return found freeze overrides
+	return dataEntities, nil
+}
+
+// @Summary      Get freeze status
+// @Description  Report whether the system configs of the scope are frozen now
+// @Produce      json
+// @Param        tenant  query     string                true  "Tenant of the system configs"
+// @Param        env     query     string                true  "Environment of the system configs"
+// @Param        type    query     string                true  "Type of the system configs"
+// @Success      200     {object}  FreezeStatusResponse  "Success"
+// @Failure      400     {object}  errors.DetailError    "Bad Request"
+// @Failure      401     {object}  errors.DetailError    "Unauthorized"
+// @Failure      429     {object}  errors.DetailError    "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError    "Not Found"
+// @Failure      500     {object}  errors.DetailError    "Internal Server Error"
+// @Router       /api/v1/freezewindow/status [get]
+func (h *Handler) GetFreezeStatus(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	tenant, env, typ := c.Query("tenant"), c.Query("env"), c.Query("type")
+	log.Infof("Request params tenant: %s, env: %s, type: %s", tenant, env, typ)
+
+	if tenant == "" || env == "" || typ == "" {
+		return nil, errcode.BlankRequiredParams.Causef("tenant, env and type must be specified")
+	}
+
+	// Find active freeze windows with repository
+	windows, err := h.repo.FindActive(c.Request.Context(), tenant, entity.Env(env), typ, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find active freeze windows with repository")
+	}
+
+	// Return freeze status
+	return FreezeStatusResponse{
+		Frozen:  len(windows) > 0,
+		Windows: windows,
+	}, nil
+}
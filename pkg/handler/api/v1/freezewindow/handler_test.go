@@ -0,0 +1,178 @@
+package freezewindow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/systemconfig"
+	"github.com/elliotxx/go-web-template/pkg/handler/handlertest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	db := handlertest.NewDB(t)
+	tenants := persistence.NewTenantRepository(db)
+	h := NewHandler(persistence.NewFreezeWindowRepository(db))
+	configs := systemconfig.NewHandler(persistence.NewSystemConfigRepository(db), persistence.NewScheduledChangeRepository(db), tenants)
+	engine := handlertest.NewEngine()
+	engine.POST("/freezewindow", handler.WrapFD(h.CreateFreezeWindow))
+	engine.DELETE("/freezewindow/:id", handler.WrapFD(h.DeleteFreezeWindow))
+	engine.PUT("/freezewindow", handler.WrapFD(h.UpdateFreezeWindow))
+	engine.GET("/freezewindow/:id", handler.WrapFD(h.GetFreezeWindow))
+	engine.GET("/freezewindow/:id/overrides", handler.WrapFD(h.FindFreezeOverrides))
+	engine.GET("/freezewindows", handler.WrapFD(h.FindFreezeWindows))
+	engine.GET("/freezewindow/status", handler.WrapFD(h.GetFreezeStatus))
+	engine.POST("/systemconfig", handler.WrapFD(configs.CreateSystemConfig))
+	engine.PUT("/systemconfig", handler.WrapFD(configs.UpdateSystemConfig))
+
+	require.NoError(t, tenants.Create(context.Background(), &entity.Tenant{
+		Name: "payments", Owners: []string{"alice"}, Status: entity.TenantStatusActive, Creator: "alice",
+	}))
+	var config entity.SystemConfig
+	resp := handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "bob",
+		`{"tenant": "payments", "env": "prod", "type": "db", "config": "v1"}`)
+	require.Equal(t, http.StatusOK, resp.Status)
+	resp.Decode(t, &config)
+
+	startAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	endAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	status := func(t *testing.T, query string) FreezeStatusResponse {
+		var status FreezeStatusResponse
+		resp := handlertest.Do(t, engine, http.MethodGet, "/freezewindow/status?"+query, "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &status)
+		return status
+	}
+	overrides := func(t *testing.T, id uint) []*entity.FreezeOverride {
+		var overrides []*entity.FreezeOverride
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/freezewindow/%d/overrides", id), "",
+			`{"page": 1, "perPage": 10}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &overrides)
+		return overrides
+	}
+
+	var release, payments entity.FreezeWindow
+	t.Run("Create", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/freezewindow", "carol",
+			fmt.Sprintf(`{"name": "release", "env": "prod", "startAt": %q, "endAt": %q}`, startAt, endAt))
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &release)
+		require.Equal(t, "carol", release.Creator)
+
+		// The windows of a narrower scope may overlap
+		resp = handlertest.Do(t, engine, http.MethodPost, "/freezewindow", "carol",
+			fmt.Sprintf(`{"name": "payments", "tenant": "payments", "env": "prod", "type": "db", "startAt": %q, "endAt": %q}`, startAt, endAt))
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &payments)
+
+		resp = handlertest.Do(t, engine, http.MethodPost, "/freezewindow", "carol",
+			fmt.Sprintf(`{"name": "backwards", "startAt": %q, "endAt": %q}`, endAt, startAt))
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+
+		resp = handlertest.Do(t, engine, http.MethodPost, "/freezewindow", "",
+			fmt.Sprintf(`{"name": "anonymous", "startAt": %q, "endAt": %q}`, startAt, endAt))
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("Get and find", func(t *testing.T) {
+		var window entity.FreezeWindow
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/freezewindow/%d", payments.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &window)
+		require.Equal(t, "payments", window.Tenant)
+
+		var windows []entity.FreezeWindow
+		resp = handlertest.Do(t, engine, http.MethodGet, "/freezewindows", "", `{"page": 1, "perPage": 10}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &windows)
+		require.Len(t, windows, 2)
+
+		resp = handlertest.Do(t, engine, http.MethodGet, "/freezewindow/100", "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+
+	t.Run("Status of overlapping windows", func(t *testing.T) {
+		frozen := status(t, "tenant=payments&env=prod&type=db")
+		require.True(t, frozen.Frozen)
+		require.Len(t, frozen.Windows, 2)
+
+		frozen = status(t, "tenant=payments&env=prod&type=cache")
+		require.True(t, frozen.Frozen)
+		require.Len(t, frozen.Windows, 1)
+		require.Equal(t, release.ID, frozen.Windows[0].ID)
+
+		require.False(t, status(t, "tenant=payments&env=dev&type=db").Frozen)
+
+		resp := handlertest.Do(t, engine, http.MethodGet, "/freezewindow/status?tenant=payments", "", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("Reject writes during the freeze", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "alice",
+			fmt.Sprintf(`{"id": %d, "config": "v2"}`, config.ID))
+		require.Equal(t, http.StatusForbidden, resp.Status)
+		require.Equal(t, errcode.ConfigFrozen.GetCode(), resp.Code)
+	})
+
+	t.Run("Reject the override of a non-owner", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "bob",
+			fmt.Sprintf(`{"id": %d, "config": "v2", "freezeOverrideReason": "hotfix"}`, config.ID))
+		require.Equal(t, http.StatusUnauthorized, resp.Status)
+		require.Equal(t, errcode.AccessPermissionError.GetCode(), resp.Code)
+		require.Empty(t, overrides(t, release.ID))
+	})
+
+	t.Run("Override by an owner", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "alice",
+			fmt.Sprintf(`{"id": %d, "config": "v2", "freezeOverrideReason": "hotfix"}`, config.ID))
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		// The override is recorded in every window it bypassed
+		for _, id := range []uint{release.ID, payments.ID} {
+			recorded := overrides(t, id)
+			require.Len(t, recorded, 1)
+			require.Equal(t, config.ID, recorded[0].SystemConfigID)
+			require.Equal(t, "alice", recorded[0].Operator)
+			require.Equal(t, "hotfix", recorded[0].Reason)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		ended := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		var updated entity.FreezeWindow
+		resp := handlertest.Do(t, engine, http.MethodPut, "/freezewindow", "dave",
+			fmt.Sprintf(`{"id": %d, "name": "release", "env": "prod", "startAt": %q, "endAt": %q}`, release.ID, startAt, ended))
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &updated)
+		require.Equal(t, "carol", updated.Creator)
+		require.Equal(t, "dave", updated.Modifier)
+
+		require.False(t, status(t, "tenant=payments&env=prod&type=cache").Frozen)
+		require.True(t, status(t, "tenant=payments&env=prod&type=db").Frozen)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, "/freezewindow", "dave",
+			fmt.Sprintf(`{"id": 100, "name": "missing", "startAt": %q, "endAt": %q}`, startAt, endAt))
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/freezewindow/%d", payments.ID), "carol", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.False(t, status(t, "tenant=payments&env=prod&type=db").Frozen)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "bob",
+			fmt.Sprintf(`{"id": %d, "config": "v3"}`, config.ID))
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/freezewindow/%d", payments.ID), "carol", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+}
@@ -0,0 +1,63 @@
+package freezewindow
+
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/handler"
+)
+
+// CreateFreezeWindowRequest represents the create request structure for a
+// freeze window. An empty tenant, env or type matches all system configs.
+type CreateFreezeWindowRequest struct {
+	// Name of the freeze window
+	Name string `json:"name" binding:"required"`
+	// Tenant of the frozen system configs
+	Tenant string `json:"tenant"`
+	// Environment of the frozen system configs (e.g. prod)
+	Env string `json:"env"`
+	// Type of the frozen system configs (e.g. cache)
+	Type string `json:"type"`
+	// Time when the freeze starts
+	StartAt time.Time `json:"startAt" binding:"required"`
+	// Time when the freeze ends
+	EndAt time.Time `json:"endAt" binding:"required"`
+	// Description or purpose of the freeze window
+	Description string `json:"description"`
+	// Username or ID of the user who created the freeze window, required unless
+	// the request has an authenticated principal, which overrides it
+	Creator string `json:"creator"`
+	// Username or ID of the user who last modified the freeze window, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+}
+
+// UpdateFreezeWindowRequest represents the update request structure for a
+// freeze window. The scope and the period are replaced as a whole.
+type UpdateFreezeWindowRequest struct {
+	// Unique ID of the freeze window
+	ID uint `json:"id" binding:"required"`
+	// Name of the freeze window
+	Name string `json:"name" binding:"required"`
+	// Tenant of the frozen system configs
+	Tenant string `json:"tenant"`
+	// Environment of the frozen system configs (e.g. prod)
+	Env string `json:"env"`
+	// Type of the frozen system configs (e.g. cache)
+	Type string `json:"type"`
+	// Time when the freeze starts
+	StartAt time.Time `json:"startAt" binding:"required"`
+	// Time when the freeze ends
+	EndAt time.Time `json:"endAt" binding:"required"`
+	// Description or purpose of the freeze window
+	Description string `json:"description"`
+	// Username or ID of the user who last modified the freeze window, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+}
+
+// QueryFreezeWindowRequest represents the query request structure for
+// freeze windows.
+type QueryFreezeWindowRequest struct {
+	handler.Pagination
+	handler.Search
+}
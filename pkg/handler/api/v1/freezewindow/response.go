@@ -0,0 +1,11 @@
+package freezewindow
+
+import "github.com/elliotxx/go-web-template/pkg/domain/entity"
+
+// FreezeStatusResponse represents the freeze status of a scope.
+type FreezeStatusResponse struct {
+	// Frozen is true if the system configs of the scope can't be written
+	Frozen bool `json:"frozen"`
+	// Windows are the freeze windows covering the scope now
+	Windows []*entity.FreezeWindow `json:"windows"`
+}
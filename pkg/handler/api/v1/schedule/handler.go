@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"strconv"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/util/kdump"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	repo repository.ScheduledChangeRepository
+}
+
+func NewHandler(repo repository.ScheduledChangeRepository) *Handler {
+	return &Handler{
+		repo: repo,
+	}
+}
+
+// @Summary      List scheduled changes
+// @Description  List the scheduled changes waiting to be applied or reverted, ordered by effective time
+// @Accept       json
+// @Produce      json
+// @Param        query  body      QueryScheduleRequest    true  "query body"
+// @Success      200    {array}   entity.ScheduledChange  "Success"
+// @Failure      400    {object}  errors.DetailError      "Bad Request"
+// @Failure      401    {object}  errors.DetailError      "Unauthorized"
+// @Failure      429    {object}  errors.DetailError      "Too Many Requests"
+// @Failure      404    {object}  errors.DetailError      "Not Found"
+// @Failure      500    {object}  errors.DetailError      "Internal Server Error"
+// @Router       /api/v1/schedules [get]
+func (h *Handler) ListSchedules(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	var requestPayload QueryScheduleRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Find pending scheduled changes with repository
+	dataEntities, err := h.repo.FindPending(c.Request.Context(), repository.Query{
+		Offset: (requestPayload.Page - 1) * requestPayload.PerPage,
+		Limit:  requestPayload.PerPage,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find scheduled changes with repository")
+	}
+
+	// Return found scheduled changes
+	return dataEntities, nil
+}
+
+// @Summary      Get scheduled change
+// @Description  Get scheduled change information by ID
+// @Produce      json
+// @Param        id   path      int                     true  "Scheduled change ID"
+// @Success      200  {object}  entity.ScheduledChange  "Success"
+// @Failure      400  {object}  errors.DetailError      "Bad Request"
+// @Failure      401  {object}  errors.DetailError      "Unauthorized"
+// @Failure      429  {object}  errors.DetailError      "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError      "Not Found"
+// @Failure      500  {object}  errors.DetailError      "Internal Server Error"
+// @Router       /api/v1/schedule/{id} [get]
+func (h *Handler) GetSchedule(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	// Get scheduled change with repository
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse scheduled change id")
+	}
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to get scheduled change")
+		}
+		return nil, errors.Wrap(err, "failed to get scheduled change with repository")
+	}
+
+	// Return scheduled change
+	return existedEntity, nil
+}
+
+// @Summary      Cancel scheduled change
+// @Description  Cancel a scheduled change which is waiting to be applied, an applied change is reverted at the next scan of the scheduler
+// @Produce      json
+// @Param        id   path      int                 true  "Scheduled change ID"
+// @Success      200  {object}  nil                 "Success"
+// @Failure      400  {object}  errors.DetailError  "Bad Request"
+// @Failure      401  {object}  errors.DetailError  "Unauthorized"
+// @Failure      429  {object}  errors.DetailError  "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError  "Not Found"
+// @Failure      500  {object}  errors.DetailError  "Internal Server Error"
+// @Router       /api/v1/schedule/{id} [delete]
+func (h *Handler) CancelSchedule(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse scheduled change id")
+	}
+
+	// Cancel scheduled change with repository
+	err = h.repo.Cancel(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, errcode.NotFound.Causewf(err, "failed to cancel scheduled change")
+		case errors.Is(err, entity.ErrScheduleNotCancellable):
+			return nil, errcode.InvalidParams.Causewf(err, "failed to cancel scheduled change")
+		}
+		return nil, errors.Wrap(err, "failed to cancel scheduled change with repository")
+	}
+
+	return nil, nil
+}
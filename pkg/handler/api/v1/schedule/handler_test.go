@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/systemconfig"
+	"github.com/elliotxx/go-web-template/pkg/handler/handlertest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/elliotxx/go-web-template/pkg/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	db := handlertest.NewDB(t)
+	changes := persistence.NewScheduledChangeRepository(db)
+	configs := persistence.NewSystemConfigRepository(db)
+	tenants := persistence.NewTenantRepository(db)
+	windows := persistence.NewFreezeWindowRepository(db)
+	s := scheduler.New(persistence.NewTransactionManager(db), changes, configs,
+		persistence.NewSystemConfigRevisionRepository(db), tenants, 0, 0)
+
+	h := NewHandler(changes)
+	configHandler := systemconfig.NewHandler(configs, changes, tenants)
+	engine := handlertest.NewEngine()
+	engine.GET("/schedules", handler.WrapFD(h.ListSchedules))
+	engine.GET("/schedule/:id", handler.WrapFD(h.GetSchedule))
+	engine.DELETE("/schedule/:id", handler.WrapFD(h.CancelSchedule))
+	engine.POST("/systemconfig", handler.WrapFD(configHandler.CreateSystemConfig))
+	engine.PUT("/systemconfig", handler.WrapFD(configHandler.UpdateSystemConfig))
+	engine.GET("/systemconfig/:id", handler.WrapFD(configHandler.GetSystemConfig))
+
+	var config entity.SystemConfig
+	resp := handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "alice",
+		`{"tenant": "MAIN_SITE", "env": "dev", "type": "db", "config": "v1"}`)
+	require.Equal(t, http.StatusOK, resp.Status)
+	resp.Decode(t, &config)
+
+	// schedule submits a change of the config going live now and reverted
+	// in an hour
+	schedule := func(t *testing.T, value string) entity.ScheduledChange {
+		var change entity.ScheduledChange
+		expiresAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "alice",
+			fmt.Sprintf(`{"id": %d, "config": %q, "expiresAt": %q}`, config.ID, value, expiresAt))
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &change)
+		require.Equal(t, entity.ScheduleStatusPending, change.Status)
+		return change
+	}
+	get := func(t *testing.T, id uint) entity.ScheduledChange {
+		var change entity.ScheduledChange
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/schedule/%d", id), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &change)
+		return change
+	}
+	configValue := func(t *testing.T) string {
+		var current entity.SystemConfig
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/systemconfig/%d", config.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &current)
+		return current.Config
+	}
+
+	t.Run("List and get", func(t *testing.T) {
+		change := schedule(t, "v2")
+
+		var listed []entity.ScheduledChange
+		resp := handlertest.Do(t, engine, http.MethodGet, "/schedules", "", `{"page": 1, "perPage": 10}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &listed)
+		require.Len(t, listed, 1)
+		require.Equal(t, change.ID, listed[0].ID)
+		require.Equal(t, "v2", get(t, change.ID).Target.Config)
+
+		resp = handlertest.Do(t, engine, http.MethodGet, "/schedule/100", "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+
+		// Leave nothing pending to the next tests
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/schedule/%d", change.ID), "alice", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+	})
+
+	t.Run("Cancel pending change", func(t *testing.T) {
+		change := schedule(t, "v2")
+		resp := handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/schedule/%d", change.ID), "alice", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Equal(t, entity.ScheduleStatusCancelled, get(t, change.ID).Status)
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configValue(t))
+
+		// Nothing is left to cancel
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/schedule/%d", change.ID), "alice", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.InvalidParams.GetCode(), resp.Code)
+
+		resp = handlertest.Do(t, engine, http.MethodDelete, "/schedule/100", "alice", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+
+	t.Run("Cancel applied change", func(t *testing.T) {
+		change := schedule(t, "v2")
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, entity.ScheduleStatusApplied, get(t, change.ID).Status)
+		require.Equal(t, "v2", configValue(t))
+
+		resp := handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/schedule/%d", change.ID), "alice", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		// The change is reverted by the next scan instead of an hour later
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, entity.ScheduleStatusReverted, get(t, change.ID).Status)
+		require.Equal(t, "v1", configValue(t))
+	})
+
+	t.Run("Defer change during freeze", func(t *testing.T) {
+		window := &entity.FreezeWindow{
+			Name:    "release",
+			Tenant:  "MAIN_SITE",
+			Env:     entity.EnvDev,
+			StartAt: time.Now().Add(-time.Hour),
+			EndAt:   time.Now().Add(time.Hour),
+		}
+		require.NoError(t, windows.Create(context.Background(), window))
+
+		change := schedule(t, "v2")
+		require.NoError(t, s.RunOnce(context.Background()))
+		deferred := get(t, change.ID)
+		require.Equal(t, entity.ScheduleStatusPending, deferred.Status)
+		require.Contains(t, deferred.Message, "deferred")
+		require.Equal(t, "v1", configValue(t))
+
+		// The change goes live once the freeze is over
+		require.NoError(t, windows.Delete(context.Background(), window.ID))
+		require.NoError(t, s.RunOnce(context.Background()))
+		applied := get(t, change.ID)
+		require.Equal(t, entity.ScheduleStatusApplied, applied.Status)
+		require.Empty(t, applied.Message)
+		require.Equal(t, "v2", configValue(t))
+	})
+}
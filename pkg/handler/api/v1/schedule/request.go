@@ -0,0 +1,9 @@
+package schedule
+
+import "github.com/elliotxx/go-web-template/pkg/handler"
+
+// QueryScheduleRequest represents the query request structure for scheduled
+// changes.
+type QueryScheduleRequest struct {
+	handler.Pagination
+}
@@ -3,12 +3,17 @@ package systemconfig
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/elliotxx/errors"
 	"github.com/elliotxx/go-web-template/pkg/domain/entity"
 	"github.com/elliotxx/go-web-template/pkg/domain/repository"
 	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/resolver"
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
 	"github.com/elliotxx/go-web-template/pkg/util/kdump"
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"github.com/sirupsen/logrus"
@@ -16,12 +21,22 @@ import (
 )
 
 type Handler struct {
-	repo repository.SystemConfigRepository
+	repo      repository.SystemConfigRepository
+	schedules repository.ScheduledChangeRepository
+	tenants   repository.TenantRepository
+	resolver  *resolver.Resolver
 }
 
-func NewHandler(repo repository.SystemConfigRepository) *Handler {
+func NewHandler(
+	repo repository.SystemConfigRepository,
+	schedules repository.ScheduledChangeRepository,
+	tenants repository.TenantRepository,
+) *Handler {
 	return &Handler{
-		repo: repo,
+		repo:      repo,
+		schedules: schedules,
+		tenants:   tenants,
+		resolver:  resolver.New(repo),
 	}
 }
 
@@ -29,7 +44,7 @@ func NewHandler(repo repository.SystemConfigRepository) *Handler {
 // @Description  Create a new system config instance
 // @Accept       json
 // @Produce      json
-// @Param        system  config    body                 CreateSystemConfigRequest  true  "Created system config"
+// @Param        config  body      CreateSystemConfigRequest  true  "Created system config"
 // @Success      200     {object}  entity.SystemConfig  "Success"
 // @Failure      400     {object}  errors.DetailError   "Bad Request"
 // @Failure      401     {object}  errors.DetailError   "Unauthorized"
@@ -50,11 +65,28 @@ func (h *Handler) CreateSystemConfig(c *gin.Context, log logrus.FieldLogger) (an
 	if err := copier.Copy(&systemConfig, &requestPayload); err != nil {
 		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
 	}
+	creator, err := handler.RequireOperator(c.Request.Context(), requestPayload.Creator, "creator")
+	if err != nil {
+		return nil, err
+	}
+	systemConfig.Creator = creator
+	systemConfig.Modifier = handler.Operator(c.Request.Context(), requestPayload.Modifier)
+
+	// Reject the references which would form a cycle
+	if err := h.resolver.CheckCycle(c.Request.Context(), &systemConfig); err != nil {
+		return nil, wrapRepositoryError(err, "failed to check references of systemConfig")
+	}
+
+	// Allow the tenant owners to write during a freeze window
+	ctx, err := h.withFreezeOverride(c.Request.Context(), systemConfig.Tenant, requestPayload.FreezeOverrideReason)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create systemConfig with repository
-	err := h.repo.Create(context.TODO(), &systemConfig)
+	err = h.repo.Create(ctx, &systemConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to creating systemConfig with repository")
+		return nil, wrapRepositoryError(err, "failed to creating systemConfig with repository")
 	}
 
 	// Return created systemConfig
@@ -64,13 +96,15 @@ func (h *Handler) CreateSystemConfig(c *gin.Context, log logrus.FieldLogger) (an
 // @Summary      Delete system config
 // @Description  Delete specified system config by ID
 // @Produce      json
-// @Param        id   path      int                  true  "SystemConfig ID"
-// @Success      200  {object}  entity.SystemConfig  "Success"
-// @Failure      400  {object}  errors.DetailError   "Bad Request"
-// @Failure      401  {object}  errors.DetailError   "Unauthorized"
-// @Failure      429  {object}  errors.DetailError   "Too Many Requests"
-// @Failure      404  {object}  errors.DetailError   "Not Found"
-// @Failure      500  {object}  errors.DetailError   "Internal Server Error"
+// @Param        id                    path      int                  true   "SystemConfig ID"
+// @Param        freezeOverrideReason  query     string               false  "Reason to delete the system config during a freeze window"
+// @Param        force                 query     bool                 false  "Delete the system config even if others reference it"
+// @Success      200                   {object}  entity.SystemConfig  "Success"
+// @Failure      400                   {object}  errors.DetailError   "Bad Request"
+// @Failure      401                   {object}  errors.DetailError   "Unauthorized"
+// @Failure      429                   {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404                   {object}  errors.DetailError   "Not Found"
+// @Failure      500                   {object}  errors.DetailError   "Internal Server Error"
 // @Router       /api/v1/systemconfig/{id} [delete]
 func (h *Handler) DeleteSystemConfig(c *gin.Context, log logrus.FieldLogger) (any, error) {
 	// Parse payload from requestPayload
@@ -83,10 +117,37 @@ func (h *Handler) DeleteSystemConfig(c *gin.Context, log logrus.FieldLogger) (an
 		return nil, err
 	}
 
+	ctx := c.Request.Context()
+	existedEntity, err := h.repo.Get(ctx, uint(id))
+	if err != nil {
+		return nil, wrapRepositoryError(err, "failed to get systemConfig with repository")
+	}
+
+	// Refuse to delete a systemConfig referenced by others unless forced
+	if c.Query("force") != "true" {
+		dependents, err := h.repo.FindDependents(ctx, existedEntity.Tenant, existedEntity.Env, existedEntity.Type)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find dependents of systemConfig with repository")
+		}
+		if len(dependents) > 0 {
+			return nil, wrapRepositoryError(
+				errors.Wrapf(entity.ErrConfigReferenced, "referenced by %d system configs", len(dependents)),
+				"failed to deleting systemConfig")
+		}
+	}
+
+	// Allow the tenant owners to delete during a freeze window
+	if reason := c.Query("freezeOverrideReason"); reason != "" {
+		ctx, err = h.withFreezeOverride(ctx, existedEntity.Tenant, reason)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Delete systemConfig with repository
-	err = h.repo.Delete(context.TODO(), uint(id))
+	err = h.repo.Delete(ctx, uint(id))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to deleting systemConfig with repository")
+		return nil, wrapRepositoryError(err, "failed to deleting systemConfig with repository")
 	}
 
 	// Return deleted systemConfig
@@ -94,11 +155,11 @@ func (h *Handler) DeleteSystemConfig(c *gin.Context, log logrus.FieldLogger) (an
 }
 
 // @Summary      Update system config
-// @Description  Update the specified system config
+// @Description  Update the specified system config, the update is scheduled if effectiveAt or expiresAt is set
 // @Accept       json
 // @Produce      json
-// @Param        system  config    body                 UpdateSystemConfigRequest  true  "Updated system config"
-// @Success      200     {object}  entity.SystemConfig  "Success"
+// @Param        config  body      UpdateSystemConfigRequest  true  "Updated system config"
+// @Success      200     {object}  entity.SystemConfig  "Success, or entity.ScheduledChange if the update is scheduled"
 // @Failure      400     {object}  errors.DetailError   "Bad Request"
 // @Failure      401     {object}  errors.DetailError   "Unauthorized"
 // @Failure      429     {object}  errors.DetailError   "Too Many Requests"
@@ -118,9 +179,14 @@ func (h *Handler) UpdateSystemConfig(c *gin.Context, log logrus.FieldLogger) (an
 	if err := copier.Copy(&requestEntity, &requestPayload); err != nil {
 		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
 	}
+	// The authenticated principal is the modifier and can't change the creator
+	if principal := ctxutil.GetPrincipal(c.Request.Context()); principal != "" {
+		requestEntity.Creator = ""
+		requestEntity.Modifier = principal
+	}
 
 	// Get the existed systemConfig by id
-	updatedEntity, err := h.repo.Get(context.TODO(), requestEntity.ID)
+	updatedEntity, err := h.repo.Get(c.Request.Context(), requestEntity.ID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.NotFound.Causewf(err, "failed to update system config")
@@ -131,10 +197,26 @@ func (h *Handler) UpdateSystemConfig(c *gin.Context, log logrus.FieldLogger) (an
 	// Overwrite non-zero values in request entity to existed entity
 	copier.CopyWithOption(updatedEntity, requestEntity, copier.Option{IgnoreEmpty: true})
 
+	// Schedule the update if it doesn't go live immediately
+	if requestPayload.EffectiveAt != nil || requestPayload.ExpiresAt != nil {
+		return h.scheduleUpdate(c.Request.Context(), &requestPayload, &requestEntity, updatedEntity)
+	}
+
+	// Reject the references which would form a cycle
+	if err = h.resolver.CheckCycle(c.Request.Context(), updatedEntity); err != nil {
+		return nil, wrapRepositoryError(err, "failed to check references of systemConfig")
+	}
+
+	// Allow the tenant owners to write during a freeze window
+	ctx, err := h.withFreezeOverride(c.Request.Context(), updatedEntity.Tenant, requestPayload.FreezeOverrideReason)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update systemConfig with repository
-	err = h.repo.Update(context.TODO(), updatedEntity)
+	err = h.repo.Update(ctx, updatedEntity)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to updating systemConfig with repository")
+		return nil, wrapRepositoryError(err, "failed to updating systemConfig with repository")
 	}
 
 	// Return updated systemConfig
@@ -162,7 +244,7 @@ func (h *Handler) GetSystemConfig(c *gin.Context, log logrus.FieldLogger) (any,
 	if err != nil {
 		return nil, err
 	}
-	existedEntity, err := h.repo.Get(context.TODO(), uint(id))
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errcode.NotFound.Causewf(err, "failed to get system config")
@@ -174,6 +256,111 @@ func (h *Handler) GetSystemConfig(c *gin.Context, log logrus.FieldLogger) (any,
 	return existedEntity, nil
 }
 
+// @Summary      Get system config by key
+// @Description  Get system config information by its tenant, env and type
+// @Produce      json
+// @Param        tenant  path      string               true  "Tenant of the system config"
+// @Param        env     path      string               true  "Env of the system config"
+// @Param        type    path      string               true  "Type of the system config"
+// @Success      200     {object}  entity.SystemConfig  "Success"
+// @Failure      400     {object}  errors.DetailError   "Bad Request"
+// @Failure      401     {object}  errors.DetailError   "Unauthorized"
+// @Failure      429     {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError   "Not Found"
+// @Failure      500     {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/systemconfig/by-key/{tenant}/{env}/{type} [get]
+func (h *Handler) GetSystemConfigByKey(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	tenant, env, typ := c.Param("tenant"), entity.Env(c.Param("env")), c.Param("type")
+	log.Infof("Request params key: %s/%s/%s", tenant, env, typ)
+
+	// Get systemConfig with repository
+	existedEntity, err := h.repo.GetByKey(c.Request.Context(), tenant, env, typ)
+	if err != nil {
+		return nil, wrapRepositoryError(err, "failed to get systemConfig with repository")
+	}
+
+	// Return systemConfig
+	return existedEntity, nil
+}
+
+// @Summary      Upsert system config by key
+// @Description  Create or update the system config identified by its tenant, env and type
+// @Accept       json
+// @Produce      json
+// @Param        tenant  path      string               true  "Tenant of the system config"
+// @Param        env     path      string               true  "Env of the system config"
+// @Param        type    path      string               true  "Type of the system config"
+// @Param        config  body      UpsertSystemConfigRequest  true  "Upserted system config"
+// @Success      200     {object}  entity.SystemConfig  "Success"
+// @Failure      400     {object}  errors.DetailError   "Bad Request"
+// @Failure      401     {object}  errors.DetailError   "Unauthorized"
+// @Failure      429     {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError   "Not Found"
+// @Failure      500     {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/systemconfig/by-key/{tenant}/{env}/{type} [put]
+func (h *Handler) UpsertSystemConfigByKey(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	tenant, env, typ := c.Param("tenant"), entity.Env(c.Param("env")), c.Param("type")
+	var requestPayload UpsertSystemConfigRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request params key: %s/%s/%s, payload: %v", tenant, env, typ, kdump.FormatN(requestPayload))
+
+	modifier, err := handler.RequireOperator(c.Request.Context(), requestPayload.Modifier, "modifier")
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the existed systemConfig by key, a missing one is created
+	upsertedEntity, err := h.repo.GetByKey(c.Request.Context(), tenant, env, typ)
+	created := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !created {
+		return nil, errors.Wrap(err, "failed to get systemConfig with repository")
+	}
+	if created {
+		upsertedEntity = &entity.SystemConfig{
+			Tenant:  tenant,
+			Env:     env,
+			Type:    typ,
+			Creator: modifier,
+		}
+	}
+
+	// The content is replaced as a whole, the labels only if present
+	upsertedEntity.Config = requestPayload.Config
+	upsertedEntity.Description = requestPayload.Description
+	upsertedEntity.Modifier = modifier
+	if requestPayload.Labels != nil {
+		upsertedEntity.Labels = requestPayload.Labels
+	}
+
+	// Reject the references which would form a cycle
+	if err = h.resolver.CheckCycle(c.Request.Context(), upsertedEntity); err != nil {
+		return nil, wrapRepositoryError(err, "failed to check references of systemConfig")
+	}
+
+	// Allow the tenant owners to write during a freeze window
+	ctx, err := h.withFreezeOverride(c.Request.Context(), tenant, requestPayload.FreezeOverrideReason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create or update systemConfig with repository
+	if created {
+		err = h.repo.Create(ctx, upsertedEntity)
+	} else {
+		err = h.repo.Update(ctx, upsertedEntity)
+	}
+	if err != nil {
+		return nil, wrapRepositoryError(err, "failed to upserting systemConfig with repository")
+	}
+
+	// Return upserted systemConfig
+	return upsertedEntity, nil
+}
+
 // @Summary      Find system configs
 // @Description  Find system configs with query
 // @Accept       json
@@ -198,11 +385,18 @@ func (h *Handler) FindSystemConfigs(c *gin.Context, log logrus.FieldLogger) (any
 	limit := requestPayload.PerPage
 	offset := (requestPayload.Page - 1) * requestPayload.PerPage
 
+	// Parse the label selector of the query
+	selector, err := labelutil.Parse(requestPayload.LabelSelector)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse label selector")
+	}
+
 	// Find systemConfigs with repository
-	dataEntities, err := h.repo.Find(context.TODO(), repository.Query{
-		Offset:  offset,
-		Limit:   limit,
-		Keyword: requestPayload.Keyword,
+	dataEntities, err := h.repo.Find(c.Request.Context(), repository.Query{
+		Offset:        offset,
+		Limit:         limit,
+		Keyword:       requestPayload.Keyword,
+		LabelSelector: selector,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get all systemConfig with repository")
@@ -215,6 +409,8 @@ func (h *Handler) FindSystemConfigs(c *gin.Context, log logrus.FieldLogger) (any
 // @Summary      Count system configs
 // @Description  Count the total number of system configs
 // @Produce      json
+// @Param        keyword        query     string               false  "Keyword to search for"
+// @Param        labelSelector  query     string               false  "Label selector, e.g. team=payments,region!=us"
 // @Success      200  {object}  entity.SystemConfig  "Success"
 // @Failure      400  {object}  errors.DetailError   "Bad Request"
 // @Failure      401  {object}  errors.DetailError   "Unauthorized"
@@ -223,8 +419,17 @@ func (h *Handler) FindSystemConfigs(c *gin.Context, log logrus.FieldLogger) (any
 // @Failure      500  {object}  errors.DetailError   "Internal Server Error"
 // @Router       /api/v1/systemconfig/count [get]
 func (h *Handler) CountSystemConfigs(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse the label selector of the query
+	selector, err := labelutil.Parse(c.Query("labelSelector"))
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse label selector")
+	}
+
 	// Count systemConfigs with repository
-	total, err := h.repo.Count(context.TODO())
+	total, err := h.repo.Count(c.Request.Context(), repository.Query{
+		Keyword:       c.Query("keyword"),
+		LabelSelector: selector,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to count systemConfig with repository")
 	}
@@ -234,3 +439,175 @@ func (h *Handler) CountSystemConfigs(c *gin.Context, log logrus.FieldLogger) (an
 		Total: total,
 	}, nil
 }
+
+// @Summary      Render system config
+// @Description  Get system config by ID with the references in its content resolved
+// @Produce      json
+// @Param        id   path      int                  true  "SystemConfig ID"
+// @Success      200  {object}  entity.SystemConfig  "Success"
+// @Failure      400  {object}  errors.DetailError   "Bad Request"
+// @Failure      401  {object}  errors.DetailError   "Unauthorized"
+// @Failure      429  {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError   "Not Found"
+// @Failure      500  {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/systemconfig/{id}/render [get]
+func (h *Handler) RenderSystemConfig(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	// Get systemConfig with repository
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, err
+	}
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		return nil, wrapRepositoryError(err, "failed to get systemConfig with repository")
+	}
+
+	// Resolve the references in the content
+	rendered, err := h.resolver.Render(c.Request.Context(), existedEntity)
+	if err != nil {
+		return nil, wrapRepositoryError(err, "failed to render systemConfig")
+	}
+	existedEntity.Config = rendered
+
+	// Return rendered systemConfig
+	return existedEntity, nil
+}
+
+// @Summary      Find system config dependents
+// @Description  Find the system configs which reference the specified system config
+// @Produce      json
+// @Param        id   path      int                  true  "SystemConfig ID"
+// @Success      200  {array}   entity.SystemConfig  "Success"
+// @Failure      400  {object}  errors.DetailError   "Bad Request"
+// @Failure      401  {object}  errors.DetailError   "Unauthorized"
+// @Failure      429  {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError   "Not Found"
+// @Failure      500  {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/systemconfig/{id}/dependents [get]
+func (h *Handler) FindDependents(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	// Get systemConfig with repository
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, err
+	}
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		return nil, wrapRepositoryError(err, "failed to get systemConfig with repository")
+	}
+
+	// Find the systemConfigs referencing it with repository
+	dependents, err := h.repo.FindDependents(c.Request.Context(), existedEntity.Tenant, existedEntity.Env, existedEntity.Type)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find dependents of systemConfig with repository")
+	}
+
+	// Return dependent systemConfigs
+	return dependents, nil
+}
+
+// scheduleUpdate saves the update as a scheduled change which is applied by
+// the scheduler at its effective time.
+func (h *Handler) scheduleUpdate(
+	ctx context.Context,
+	requestPayload *UpdateSystemConfigRequest,
+	target, merged *entity.SystemConfig,
+) (any, error) {
+	// Check the merged system config early, so that an invalid update is
+	// rejected now instead of failing at its effective time
+	if err := merged.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to schedule system config update")
+	}
+
+	change := entity.ScheduledChange{
+		SystemConfigID: target.ID,
+		Target:         target,
+		EffectiveAt:    time.Now(),
+		ExpiresAt:      requestPayload.ExpiresAt,
+		Status:         entity.ScheduleStatusPending,
+		Creator:        handler.Operator(ctx, requestPayload.Modifier),
+	}
+	if requestPayload.EffectiveAt != nil {
+		change.EffectiveAt = *requestPayload.EffectiveAt
+	}
+	if err := change.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to schedule system config update")
+	}
+
+	// Create scheduled change with repository
+	err := h.schedules.Create(ctx, &change)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to creating scheduled change with repository")
+	}
+
+	// Return scheduled change
+	return change, nil
+}
+
+// withFreezeOverride returns a context which allows the repository to write
+// the frozen system configs of the tenant if a reason is given. Only the
+// owners of the tenant are allowed to override a freeze, the operator is the
+// authenticated principal of the request, never a name given by the caller.
+func (h *Handler) withFreezeOverride(ctx context.Context, tenantName, reason string) (context.Context, error) {
+	if reason == "" {
+		return ctx, nil
+	}
+
+	operator := ctxutil.GetPrincipal(ctx)
+	if operator == "" {
+		return nil, errcode.AccessPermissionError.Causef("overriding a freeze requires an authenticated principal")
+	}
+
+	tenant, err := h.tenants.GetByName(ctx, tenantName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.InvalidParams.Causewf(err, "failed to get tenant %q", tenantName)
+		}
+		return nil, errors.Wrap(err, "failed to get tenant with repository")
+	}
+
+	override := &entity.FreezeOverride{
+		Reason:   reason,
+		Operator: operator,
+	}
+	if err = override.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to override freeze")
+	}
+	if !tenant.HasOwner(operator) {
+		return nil, errcode.AccessPermissionError.Causef("%q isn't an owner of tenant %q and can't override a freeze", operator, tenantName)
+	}
+
+	return repository.WithFreezeOverride(ctx, override), nil
+}
+
+// wrapRepositoryError maps the domain errors returned by the repository to
+// the corresponding error codes, other errors are wrapped with the message.
+func wrapRepositoryError(err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errcode.NotFound.Causewf(err, message)
+	case errors.Is(err, entity.ErrEnvNotFound), errors.Is(err, entity.ErrTenantNotFound),
+		errors.Is(err, entity.ErrReferenceCycle), errors.Is(err, entity.ErrReferenceNotResolved),
+		errors.Is(err, entity.ErrConfigReferenced):
+		return errcode.InvalidParams.Causewf(err, message)
+	case errors.Is(err, entity.ErrTenantSuspended):
+		return errcode.TenantSuspended.Causewf(err, message)
+	case errors.Is(err, entity.ErrConfigConflict):
+		return errcode.ConfigConflict.Causewf(err, message)
+	case errors.Is(err, entity.ErrConfigFrozen):
+		return errcode.ConfigFrozen.Causewf(err, message)
+	case errors.Is(err, entity.ErrConfigQuotaExceeded):
+		return errcode.TenantConfigQuotaExceeded.Causewf(err, message)
+	case errors.Is(err, entity.ErrConfigSizeExceeded):
+		return errcode.TenantConfigSizeExceeded.Causewf(err, message)
+	default:
+		return errors.Wrap(err, message)
+	}
+}
@@ -0,0 +1,209 @@
+package systemconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/handlertest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/memory"
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTenants finds the tenants by name, the other methods aren't used.
+type fakeTenants struct {
+	repository.TenantRepository
+	tenants map[string]*entity.Tenant
+}
+
+func (r *fakeTenants) GetByName(ctx context.Context, name string) (*entity.Tenant, error) {
+	return r.tenants[name], nil
+}
+
+func TestWithFreezeOverride(t *testing.T) {
+	h := &Handler{tenants: &fakeTenants{tenants: map[string]*entity.Tenant{
+		"payments": {Name: "payments", Owners: []string{"alice"}},
+	}}}
+
+	t.Run("No reason", func(t *testing.T) {
+		ctx, err := h.withFreezeOverride(context.Background(), "payments", "")
+		require.NoError(t, err)
+		require.Nil(t, repository.FreezeOverrideFrom(ctx))
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		ctx, err := h.withFreezeOverride(ctxutil.WithPrincipal(context.Background(), "alice"), "payments", "hotfix")
+		require.NoError(t, err)
+		override := repository.FreezeOverrideFrom(ctx)
+		require.NotNil(t, override)
+		require.Equal(t, "alice", override.Operator)
+	})
+
+	t.Run("Not an owner", func(t *testing.T) {
+		_, err := h.withFreezeOverride(ctxutil.WithPrincipal(context.Background(), "mallory"), "payments", "hotfix")
+		var e errors.DetailError
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, errcode.AccessPermissionError.GetCode(), e.GetCode())
+	})
+
+	t.Run("No principal", func(t *testing.T) {
+		_, err := h.withFreezeOverride(context.Background(), "payments", "hotfix")
+		var e errors.DetailError
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, errcode.AccessPermissionError.GetCode(), e.GetCode())
+	})
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(memory.NewSystemConfigRepository(), nil, &fakeTenants{tenants: map[string]*entity.Tenant{
+		"payments": {Name: "payments", Owners: []string{"alice"}},
+	}})
+	engine := handlertest.NewEngine()
+	engine.POST("/systemconfig", handler.WrapFD(h.CreateSystemConfig))
+	engine.DELETE("/systemconfig/:id", handler.WrapFD(h.DeleteSystemConfig))
+	engine.PUT("/systemconfig", handler.WrapFD(h.UpdateSystemConfig))
+	engine.GET("/systemconfig/:id", handler.WrapFD(h.GetSystemConfig))
+	engine.GET("/systemconfig/:id/render", handler.WrapFD(h.RenderSystemConfig))
+	engine.GET("/systemconfig/:id/dependents", handler.WrapFD(h.FindDependents))
+	engine.GET("/systemconfig/by-key/:tenant/:env/:type", handler.WrapFD(h.GetSystemConfigByKey))
+	engine.PUT("/systemconfig/by-key/:tenant/:env/:type", handler.WrapFD(h.UpsertSystemConfigByKey))
+	engine.GET("/systemconfigs", handler.WrapFD(h.FindSystemConfigs))
+	engine.GET("/systemconfig/count", handler.WrapFD(h.CountSystemConfigs))
+
+	var db, app entity.SystemConfig
+	t.Run("Create", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "",
+			`{"tenant": "payments", "env": "prod", "type": "db", "config": "{\"host\": \"db.local\"}", "creator": "bob"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &db)
+		require.Equal(t, "bob", db.Creator)
+
+		// The authenticated principal overrides the claimed creator
+		resp = handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "alice",
+			`{"tenant": "payments", "env": "prod", "type": "app", "config": "{\"db\": \"${ref:payments/prod/db#/host}\"}", "creator": "bob", "labels": {"team": "payments"}}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &app)
+		require.Equal(t, "alice", app.Creator)
+		require.Equal(t, "alice", app.Modifier)
+	})
+
+	t.Run("Create without creator", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "",
+			`{"tenant": "payments", "env": "prod", "type": "cache", "config": "{}"}`)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.InvalidParams.GetCode(), resp.Code)
+	})
+
+	t.Run("Create a duplicate key", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "alice",
+			`{"tenant": "payments", "env": "prod", "type": "db", "config": "{}"}`)
+		require.Equal(t, http.StatusConflict, resp.Status)
+		require.Equal(t, errcode.ConfigConflict.GetCode(), resp.Code)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		var got entity.SystemConfig
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/systemconfig/%d", db.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &got)
+		require.Equal(t, db.Config, got.Config)
+
+		resp = handlertest.Do(t, engine, http.MethodGet, "/systemconfig/by-key/payments/prod/app", "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &got)
+		require.Equal(t, app.ID, got.ID)
+
+		resp = handlertest.Do(t, engine, http.MethodGet, "/systemconfig/100", "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		require.Equal(t, errcode.NotFound.GetCode(), resp.Code)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		var updated entity.SystemConfig
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "carol",
+			fmt.Sprintf(`{"id": %d, "description": "the database", "creator": "mallory"}`, db.ID))
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &updated)
+		require.Equal(t, "the database", updated.Description)
+		require.Equal(t, db.Config, updated.Config)
+		require.Equal(t, "bob", updated.Creator)
+		require.Equal(t, "carol", updated.Modifier)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "carol", `{"id": 100, "description": "missing"}`)
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+
+	t.Run("Upsert by key", func(t *testing.T) {
+		var upserted entity.SystemConfig
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig/by-key/payments/gray/db", "alice", `{"config": "{}"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &upserted)
+		require.NotZero(t, upserted.ID)
+		require.Equal(t, "alice", upserted.Creator)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, "/systemconfig/by-key/payments/gray/db", "carol", `{"config": "{\"host\": \"gray\"}"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		var again entity.SystemConfig
+		resp.Decode(t, &again)
+		require.Equal(t, upserted.ID, again.ID)
+		require.Equal(t, "alice", again.Creator)
+		require.Equal(t, "carol", again.Modifier)
+	})
+
+	t.Run("Find and count", func(t *testing.T) {
+		var found []*entity.SystemConfig
+		resp := handlertest.Do(t, engine, http.MethodGet, "/systemconfigs", "", `{"page": 1, "perPage": 10, "labelSelector": "team=payments"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &found)
+		require.Len(t, found, 1)
+		require.Equal(t, app.ID, found[0].ID)
+
+		var count CountSystemConfigResponse
+		resp = handlertest.Do(t, engine, http.MethodGet, "/systemconfig/count", "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &count)
+		require.EqualValues(t, 3, count.Total)
+
+		resp = handlertest.Do(t, engine, http.MethodGet, "/systemconfig/count?labelSelector=team%20in", "", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("Render and dependents", func(t *testing.T) {
+		var rendered entity.SystemConfig
+		resp := handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/systemconfig/%d/render", app.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &rendered)
+		require.JSONEq(t, `{"db": "db.local"}`, rendered.Config)
+
+		var dependents []*entity.SystemConfig
+		resp = handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/systemconfig/%d/dependents", db.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &dependents)
+		require.Len(t, dependents, 1)
+		require.Equal(t, app.ID, dependents[0].ID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		// The referenced system config is only deleted by force
+		resp := handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/systemconfig/%d", db.ID), "", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.InvalidParams.GetCode(), resp.Code)
+
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/systemconfig/%d?force=true", db.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp = handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/systemconfig/%d", db.ID), "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+
+		// Only the owners of the tenant can override a freeze
+		resp = handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/systemconfig/%d?freezeOverrideReason=hotfix", app.ID), "mallory", "")
+		require.Equal(t, http.StatusUnauthorized, resp.Status)
+		require.Equal(t, errcode.AccessPermissionError.GetCode(), resp.Code)
+	})
+}
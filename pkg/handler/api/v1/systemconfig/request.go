@@ -1,6 +1,10 @@
 package systemconfig
 
-import "github.com/elliotxx/go-web-template/pkg/handler"
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/handler"
+)
 
 // CreateSystemConfigRequest represents the create request structure for
 // configuration of a system.
@@ -15,10 +19,19 @@ type CreateSystemConfigRequest struct {
 	Config string `json:"config" binding:"required"`
 	// Description or purpose of the system
 	Description string `json:"description"`
-	// Username or ID of the user who created the system
-	Creator string `json:"creator" binding:"required"`
-	// Username or ID of the user who last modified the system
+	// Arbitrary key/value labels of the system config
+	Labels map[string]string `json:"labels"`
+	// Username or ID of the user who created the system, required unless
+	// the request has an authenticated principal, which overrides it
+	Creator string `json:"creator"`
+	// Username or ID of the user who last modified the system, overridden by the
+	// authenticated principal of the request
 	Modifier string `json:"modifier"`
+	// Reason to write the system config during a freeze window, only the
+	// owners of the tenant authenticated by the X-Principal header can
+	// override a freeze
+	// Optional: true
+	FreezeOverrideReason string `json:"freezeOverrideReason,omitempty"`
 }
 
 // UpdateSystemConfigRequest represents the update request structure for
@@ -36,10 +49,49 @@ type UpdateSystemConfigRequest struct {
 	Config string `json:"config"`
 	// Description or purpose of the system
 	Description string `json:"description"`
-	// Username or ID of the user who created the system
+	// Arbitrary key/value labels of the system config, replaces the
+	// existing labels if present
+	Labels map[string]string `json:"labels"`
+	// Username or ID of the user who created the system, ignored if the
+	// request has an authenticated principal
 	Creator string `json:"creator"`
-	// Username or ID of the user who last modified the system
+	// Username or ID of the user who last modified the system, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+	// Time when the update goes live, the update is scheduled instead of
+	// being applied immediately if either effectiveAt or expiresAt is set
+	// Optional: true
+	EffectiveAt *time.Time `json:"effectiveAt,omitempty"`
+	// Time when the update is reverted
+	// Optional: true
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Reason to write the system config during a freeze window, only the
+	// owners of the tenant authenticated by the X-Principal header can
+	// override a freeze
+	// Optional: true
+	FreezeOverrideReason string `json:"freezeOverrideReason,omitempty"`
+}
+
+// UpsertSystemConfigRequest represents the upsert request structure for
+// configuration of a system, the system config is identified by the
+// tenant, env and type in the path.
+type UpsertSystemConfigRequest struct {
+	// Configuration data in JSON or YAML format
+	Config string `json:"config" binding:"required"`
+	// Description or purpose of the system
+	Description string `json:"description"`
+	// Arbitrary key/value labels of the system config, replaces the
+	// existing labels if present
+	Labels map[string]string `json:"labels"`
+	// Username or ID of the user who writes the system, it's also the
+	// creator if the system config is created. It's required unless the
+	// request has an authenticated principal, which overrides it
 	Modifier string `json:"modifier"`
+	// Reason to write the system config during a freeze window, only the
+	// owners of the tenant authenticated by the X-Principal header can
+	// override a freeze
+	// Optional: true
+	FreezeOverrideReason string `json:"freezeOverrideReason,omitempty"`
 }
 
 // QuerySystemConfigRequest represents the query request structure for
@@ -47,4 +99,8 @@ type UpdateSystemConfigRequest struct {
 type QuerySystemConfigRequest struct {
 	handler.Pagination
 	handler.Search
+	// LabelSelector is a Kubernetes-style label selector, for example
+	// "team=payments,region!=us,tier in (a,b)".
+	// Optional: true
+	LabelSelector string `json:"labelSelector,omitempty"`
 }
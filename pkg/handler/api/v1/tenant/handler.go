@@ -0,0 +1,263 @@
+package tenant
+
+import (
+	"strconv"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/util/kdump"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	repo repository.TenantRepository
+}
+
+func NewHandler(repo repository.TenantRepository) *Handler {
+	return &Handler{
+		repo: repo,
+	}
+}
+
+// @Summary      Create tenant
+// @Description  Create a new tenant with owners and quotas
+// @Accept       json
+// @Produce      json
+// @Param        tenant  body      CreateTenantRequest  true  "Created tenant"
+// @Success      200     {object}  entity.Tenant        "Success"
+// @Failure      400     {object}  errors.DetailError   "Bad Request"
+// @Failure      401     {object}  errors.DetailError   "Unauthorized"
+// @Failure      429     {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError   "Not Found"
+// @Failure      500     {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/tenant [post]
+func (h *Handler) CreateTenant(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	var requestPayload CreateTenantRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Convert request payload to domain model
+	var tenant entity.Tenant
+	if err := copier.Copy(&tenant, &requestPayload); err != nil {
+		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
+	}
+	creator, err := handler.RequireOperator(c.Request.Context(), requestPayload.Creator, "creator")
+	if err != nil {
+		return nil, err
+	}
+	tenant.Creator = creator
+	tenant.Modifier = handler.Operator(c.Request.Context(), requestPayload.Modifier)
+	if tenant.Status == "" {
+		tenant.Status = entity.TenantStatusActive
+	}
+	if err := tenant.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to create tenant")
+	}
+
+	// Create tenant with repository
+	err = h.repo.Create(c.Request.Context(), &tenant)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to creating tenant with repository")
+	}
+
+	// Return created tenant
+	return tenant, nil
+}
+
+// @Summary      Delete tenant
+// @Description  Delete specified tenant by ID, tenants which own system configs can't be deleted
+// @Produce      json
+// @Param        id   path      int                 true  "Tenant ID"
+// @Success      200  {object}  entity.Tenant       "Success"
+// @Failure      400  {object}  errors.DetailError  "Bad Request"
+// @Failure      401  {object}  errors.DetailError  "Unauthorized"
+// @Failure      429  {object}  errors.DetailError  "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError  "Not Found"
+// @Failure      500  {object}  errors.DetailError  "Internal Server Error"
+// @Router       /api/v1/tenant/{id} [delete]
+func (h *Handler) DeleteTenant(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse tenant id")
+	}
+
+	// Delete tenant with repository
+	err = h.repo.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, errcode.NotFound.Causewf(err, "failed to delete tenant")
+		case errors.Is(err, entity.ErrTenantInUse):
+			return nil, errcode.InvalidParams.Causewf(err, "failed to delete tenant")
+		}
+		return nil, errors.Wrap(err, "failed to deleting tenant with repository")
+	}
+
+	// Return deleted tenant
+	return nil, nil
+}
+
+// @Summary      Update tenant
+// @Description  Update the specified tenant, the name of a tenant can't be changed
+// @Accept       json
+// @Produce      json
+// @Param        tenant  body      UpdateTenantRequest  true  "Updated tenant"
+// @Success      200     {object}  entity.Tenant        "Success"
+// @Failure      400     {object}  errors.DetailError   "Bad Request"
+// @Failure      401     {object}  errors.DetailError   "Unauthorized"
+// @Failure      429     {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404     {object}  errors.DetailError   "Not Found"
+// @Failure      500     {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/tenant [put]
+func (h *Handler) UpdateTenant(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	var requestPayload UpdateTenantRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Get the existed tenant by id
+	updatedEntity, err := h.repo.Get(c.Request.Context(), requestPayload.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to update tenant")
+		}
+		return nil, errcode.InvalidParams.Cause(err)
+	}
+
+	// Overwrite the specified values in request to existed entity
+	if requestPayload.DisplayName != "" {
+		updatedEntity.DisplayName = requestPayload.DisplayName
+	}
+	if len(requestPayload.Owners) > 0 {
+		updatedEntity.Owners = requestPayload.Owners
+	}
+	if requestPayload.Status != "" {
+		updatedEntity.Status = entity.TenantStatus(requestPayload.Status)
+	}
+	if requestPayload.Quota != nil {
+		updatedEntity.Quota = entity.TenantQuota(*requestPayload.Quota)
+	}
+	if modifier := handler.Operator(c.Request.Context(), requestPayload.Modifier); modifier != "" {
+		updatedEntity.Modifier = modifier
+	}
+	if err = updatedEntity.Validate(); err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to update tenant")
+	}
+
+	// Update tenant with repository
+	err = h.repo.Update(c.Request.Context(), updatedEntity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to updating tenant with repository")
+	}
+
+	// Return updated tenant
+	return updatedEntity, nil
+}
+
+// @Summary      Get tenant
+// @Description  Get tenant information by tenant ID
+// @Produce      json
+// @Param        id   path      int                 true  "Tenant ID"
+// @Success      200  {object}  entity.Tenant       "Success"
+// @Failure      400  {object}  errors.DetailError  "Bad Request"
+// @Failure      401  {object}  errors.DetailError  "Unauthorized"
+// @Failure      429  {object}  errors.DetailError  "Too Many Requests"
+// @Failure      404  {object}  errors.DetailError  "Not Found"
+// @Failure      500  {object}  errors.DetailError  "Internal Server Error"
+// @Router       /api/v1/tenant/{id} [get]
+func (h *Handler) GetTenant(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	// Get tenant with repository
+	id, err := strconv.Atoi(paramID)
+	if err != nil {
+		return nil, errcode.InvalidParams.Causewf(err, "failed to parse tenant id")
+	}
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.NotFound.Causewf(err, "failed to get tenant")
+		}
+		return nil, errors.Wrap(err, "failed to get tenant with repository")
+	}
+
+	// Return tenant
+	return existedEntity, nil
+}
+
+// @Summary      Find tenants
+// @Description  Find tenants with query
+// @Accept       json
+// @Produce      json
+// @Param        query  body      QueryTenantRequest  true  "query body"
+// @Success      200    {array}   entity.Tenant       "Success"
+// @Failure      400    {object}  errors.DetailError  "Bad Request"
+// @Failure      401    {object}  errors.DetailError  "Unauthorized"
+// @Failure      429    {object}  errors.DetailError  "Too Many Requests"
+// @Failure      404    {object}  errors.DetailError  "Not Found"
+// @Failure      500    {object}  errors.DetailError  "Internal Server Error"
+// @Router       /api/v1/tenants [get]
+func (h *Handler) FindTenants(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	var requestPayload QueryTenantRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Find tenants with repository
+	dataEntities, err := h.repo.Find(c.Request.Context(), repository.Query{
+		Offset:  (requestPayload.Page - 1) * requestPayload.PerPage,
+		Limit:   requestPayload.PerPage,
+		Keyword: requestPayload.Keyword,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find tenants with repository")
+	}
+
+	// Return found tenants
+	return dataEntities, nil
+}
+
+// @Summary      Count tenants
+// @Description  Count the total number of tenants
+// @Produce      json
+// @Param        keyword  query     string               false  "Keyword to search for"
+// @Success      200      {object}  CountTenantResponse  "Success"
+// @Failure      400      {object}  errors.DetailError   "Bad Request"
+// @Failure      401      {object}  errors.DetailError   "Unauthorized"
+// @Failure      429      {object}  errors.DetailError   "Too Many Requests"
+// @Failure      404      {object}  errors.DetailError   "Not Found"
+// @Failure      500      {object}  errors.DetailError   "Internal Server Error"
+// @Router       /api/v1/tenant/count [get]
+func (h *Handler) CountTenants(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Count tenants with repository
+	total, err := h.repo.Count(c.Request.Context(), repository.Query{
+		Keyword: c.Query("keyword"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count tenants with repository")
+	}
+
+	// Return total of tenants
+	return CountTenantResponse{
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package tenant
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/systemconfig"
+	"github.com/elliotxx/go-web-template/pkg/handler/handlertest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	db := handlertest.NewDB(t)
+	tenants := persistence.NewTenantRepository(db)
+	h := NewHandler(tenants)
+	configs := systemconfig.NewHandler(persistence.NewSystemConfigRepository(db), persistence.NewScheduledChangeRepository(db), tenants)
+	engine := handlertest.NewEngine()
+	engine.POST("/tenant", handler.WrapFD(h.CreateTenant))
+	engine.DELETE("/tenant/:id", handler.WrapFD(h.DeleteTenant))
+	engine.PUT("/tenant", handler.WrapFD(h.UpdateTenant))
+	engine.GET("/tenant/:id", handler.WrapFD(h.GetTenant))
+	engine.POST("/systemconfig", handler.WrapFD(configs.CreateSystemConfig))
+	engine.PUT("/systemconfig", handler.WrapFD(configs.UpdateSystemConfig))
+
+	var payments entity.Tenant
+	t.Run("Create", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/tenant", "alice",
+			`{"name": "payments", "owners": ["alice"], "quota": {"maxConfigs": 1, "maxConfigSize": 32}}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &payments)
+		require.Equal(t, entity.TenantStatusActive, payments.Status)
+		require.Equal(t, entity.TenantQuota{MaxConfigs: 1, MaxConfigSize: 32}, payments.Quota)
+		require.Equal(t, "alice", payments.Creator)
+
+		resp = handlertest.Do(t, engine, http.MethodPost, "/tenant", "alice", `{"name": "billing", "owners": []}`)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	var config entity.SystemConfig
+	t.Run("Quota exceeded", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "alice",
+			`{"tenant": "payments", "env": "dev", "type": "db", "config": "{}"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &config)
+
+		resp = handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "alice",
+			`{"tenant": "payments", "env": "dev", "type": "cache", "config": "{}"}`)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.TenantConfigQuotaExceeded.GetCode(), resp.Code)
+	})
+
+	t.Run("Size exceeded", func(t *testing.T) {
+		large := fmt.Sprintf(`{\"host\": \"%s\"}`, strings.Repeat("x", 32))
+		resp := handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "alice",
+			fmt.Sprintf(`{"id": %d, "config": "%s"}`, config.ID, large))
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.TenantConfigSizeExceeded.GetCode(), resp.Code)
+	})
+
+	t.Run("Raise the quota", func(t *testing.T) {
+		var updated entity.Tenant
+		resp := handlertest.Do(t, engine, http.MethodPut, "/tenant", "bob",
+			fmt.Sprintf(`{"id": %d, "quota": {"maxConfigs": 2}}`, payments.ID))
+		require.Equal(t, http.StatusOK, resp.Status)
+		resp.Decode(t, &updated)
+		require.Equal(t, entity.TenantQuota{MaxConfigs: 2}, updated.Quota)
+		require.Equal(t, "bob", updated.Modifier)
+
+		resp = handlertest.Do(t, engine, http.MethodPost, "/systemconfig", "alice",
+			`{"tenant": "payments", "env": "dev", "type": "cache", "config": "{}"}`)
+		require.Equal(t, http.StatusOK, resp.Status)
+	})
+
+	t.Run("Suspended", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodPut, "/tenant", "bob",
+			fmt.Sprintf(`{"id": %d, "status": "suspended"}`, payments.ID))
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		resp = handlertest.Do(t, engine, http.MethodPut, "/systemconfig", "alice",
+			fmt.Sprintf(`{"id": %d, "description": "the database"}`, config.ID))
+		require.Equal(t, http.StatusForbidden, resp.Status)
+		require.Equal(t, errcode.TenantSuspended.GetCode(), resp.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		resp := handlertest.Do(t, engine, http.MethodDelete, fmt.Sprintf("/tenant/%d", payments.ID), "", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, errcode.InvalidParams.GetCode(), resp.Code)
+
+		resp = handlertest.Do(t, engine, http.MethodDelete, "/tenant/100", "", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		resp = handlertest.Do(t, engine, http.MethodGet, fmt.Sprintf("/tenant/%d", payments.ID), "", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+	})
+}
@@ -0,0 +1,58 @@
+package tenant
+
+import "github.com/elliotxx/go-web-template/pkg/handler"
+
+// TenantQuotaRequest represents the quota structure of a tenant request,
+// a zero value means unlimited.
+type TenantQuotaRequest struct {
+	// Maximum number of system configs owned by the tenant
+	MaxConfigs int `json:"maxConfigs" binding:"gte=0"`
+	// Maximum size in bytes of the content of a single system config
+	MaxConfigSize int `json:"maxConfigSize" binding:"gte=0"`
+	// Maximum number of revisions retained for a single system config
+	MaxRevisions int `json:"maxRevisions" binding:"gte=0"`
+}
+
+// CreateTenantRequest represents the create request structure for a tenant.
+type CreateTenantRequest struct {
+	// Name of the tenant referenced by system configs
+	Name string `json:"name" binding:"required,max=32"`
+	// Human readable name of the tenant
+	DisplayName string `json:"displayName"`
+	// Usernames or IDs of the users who own the tenant
+	Owners []string `json:"owners" binding:"required,min=1"`
+	// Status of the tenant, defaults to active
+	Status string `json:"status" binding:"omitempty,oneof=active suspended"`
+	// Resource limits of the tenant
+	Quota TenantQuotaRequest `json:"quota"`
+	// Username or ID of the user who created the tenant, required unless
+	// the request has an authenticated principal, which overrides it
+	Creator string `json:"creator"`
+	// Username or ID of the user who last modified the tenant, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+}
+
+// UpdateTenantRequest represents the update request structure for a tenant.
+// The name of a tenant can't be changed.
+type UpdateTenantRequest struct {
+	// Unique ID of the tenant
+	ID uint `json:"id" binding:"required"`
+	// Human readable name of the tenant
+	DisplayName string `json:"displayName"`
+	// Usernames or IDs of the users who own the tenant
+	Owners []string `json:"owners"`
+	// Status of the tenant, suspended tenants are read-only
+	Status string `json:"status" binding:"omitempty,oneof=active suspended"`
+	// Resource limits of the tenant, replaces the existing quota if present
+	Quota *TenantQuotaRequest `json:"quota"`
+	// Username or ID of the user who last modified the tenant, overridden by the
+	// authenticated principal of the request
+	Modifier string `json:"modifier"`
+}
+
+// QueryTenantRequest represents the query request structure for tenants.
+type QueryTenantRequest struct {
+	handler.Pagination
+	handler.Search
+}
@@ -0,0 +1,6 @@
+package tenant
+
+// CountTenantResponse represents the count response structure for tenants.
+type CountTenantResponse struct {
+	Total int `json:"total"`
+}
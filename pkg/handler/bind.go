@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes why a field of a request is invalid.
+type FieldError struct {
+	// Field is the name of the field in the request, e.g. page or
+	// pagination.perPage, by the tag of its source
+	Field string `json:"field" yaml:"field"`
+	// Rule is the failed rule, e.g. required or gte
+	Rule string `json:"rule" yaml:"rule"`
+	// Message describes the failed rule
+	Message string `json:"message" yaml:"message"`
+}
+
+// FieldErrors are the invalid fields of a request, the response of a
+// request failing with FieldErrors as cause lists them in details.
+type FieldErrors []FieldError
+
+// Error implements the error interface.
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, f := range e {
+		messages[i] = f.Field + " " + f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Bind fills the request from the JSON body, the path parameters, the query
+// string and the headers of the context, in that order, by the json, uri,
+// form and header tags of its fields. The request is then validated by the
+// binding tags of its fields, and by its Validate() error method if any.
+//
+// Example:
+//
+//	type UpdateEnvironmentRequest struct {
+//		ID       uint   `uri:"id" binding:"required"`
+//		Modifier string `json:"modifier" binding:"required"`
+//		Operator string `header:"X-Operator"`
+//	}
+func Bind(c *gin.Context, req any) error {
+	// Decode the JSON body if any, the list requests send it on GET too
+	if c.Request.Body != nil {
+		err := json.NewDecoder(c.Request.Body).Decode(req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return errcode.ErrDeserializedParams.Cause(decodeError(err))
+		}
+	}
+
+	// Only the keys named by the tags are bound, binding.MapFormWithTag
+	// would bind the others to the untagged fields of the same Go name
+	params := taggedValues(req, "uri", func(key string) []string {
+		if value, ok := c.Params.Get(key); ok {
+			return []string{value}
+		}
+		return nil
+	})
+	if err := binding.MapFormWithTag(req, params, "uri"); err != nil {
+		return errcode.InvalidParams.Causewf(err, "failed to bind path parameters")
+	}
+	query := c.Request.URL.Query()
+	if err := binding.MapFormWithTag(req, taggedValues(req, "form", func(key string) []string { return query[key] }), "form"); err != nil {
+		return errcode.InvalidParams.Causewf(err, "failed to bind query parameters")
+	}
+	// The header tags match the headers in any case
+	if err := binding.MapFormWithTag(req, taggedValues(req, "header", c.Request.Header.Values), "header"); err != nil {
+		return errcode.InvalidParams.Causewf(err, "failed to bind headers")
+	}
+
+	return validate(req)
+}
+
+// taggedValues returns the values of the keys named by the tag in the fields
+// of the request, got by the function.
+func taggedValues(req any, tag string, get func(key string) []string) map[string][]string {
+	keys := map[string]bool{}
+	tagKeys(reflect.TypeOf(req), tag, keys, map[reflect.Type]bool{})
+
+	values := make(map[string][]string, len(keys))
+	for key := range keys {
+		if v := get(key); len(v) > 0 {
+			values[key] = v
+		}
+	}
+	return values
+}
+
+// tagKeys adds the names in the tag of the fields of the type to the keys,
+// including the fields of the nested structs.
+func tagKeys(t reflect.Type, tag string, keys map[string]bool, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		switch name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name {
+		case "-":
+		case "":
+			tagKeys(f.Type, tag, keys, seen)
+		default:
+			keys[name] = true
+		}
+	}
+}
+
+// validate checks the binding tags and calls the Validate method of the
+// request.
+func validate(req any) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return errcode.InvalidParams.Cause(fieldErrors(reflect.TypeOf(req), validationErrors))
+		}
+		return errcode.InvalidParams.Cause(err)
+	}
+
+	if v, ok := req.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return errcode.InvalidParams.Cause(err)
+		}
+	}
+
+	return nil
+}
+
+// decodeError converts the type errors of the JSON body to field errors.
+func decodeError(err error) error {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return FieldErrors{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: "must be " + typeError.Type.String(),
+		}}
+	}
+	return errors.Wrap(err, "failed to decode json")
+}
+
+// fieldErrors converts the validation errors of the request type to field
+// errors.
+func fieldErrors(t reflect.Type, validationErrors validator.ValidationErrors) FieldErrors {
+	result := make(FieldErrors, len(validationErrors))
+	for i, e := range validationErrors {
+		result[i] = FieldError{
+			Field:   fieldName(t, e.StructNamespace()),
+			Rule:    e.Tag(),
+			Message: ruleMessage(e),
+		}
+	}
+	return result
+}
+
+// fieldName returns the name of the field of the namespace in the request,
+// e.g. pagination.perPage for Request.Pagination.PerPage. The fields are
+// named by their json, uri, form or header tag, or else by their Go name,
+// and the embedded structs are left out.
+func fieldName(t reflect.Type, namespace string) string {
+	var names []string
+	// The first part of the namespace is the name of the request type
+	for _, part := range strings.Split(namespace, ".")[1:] {
+		// The elements of slices and maps have an index, e.g. Items[0]
+		goName, index, _ := strings.Cut(part, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			names = append(names, part)
+			continue
+		}
+		f, ok := t.FieldByName(goName)
+		if !ok {
+			names = append(names, part)
+			continue
+		}
+
+		t = f.Type
+		if index != "" {
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+		if f.Anonymous {
+			continue
+		}
+		names = append(names, tagName(f)+index)
+	}
+	return strings.Join(names, ".")
+}
+
+// tagName returns the name of the field in its json, uri, form or header
+// tag, or its Go name.
+func tagName(f reflect.StructField) string {
+	for _, key := range []string{"json", "uri", "form", "header"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// ruleMessage describes the failed rule of the validation error.
+func ruleMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "gte", "min":
+		return "must be at least " + e.Param()
+	case "lte", "max":
+		return "must be at most " + e.Param()
+	case "gt":
+		return "must be greater than " + e.Param()
+	case "lt":
+		return "must be less than " + e.Param()
+	case "len":
+		return "must have the length " + e.Param()
+	case "oneof":
+		return "must be one of " + e.Param()
+	default:
+		if e.Param() != "" {
+			return fmt.Sprintf("must satisfy %s=%s", e.Tag(), e.Param())
+		}
+		return "must satisfy " + e.Tag()
+	}
+}
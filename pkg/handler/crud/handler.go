@@ -0,0 +1,337 @@
+// Package crud provides the generic CRUD handlers of the resources stored in
+// a repository.Repository, so that a new resource only defines its entity,
+// its model and its requests.
+package crud
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/util/kdump"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Action is the operation of a request on a resource.
+type Action string
+
+// These constants represent the actions passed to Hooks.Authorize.
+const (
+	ActionCreate Action = "create"
+	ActionDelete Action = "delete"
+	ActionUpdate Action = "update"
+	ActionGet    Action = "get"
+	ActionList   Action = "list"
+)
+
+// Hooks customize the handlers of a resource, the nil hooks are skipped.
+type Hooks[E any] struct {
+	// Authorize is called before the action is taken. The entity is the one
+	// to create, the stored one for delete and get, both the stored one and
+	// the updated one for update, and nil for list. The error is returned as
+	// errcode.AccessPermissionError.
+	Authorize func(c *gin.Context, action Action, entity *E) error
+	// Validate is called before an entity is created or updated, after the
+	// Validate method of the entity if any. The error is returned as
+	// errcode.InvalidParams.
+	Validate func(ctx context.Context, entity *E) error
+}
+
+// Handler handles the CRUD requests of the resource E stored in a
+// repository. C and U are the requests to create and update the resource,
+// their fields are copied to the entity by name, and the empty fields of U
+// are left unchanged.
+//
+// The routes mounted by Mount, and by Register for a resource named "widget",
+// are:
+//
+//	POST   /widget       create a widget from C
+//	DELETE /widget/:id   delete a widget
+//	PUT    /widget/:id   update a widget from U
+//	GET    /widget/:id   get a widget
+//	GET    /widgets      list the widgets of QueryRequest
+//	GET    /widget/count count the widgets matching the keyword query
+//
+// swag can't document generic handlers, so the routes of a resource are
+// documented by hand in its package if needed.
+type Handler[E any, C any, U any] struct {
+	name  string
+	repo  repository.Repository[E]
+	hooks Hooks[E]
+}
+
+// NewHandler creates the handlers of the resource with the name.
+//
+// Example:
+//
+//	widgets := crud.NewHandler[entity.Widget, CreateWidgetRequest, UpdateWidgetRequest](
+//		"widget", persistence.NewRepository[entity.Widget, persistence.WidgetModel](db), crud.Hooks[entity.Widget]{})
+//	widgets.Register(apiv1)
+func NewHandler[E any, C any, U any](name string, repo repository.Repository[E], hooks Hooks[E]) *Handler[E, C, U] {
+	return &Handler[E, C, U]{
+		name:  name,
+		repo:  repo,
+		hooks: hooks,
+	}
+}
+
+// Routes are the handlers of the CRUD routes of a resource, the nil ones
+// aren't mounted.
+type Routes struct {
+	Create gin.HandlerFunc
+	Delete gin.HandlerFunc
+	Update gin.HandlerFunc
+	Get    gin.HandlerFunc
+	List   gin.HandlerFunc
+	Count  gin.HandlerFunc
+}
+
+// Mount mounts the routes of the resource with the name on the router, at
+// the paths listed by Handler.
+//
+// Example:
+//
+//	crud.Mount(apiv1, "environment", environmentHandler.Routes())
+func Mount(r gin.IRoutes, name string, routes Routes) {
+	for _, route := range []struct {
+		method  string
+		path    string
+		handler gin.HandlerFunc
+	}{
+		{http.MethodPost, "/" + name, routes.Create},
+		{http.MethodDelete, "/" + name + "/:id", routes.Delete},
+		{http.MethodPut, "/" + name + "/:id", routes.Update},
+		{http.MethodGet, "/" + name + "/:id", routes.Get},
+		{http.MethodGet, "/" + name + "s", routes.List},
+		{http.MethodGet, "/" + name + "/count", routes.Count},
+	} {
+		if route.handler != nil {
+			r.Handle(route.method, route.path, route.handler)
+		}
+	}
+}
+
+// Routes returns the handlers of the routes of the resource.
+func (h *Handler[E, C, U]) Routes() Routes {
+	return Routes{
+		Create: handler.WrapFD(h.Create),
+		Delete: handler.WrapFD(h.Delete),
+		Update: handler.WrapFD(h.Update),
+		Get:    handler.WrapFD(h.Get),
+		List:   handler.WrapFD(h.List),
+		Count:  handler.WrapFD(h.Count),
+	}
+}
+
+// Register mounts the routes of the resource on the router.
+func (h *Handler[E, C, U]) Register(r gin.IRoutes) {
+	Mount(r, h.name, h.Routes())
+}
+
+// Create creates a resource from the request C.
+func (h *Handler[E, C, U]) Create(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from requestPayload
+	var requestPayload C
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Convert request payload to domain model
+	var dataEntity E
+	if err := copier.Copy(&dataEntity, &requestPayload); err != nil {
+		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
+	}
+	if err := h.check(c, ActionCreate, &dataEntity); err != nil {
+		return nil, err
+	}
+
+	// Create resource with repository
+	err := h.repo.Create(c.Request.Context(), &dataEntity)
+	if err != nil {
+		return nil, h.wrapRepositoryError(err, "failed to create")
+	}
+
+	// Return created resource
+	return dataEntity, nil
+}
+
+// Delete deletes the resource of the ID.
+func (h *Handler[E, C, U]) Delete(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Get the existed resource by id to authorize the deletion
+	id, _, err := h.get(c, log, ActionDelete)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete resource with repository
+	err = h.repo.Delete(c.Request.Context(), id)
+	if err != nil {
+		return nil, h.wrapRepositoryError(err, "failed to delete")
+	}
+
+	// Return deleted resource
+	return nil, nil
+}
+
+// Update updates the resource of the ID from the request U.
+func (h *Handler[E, C, U]) Update(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Get the existed resource by id, the caller must be allowed to update
+	// it before the request changes the fields checked by Authorize
+	id, updatedEntity, err := h.get(c, log, ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse payload from requestPayload
+	var requestPayload U
+	if err = c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	// Overwrite the specified values in request to existed entity
+	if err = copier.CopyWithOption(updatedEntity, &requestPayload, copier.Option{IgnoreEmpty: true}); err != nil {
+		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
+	}
+	// The ID in the path wins over the one in the request
+	if err = copier.Copy(updatedEntity, &struct{ ID uint }{ID: id}); err != nil {
+		return nil, errors.Wrap(err, "failed to convert request payload to domain model")
+	}
+	if err = h.check(c, ActionUpdate, updatedEntity); err != nil {
+		return nil, err
+	}
+
+	// Update resource with repository
+	err = h.repo.Update(c.Request.Context(), updatedEntity)
+	if err != nil {
+		return nil, h.wrapRepositoryError(err, "failed to update")
+	}
+
+	// Return updated resource
+	return updatedEntity, nil
+}
+
+// Get returns the resource of the ID.
+func (h *Handler[E, C, U]) Get(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	_, existedEntity, err := h.get(c, log, ActionGet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the resource
+	return existedEntity, nil
+}
+
+// List returns a page of the resources matching the keyword.
+func (h *Handler[E, C, U]) List(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	// Parse payload from request
+	var requestPayload QueryRequest
+	if err := c.ShouldBindJSON(&requestPayload); err != nil {
+		return nil, errcode.ErrDeserializedParams.Causewf(err, "failed to decode json")
+	}
+	log.Infof("Request payload: %v", kdump.FormatN(requestPayload))
+
+	if err := h.authorize(c, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	// Find resources with repository
+	dataEntities, err := h.repo.Find(c.Request.Context(), repository.Query{
+		Offset:  (requestPayload.Page - 1) * requestPayload.PerPage,
+		Limit:   requestPayload.PerPage,
+		Keyword: requestPayload.Keyword,
+	})
+	if err != nil {
+		return nil, h.wrapRepositoryError(err, "failed to list")
+	}
+
+	// Return the resources
+	return dataEntities, nil
+}
+
+// Count returns the total of the resources matching the keyword of the
+// query string.
+func (h *Handler[E, C, U]) Count(c *gin.Context, log logrus.FieldLogger) (any, error) {
+	if err := h.authorize(c, ActionList, nil); err != nil {
+		return nil, err
+	}
+
+	// Count resources with repository
+	total, err := h.repo.Count(c.Request.Context(), repository.Query{Keyword: c.Query("keyword")})
+	if err != nil {
+		return nil, h.wrapRepositoryError(err, "failed to count")
+	}
+
+	// Return the total
+	return total, nil
+}
+
+// get returns the ID in the path and its resource, and authorizes the
+// action on the resource.
+func (h *Handler[E, C, U]) get(c *gin.Context, log logrus.FieldLogger, action Action) (uint, *E, error) {
+	// Parse payload from request
+	paramID := c.Param("id")
+	log.Infof("Request params id: %s", paramID)
+
+	id, err := strconv.ParseUint(paramID, 10, 0)
+	if err != nil {
+		return 0, nil, errcode.InvalidParams.Causewf(err, "failed to parse %s id", h.name)
+	}
+
+	// Get resource with repository
+	existedEntity, err := h.repo.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		return 0, nil, h.wrapRepositoryError(err, "failed to get")
+	}
+
+	if err = h.authorize(c, action, existedEntity); err != nil {
+		return 0, nil, err
+	}
+
+	return uint(id), existedEntity, nil
+}
+
+// check validates the entity to write and authorizes the action on it.
+func (h *Handler[E, C, U]) check(c *gin.Context, action Action, dataEntity *E) error {
+	if v, ok := any(dataEntity).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return errcode.InvalidParams.Causewf(err, "failed to %s %s", action, h.name)
+		}
+	}
+	if h.hooks.Validate != nil {
+		if err := h.hooks.Validate(c.Request.Context(), dataEntity); err != nil {
+			return errcode.InvalidParams.Causewf(err, "failed to %s %s", action, h.name)
+		}
+	}
+
+	return h.authorize(c, action, dataEntity)
+}
+
+// authorize calls the Authorize hook of the action.
+func (h *Handler[E, C, U]) authorize(c *gin.Context, action Action, dataEntity *E) error {
+	if h.hooks.Authorize == nil {
+		return nil
+	}
+	if err := h.hooks.Authorize(c, action, dataEntity); err != nil {
+		return errcode.AccessPermissionError.Causewf(err, "failed to %s %s", action, h.name)
+	}
+	return nil
+}
+
+// wrapRepositoryError converts the errors of the repository to the error
+// codes of the response.
+func (h *Handler[E, C, U]) wrapRepositoryError(err error, message string) error {
+	message += " " + h.name
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errcode.NotFound.Causewf(err, message)
+	}
+	return errors.Wrap(err, message)
+}
@@ -0,0 +1,178 @@
+package crud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// widget is the entity of the handler tests.
+type widget struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (w *widget) Validate() error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type widgetModel struct {
+	gorm.Model
+	Name        string
+	Description string
+}
+
+func (m *widgetModel) TableName() string {
+	return "widget"
+}
+
+func (m *widgetModel) ToEntity() (*widget, error) {
+	return &widget{ID: m.ID, Name: m.Name, Description: m.Description}, nil
+}
+
+func (m *widgetModel) FromEntity(e *widget) error {
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Description = e.Description
+	return nil
+}
+
+type createWidgetRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type updateWidgetRequest struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	t.Cleanup(func() { persistence.CloseDB(t, db) })
+	require.NoError(t, db.AutoMigrate(&widgetModel{}))
+
+	widgets := persistence.NewRepository[widget, widgetModel](db)
+	engine := gin.New()
+	NewHandler[widget, createWidgetRequest, updateWidgetRequest]("widget", widgets, Hooks[widget]{}).Register(engine)
+	// The guarded widgets can only be updated if they're described as
+	// owned by alice
+	NewHandler[widget, createWidgetRequest, updateWidgetRequest]("widget", widgets, Hooks[widget]{
+		Authorize: func(c *gin.Context, action Action, entity *widget) error {
+			if action == ActionUpdate && entity.Description != "alice" {
+				return errors.New("only alice can update the widget")
+			}
+			return nil
+		},
+	}).Register(engine.Group("/guarded"))
+
+	do := func(method, path, body string) (int, widget) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		var resp struct {
+			Data widget `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Data
+	}
+
+	code, first := do(http.MethodPost, "/widget", `{"name": "first", "description": "the first widget"}`)
+	require.Equal(t, http.StatusOK, code)
+	code, second := do(http.MethodPost, "/widget", `{"name": "second"}`)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("Update the resource of the path", func(t *testing.T) {
+		code, updated := do(http.MethodPut, "/widget/1", `{"name": "renamed"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, widget{ID: first.ID, Name: "renamed", Description: "the first widget"}, updated)
+	})
+
+	t.Run("Keep the path ID over the request ID", func(t *testing.T) {
+		code, updated := do(http.MethodPut, "/widget/1", `{"id": 2, "description": "moved"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, first.ID, updated.ID)
+
+		code, stored := do(http.MethodGet, "/widget/2", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, second, stored)
+	})
+
+	t.Run("Authorize the stored and the updated resource", func(t *testing.T) {
+		code, owned := do(http.MethodPost, "/widget", `{"name": "owned", "description": "alice"}`)
+		require.Equal(t, http.StatusOK, code)
+		code, _ = do(http.MethodPut, fmt.Sprintf("/guarded/widget/%d", owned.ID), `{"name": "renamed"}`)
+		require.Equal(t, http.StatusOK, code)
+
+		// Neither taking over another widget nor giving it away is allowed
+		code, _ = do(http.MethodPut, fmt.Sprintf("/guarded/widget/%d", second.ID), `{"description": "alice"}`)
+		require.Equal(t, http.StatusUnauthorized, code)
+		code, _ = do(http.MethodPut, fmt.Sprintf("/guarded/widget/%d", owned.ID), `{"description": "bob"}`)
+		require.Equal(t, http.StatusUnauthorized, code)
+
+		code, stored := do(http.MethodGet, fmt.Sprintf("/widget/%d", second.ID), "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, second, stored)
+	})
+
+	t.Run("Update a missing resource", func(t *testing.T) {
+		code, _ := do(http.MethodPut, "/widget/100", `{"name": "missing"}`)
+		require.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestMount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	named := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(http.StatusOK, name+" "+c.Param("id")) }
+	}
+	Mount(engine.Group("/api/v1"), "widget", Routes{
+		Create: named("create"),
+		Delete: named("delete"),
+		Update: named("update"),
+		Get:    named("get"),
+		List:   named("list"),
+	})
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodPost, "/api/v1/widget", http.StatusOK, "create "},
+		{http.MethodDelete, "/api/v1/widget/1", http.StatusOK, "delete 1"},
+		{http.MethodPut, "/api/v1/widget/2", http.StatusOK, "update 2"},
+		{http.MethodGet, "/api/v1/widget/3", http.StatusOK, "get 3"},
+		{http.MethodGet, "/api/v1/widgets", http.StatusOK, "list "},
+		// The nil handlers aren't mounted
+		{http.MethodGet, "/api/v1/widget/count", http.StatusOK, "get count"},
+		{http.MethodPut, "/api/v1/widget", http.StatusNotFound, "404 page not found"},
+	} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		require.Equal(t, tc.code, w.Code, tc.path)
+		require.Equal(t, tc.body, w.Body.String(), tc.path)
+	}
+}
@@ -0,0 +1,10 @@
+package crud
+
+import "github.com/elliotxx/go-web-template/pkg/handler"
+
+// QueryRequest represents the query request structure for a page of
+// resources.
+type QueryRequest struct {
+	handler.Pagination
+	handler.Search
+}
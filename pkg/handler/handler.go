@@ -9,13 +9,15 @@ import (
 
 	"github.com/elliotxx/go-web-template/pkg/errcode"
 	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
-	"github.com/elliotxx/errors"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 // The Handler is an abstract of handle request
+//
+// Deprecated: Use HandlerTypedFunc and WrapT, which bind and validate the
+// typed request.
 type Handler interface {
 	Validate(c *gin.Context) error
 	Handle(c *gin.Context, log logrus.FieldLogger) (any, error)
@@ -39,7 +41,7 @@ func WrapF(f HandlerFunc) gin.HandlerFunc {
 		// Create a logger for current request
 		log := getRequestLogger(c, f)
 		// Inject the logger to context
-		c.Request = c.Request.WithContext(ctxutil.WithLogger(log))
+		c.Request = c.Request.WithContext(ctxutil.CtxWithLogger(c.Request.Context(), log))
 
 		// Handle and calculate the cost time for request
 		handleRequest(c, log, f)
@@ -52,7 +54,7 @@ func WrapFD(f HandlerDataFunc) gin.HandlerFunc {
 		// Create a logger for current request
 		log := getRequestDataLogger(c, f)
 		// Inject the logger to context
-		c.Request = c.Request.WithContext(ctxutil.WithLogger(log))
+		c.Request = c.Request.WithContext(ctxutil.CtxWithLogger(c.Request.Context(), log))
 
 		// Handle and calculate the cost time for request
 		handleDataRequest(c, log, f)
@@ -65,7 +67,7 @@ func WrapH(h Handler) gin.HandlerFunc {
 		// Create a logger for current request
 		log := getRequestDataLogger(c, h.Handle)
 		// Inject the logger to context
-		c.Request = c.Request.WithContext(ctxutil.WithLogger(log))
+		c.Request = c.Request.WithContext(ctxutil.CtxWithLogger(c.Request.Context(), log))
 
 		// Logging the request start message
 		loggingStartMsg(log)
@@ -87,38 +89,26 @@ func WrapH(h Handler) gin.HandlerFunc {
 
 // Create a logger for current request
 func getRequestDataLogger(c *gin.Context, f HandlerDataFunc) logrus.FieldLogger {
-	// Get handler name
-	fullName := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
-	fullNames := strings.Split(fullName, "/")
-	var name string
-	if len(fullNames) > 0 {
-		name = fullNames[len(fullNames)-1]
-	} else {
-		name = fullName
-	}
-
-	// Create a logger with fields
-	return logrus.WithFields(
-		logrus.Fields{
-			"traceID": requestid.Get(c),
-			"handler": name,
-		},
-	)
+	return newRequestLogger(c, handlerName(f))
 }
 
 // Create a logger for current request
 func getRequestLogger(c *gin.Context, f HandlerFunc) logrus.FieldLogger {
-	// Get handler name
+	return newRequestLogger(c, handlerName(f))
+}
+
+// Get the name of the handler function, e.g. environment.(*Handler).GetEnvironment-fm
+func handlerName(f any) string {
 	fullName := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
 	fullNames := strings.Split(fullName, "/")
-	var name string
 	if len(fullNames) > 0 {
-		name = fullNames[len(fullNames)-1]
-	} else {
-		name = fullName
+		return fullNames[len(fullNames)-1]
 	}
+	return fullName
+}
 
-	// Create a logger with fields
+// Create a logger with the trace ID and the handler name for current request
+func newRequestLogger(c *gin.Context, name string) logrus.FieldLogger {
 	return logrus.WithFields(
 		logrus.Fields{
 			"traceID": requestid.Get(c),
@@ -141,24 +131,7 @@ func validateRequest(c *gin.Context, log logrus.FieldLogger, h Handler) bool {
 
 	if err != nil {
 		log.Errorf("Failed to validate request: %v", err)
-		response := Response{
-			Success: false,
-			TraceID: requestid.Get(c),
-		}
-		switch e := err.(type) {
-		case errors.DetailError:
-			response.Code = e.GetCode()
-			response.Message = errors.Wrap(e.GetCause(), e.GetMsg()).Error()
-			c.AbortWithStatusJSON(errcode.StatusCode(e), response)
-		case errors.ErrorCode:
-			response.Code = e.GetCode()
-			response.Message = e.GetMsg()
-			c.AbortWithStatusJSON(errcode.StatusCode(e), response)
-		default:
-			response.Code = errcode.InvalidParams.GetCode()
-			response.Message = e.Error()
-			c.AbortWithStatusJSON(http.StatusBadRequest, response)
-		}
+		renderError(c, Response{}, err, errcode.InvalidParams)
 		return false
 	}
 
@@ -193,22 +166,7 @@ func handleDataRequest(c *gin.Context, log logrus.FieldLogger, f HandlerDataFunc
 		c.AbortWithStatusJSON(http.StatusOK, response)
 	} else {
 		log.Errorf("Failed to handle request: %+v", err)
-
-		response.Success = false
-		switch e := err.(type) {
-		case errors.DetailError:
-			response.Code = e.GetCode()
-			response.Message = errors.Wrap(e.GetCause(), e.GetMsg()).Error()
-			c.AbortWithStatusJSON(errcode.StatusCode(e), response)
-		case errors.ErrorCode:
-			response.Code = e.GetCode()
-			response.Message = e.GetMsg()
-			c.AbortWithStatusJSON(errcode.StatusCode(e), response)
-		default:
-			response.Code = errcode.InternalError.GetCode()
-			response.Message = e.Error()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, response)
-		}
+		renderError(c, response, err, errcode.InternalError)
 	}
 }
 
@@ -222,14 +180,7 @@ func handleRequest(c *gin.Context, log logrus.FieldLogger, f HandlerFunc) {
 
 	if err != nil {
 		log.Errorf("Failed to handle request: %+v", err)
-		switch e := err.(type) {
-		case errors.DetailError:
-			c.JSON(errcode.StatusCode(e), gin.H{"code": e.GetCode(), "msg": e.GetMsg(), "cause": e.GetCause().Error()})
-		case errors.ErrorCode:
-			c.JSON(errcode.StatusCode(e), gin.H{"code": e.GetCode(), "msg": e.GetMsg()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"code": errcode.InternalError.GetCode(), "cause": e.Error()})
-		}
+		renderError(c, Response{}, err, errcode.InternalError)
 		return
 	}
 
@@ -0,0 +1,87 @@
+// Package handlertest serves requests through a gin engine for the tests of
+// the handlers, and decodes the responses enveloped by handler.Response.
+package handlertest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/assets"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/migration"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/elliotxx/go-web-template/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// trustedProxy is the prefix of the peer address of the httptest requests,
+// so that the engine trusts the principal of the requests.
+var trustedProxy = netip.MustParsePrefix("192.0.2.1/32")
+
+// Response is a decoded response of the engine, the data is kept raw to be
+// decoded by the test.
+type Response struct {
+	// Status is the HTTP status code of the response.
+	Status  int             `json:"-"`
+	Success bool            `json:"success"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Decode decodes the data of the response into v.
+func (r *Response) Decode(t testing.TB, v any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(r.Data, v), string(r.Data))
+}
+
+// NewEngine returns a gin engine in test mode which keeps the principal of
+// the requests in their context, the handlers are registered by the test.
+func NewEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.RequestContext([]netip.Prefix{trustedProxy}))
+	return engine
+}
+
+// NewDB returns a sqlite database of the test with the migrations applied,
+// so it has the default environments and tenant.
+func NewDB(t *testing.T) *gorm.DB {
+	dsn := filepath.Join(t.TempDir(), "app.db") + "?_pragma=busy_timeout(5000)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard, TranslateError: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { persistence.CloseDB(t, db) })
+
+	migrations, err := migration.Load(assets.Migrations, "migrations/sqlite")
+	require.NoError(t, err)
+	_, err = migration.New(db, migrations).Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+// Do serves the JSON request on the engine and decodes the response. The
+// request is made by the principal unless it's empty.
+func Do(t testing.TB, engine http.Handler, method, path, principal, body string) *Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if principal != "" {
+		req.Header.Set(middleware.PrincipalHeader, principal)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	resp := &Response{Status: w.Code}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp), w.Body.String())
+	return resp
+}
@@ -1,6 +1,9 @@
 package healthz
 
 import (
+	"context"
+
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
 	"github.com/elliotxx/healthcheck/checks"
 	"gorm.io/gorm"
 )
@@ -34,3 +37,26 @@ func (c *gormDBCheck) Pass() bool {
 	sqlCheck := checks.NewSQLCheck(sqldb)
 	return sqlCheck.Pass()
 }
+
+// replicaCheck is a check that pings the read replicas, ejecting the
+// unhealthy ones from serving the read-only queries until they recover.
+type replicaCheck struct {
+	replicas *persistence.ReplicaSet
+}
+
+func NewReplicaCheck(replicas *persistence.ReplicaSet) checks.Check {
+	return &replicaCheck{
+		replicas: replicas,
+	}
+}
+
+func (c *replicaCheck) Name() string {
+	return "Replicas"
+}
+
+// Pass always returns true, as the reads fall back to the primary when no
+// replica is healthy.
+func (c *replicaCheck) Pass() bool {
+	c.replicas.CheckHealth(context.Background())
+	return true
+}
@@ -1,6 +1,7 @@
 package healthz
 
 import (
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
 	"github.com/elliotxx/healthcheck"
 	"github.com/elliotxx/healthcheck/checks"
 	"github.com/gin-gonic/gin"
@@ -32,13 +33,18 @@ func NewLivezHandler() gin.HandlerFunc {
 // NewReadyzHandler creates a new readiness check handler that can be
 // used to check if the application is ready to serve traffic.
 func NewReadyzHandler(db *gorm.DB) gin.HandlerFunc {
+	// checkList is a list of healthcheck to run.
+	checkList := []checks.Check{
+		checks.NewPingCheck(),
+		NewGormDBCheck(db),
+	}
+	if replicas := persistence.ReplicasOf(db); replicas != nil {
+		checkList = append(checkList, NewReplicaCheck(replicas))
+	}
+
 	conf := healthcheck.HandlerConfig{
-		Verbose: true,
-		// checkList is a list of healthcheck to run.
-		Checks: []checks.Check{
-			checks.NewPingCheck(),
-			NewGormDBCheck(db),
-		},
+		Verbose:             true,
+		Checks:              checkList,
 		FailureNotification: healthcheck.FailureNotification{Threshold: 1},
 	}
 
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+)
+
+// Operator returns the user writing the resources of the request, who is
+// recorded as their creator or modifier: the authenticated principal of the
+// request, or the name claimed in the request only if the request has no
+// principal, i.e. it doesn't come from a trusted proxy setting the
+// X-Principal header.
+func Operator(ctx context.Context, claimed string) string {
+	if principal := ctxutil.GetPrincipal(ctx); principal != "" {
+		return principal
+	}
+	return claimed
+}
+
+// RequireOperator returns the Operator of the request, or an InvalidParams
+// error naming the field of the claimed name if there is none.
+func RequireOperator(ctx context.Context, claimed, field string) (string, error) {
+	if operator := Operator(ctx, claimed); operator != "" {
+		return operator, nil
+	}
+	return "", errcode.InvalidParams.Causef("%s is required without an authenticated principal", field)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperator(t *testing.T) {
+	authenticated := ctxutil.WithPrincipal(context.Background(), "alice")
+
+	// The principal overrides the claimed name
+	require.Equal(t, "alice", Operator(authenticated, "mallory"))
+	require.Equal(t, "bob", Operator(context.Background(), "bob"))
+
+	operator, err := RequireOperator(authenticated, "", "creator")
+	require.NoError(t, err)
+	require.Equal(t, "alice", operator)
+	_, err = RequireOperator(context.Background(), "", "creator")
+	require.ErrorContains(t, err, "creator is required")
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of the RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// ErrorFormat is the format of the error responses.
+type ErrorFormat string
+
+const (
+	// ErrorFormatEnvelope answers the errors with the Response of every
+	// request, e.g. {"success": false, "code": "A0400", "message": "..."}
+	ErrorFormatEnvelope ErrorFormat = "envelope"
+	// ErrorFormatProblem answers the errors with the RFC 7807 Problem
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+// ErrorFormats are the supported formats of the error responses.
+var ErrorFormats = []ErrorFormat{ErrorFormatEnvelope, ErrorFormatProblem}
+
+// errorFormatKey keeps the default error format of the server in the gin
+// context.
+const errorFormatKey = "handler.errorFormat"
+
+// ProblemTypeBase is the base of the type URIs of the problems, the type of
+// a problem is the base followed by its lowercase error code.
+var ProblemTypeBase = "https://github.com/elliotxx/go-web-template/blob/master/docs/errcode.md#"
+
+// Problem is the RFC 7807 problem details of a failed request, extended with
+// the error code, the trace ID and the invalid fields of the request.
+type Problem struct {
+	// Type is the URI identifying the error code
+	Type string `json:"type" yaml:"type"`
+	// Title is the message of the error code
+	Title string `json:"title" yaml:"title"`
+	// Status is the HTTP status code
+	Status int `json:"status" yaml:"status"`
+	// Detail explains this occurrence of the problem
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+	// Instance is the path of the failed request
+	Instance string      `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Code     string      `json:"code" yaml:"code"`
+	TraceID  string      `json:"traceID,omitempty" yaml:"traceID,omitempty"`
+	Errors   FieldErrors `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// ProblemType returns the type URI of the error code.
+func ProblemType(code string) string {
+	return ProblemTypeBase + strings.ToLower(code)
+}
+
+// ParseErrorFormat returns the error format of the name.
+func ParseErrorFormat(name string) (ErrorFormat, error) {
+	for _, f := range ErrorFormats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", errors.Errorf("unknown error format %q, must be one of %v", name, ErrorFormats)
+}
+
+// UseErrorFormat returns a gin middleware which sets the default format of
+// the error responses, an empty format is the envelope. The requests
+// preferring application/problem+json are answered with the Problem whatever
+// the default is.
+func UseErrorFormat(format ErrorFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(errorFormatKey, format)
+		c.Next()
+	}
+}
+
+// errorFormat returns the format of the error response of the request. The
+// requests naming application/problem+json in the Accept header are answered
+// with the Problem if its quality is positive and not lower than the one of
+// application/json, and with the envelope otherwise.
+func errorFormat(c *gin.Context) ErrorFormat {
+	if qualities, ok := acceptQuality(c.GetHeader("Accept")); ok {
+		if qualities.problem > 0 && qualities.problem >= qualities.json {
+			return ErrorFormatProblem
+		}
+		return ErrorFormatEnvelope
+	}
+	if format, ok := c.Value(errorFormatKey).(ErrorFormat); ok {
+		return format
+	}
+	return ErrorFormatEnvelope
+}
+
+// acceptQualities are the qualities of the error media types in an Accept
+// header.
+type acceptQualities struct {
+	// problem is the quality of application/problem+json
+	problem float64
+	// json is the quality of application/json, given by its most specific
+	// media range
+	json float64
+}
+
+// acceptQuality returns the qualities of the error media types in the Accept
+// header, it returns false if application/problem+json isn't named.
+func acceptQuality(header string) (acceptQualities, bool) {
+	var (
+		qualities   acceptQualities
+		named       bool
+		specificity = -1
+	)
+	for _, accepted := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(accepted)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case ProblemContentType:
+			qualities.problem, named = quality, true
+		case "application/json":
+			qualities.json, specificity = quality, 2
+		case "application/*":
+			if specificity < 1 {
+				qualities.json, specificity = quality, 1
+			}
+		case "*/*":
+			if specificity < 0 {
+				qualities.json, specificity = quality, 0
+			}
+		}
+	}
+	return qualities, named
+}
+
+// renderError answers the request with the error in the format of the
+// request. The response is the envelope filled so far, e.g. with the start
+// time of the request, and the errors without an error code are reported as
+// the fallback.
+func renderError(c *gin.Context, response Response, err error, fallback errors.ErrorCode) {
+	var (
+		status int
+		title  string
+		detail string
+	)
+	response.Success = false
+	response.TraceID = requestid.Get(c)
+	// The messages of the error codes follow the Accept-Language header
+	lang := c.GetHeader("Accept-Language")
+	switch e := err.(type) {
+	case errors.DetailError:
+		status = errcode.StatusCode(e)
+		title = errcode.LocalizedMsg(e, lang)
+		response.Code = e.GetCode()
+		response.Message = title
+		if e.GetCause() != nil {
+			response.Message = errors.Wrap(e.GetCause(), title).Error()
+			errors.As(e.GetCause(), &response.Details)
+			detail = e.GetCause().Error()
+		}
+	case errors.ErrorCode:
+		status = errcode.StatusCode(e)
+		title = errcode.LocalizedMsg(e, lang)
+		response.Code = e.GetCode()
+		response.Message = title
+	default:
+		status = errcode.StatusCode(fallback)
+		title = errcode.LocalizedMsg(fallback, lang)
+		response.Code = fallback.GetCode()
+		response.Message = e.Error()
+		detail = e.Error()
+	}
+
+	// Tell the client when to send the request again, e.g. when throttled
+	if retryAfter := errcode.RetryAfter(err); retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+	}
+
+	if errorFormat(c) != ErrorFormatProblem {
+		c.AbortWithStatusJSON(status, response)
+		return
+	}
+
+	// The content type set beforehand is kept by the JSON render
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(status, Problem{
+		Type:     ProblemType(response.Code),
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     response.Code,
+		TraceID:  response.TraceID,
+		Errors:   response.Details,
+	})
+}
+
+// RenderError answers the request with the error in the format of the
+// request, the errors without an error code are internal errors. It's used
+// by the middlewares failing the requests before their handlers.
+func RenderError(c *gin.Context, err error) {
+	now := time.Now()
+	renderError(c, Response{StartTime: now, EndTime: now}, err, errcode.InternalError)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newEngine := func(format ErrorFormat) *gin.Engine {
+		engine := gin.New()
+		engine.Use(requestid.New(), UseErrorFormat(format))
+		engine.GET("/item/:id", WrapT(func(c *gin.Context, log logrus.FieldLogger, req *typedRequest) (any, error) {
+			return nil, errcode.NotFound.Causef("item %d is not found", req.ID)
+		}))
+		engine.GET("/fail", WrapF(func(c *gin.Context, log logrus.FieldLogger) error {
+			return errors.New("boom")
+		}))
+		engine.GET("/throttled", WrapF(func(c *gin.Context, log logrus.FieldLogger) error {
+			return errcode.TooManyRequests
+		}))
+		return engine
+	}
+
+	do := func(engine *gin.Engine, path, accept, body string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, path, strings.NewReader(body))
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		req.Header.Set("X-Request-ID", "trace-1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	t.Run("Envelope by default", func(t *testing.T) {
+		w, resp := do(newEngine(""), "/item/3", "", `{"page": 1, "perPage": 1}`)
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Equal(t, false, resp["success"])
+		require.Equal(t, "A0100", resp["code"])
+		require.Equal(t, "trace-1", resp["traceID"])
+
+		w, resp = do(newEngine(ErrorFormatEnvelope), "/fail", "", "")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Equal(t, "B0001", resp["code"])
+		require.Equal(t, "boom", resp["message"])
+	})
+
+	t.Run("Problem by the Accept header", func(t *testing.T) {
+		w, resp := do(newEngine(ErrorFormatEnvelope), "/item/3", "application/json;q=0.9, application/problem+json", `{"page": 1, "perPage": 1}`)
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		require.Equal(t, map[string]any{
+			"type":     ProblemTypeBase + "a0100",
+			"title":    errcode.NotFound.GetMsg(),
+			"status":   404.0,
+			"detail":   "item 3 is not found",
+			"instance": "/item/3",
+			"code":     "A0100",
+			"traceID":  "trace-1",
+		}, resp)
+	})
+
+	t.Run("Negotiate by the quality of the Accept header", func(t *testing.T) {
+		for accept, want := range map[string]string{
+			"application/problem+json":                            ProblemContentType,
+			"application/json, application/problem+json":          ProblemContentType,
+			"*/*, application/problem+json;q=0.5":                 "application/json; charset=utf-8",
+			"application/*;q=0.1, application/problem+json;q=0.5": ProblemContentType,
+			"application/json, application/problem+json;q=0.9":    "application/json; charset=utf-8",
+			"application/problem+json;q=0":                        "application/json; charset=utf-8",
+			"application/problem+json;q=invalid":                  "application/json; charset=utf-8",
+		} {
+			w, _ := do(newEngine(ErrorFormatEnvelope), "/fail", accept, "")
+			require.Equal(t, want, w.Header().Get("Content-Type"), accept)
+		}
+
+		// The Problem isn't answered when the client refuses it
+		w, resp := do(newEngine(ErrorFormatProblem), "/fail", "application/json, application/problem+json;q=0", "")
+		require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Equal(t, "B0001", resp["code"])
+	})
+
+	t.Run("Problem by the server option", func(t *testing.T) {
+		engine := newEngine(ErrorFormatProblem)
+		w, resp := do(engine, "/item/3", "", `{"page": 0, "perPage": 1}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		require.Equal(t, "A0400", resp["code"])
+		require.Equal(t, []any{
+			map[string]any{"field": "page", "rule": "required", "message": "is required"},
+		}, resp["errors"])
+
+		w, resp = do(engine, "/fail", "", "")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Equal(t, errcode.InternalError.GetMsg(), resp["title"])
+		require.Equal(t, "boom", resp["detail"])
+	})
+
+	t.Run("Retry after", func(t *testing.T) {
+		w, resp := do(newEngine(ErrorFormatProblem), "/throttled", "", "")
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "1", w.Header().Get("Retry-After"))
+		require.Equal(t, "A0501", resp["code"])
+	})
+
+	t.Run("Localized messages", func(t *testing.T) {
+		engine := newEngine(ErrorFormatEnvelope)
+		req := httptest.NewRequest(http.MethodGet, "/item/3", strings.NewReader(`{"page": 1, "perPage": 1}`))
+		req.Header.Set("Accept-Language", "en-GB,en;q=0.9")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "Not found: item 3 is not found", resp["message"])
+	})
+
+	t.Run("Parse error formats", func(t *testing.T) {
+		format, err := ParseErrorFormat("problem")
+		require.NoError(t, err)
+		require.Equal(t, ErrorFormatProblem, format)
+		_, err = ParseErrorFormat("xml")
+		require.Error(t, err)
+	})
+}
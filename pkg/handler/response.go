@@ -6,14 +6,15 @@ import (
 )
 
 type Response struct {
-	Success   bool      `json:"success" yaml:"success"`
-	Code      string    `json:"code" yaml:"code"`
-	Message   string    `json:"message" yaml:"message"`
-	Data      any       `json:"data,omitempty" yaml:"data,omitempty"`
-	TraceID   string    `json:"traceID,omitempty" yaml:"traceID,omitempty"`
-	StartTime time.Time `json:"startTime,omitempty" yaml:"startTime,omitempty"`
-	EndTime   time.Time `json:"endTime,omitempty" yaml:"endTime,omitempty"`
-	CostTime  Duration  `json:"costTime,omitempty" yaml:"costTime,omitempty"`
+	Success   bool        `json:"success" yaml:"success"`
+	Code      string      `json:"code" yaml:"code"`
+	Message   string      `json:"message" yaml:"message"`
+	Data      any         `json:"data,omitempty" yaml:"data,omitempty"`
+	Details   FieldErrors `json:"details,omitempty" yaml:"details,omitempty"`
+	TraceID   string      `json:"traceID,omitempty" yaml:"traceID,omitempty"`
+	StartTime time.Time   `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	EndTime   time.Time   `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+	CostTime  Duration    `json:"costTime,omitempty" yaml:"costTime,omitempty"`
 }
 
 type Duration time.Duration
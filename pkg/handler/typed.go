@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/elliotxx/go-web-template/pkg/util/kdump"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// The HandlerTypedFunc type is an adapter to allow the use of ordinary
+// functions with a typed request and response as HTTP handlers. The
+// request is bound and validated by WrapT before the function is called.
+type HandlerTypedFunc[Req any, Resp any] func(c *gin.Context, log logrus.FieldLogger, req *Req) (Resp, error)
+
+// WrapT is a helper function for wrapping handler.HandlerTypedFunc and
+// returns a Gin middleware. The request is bound by Bind, a request failing
+// to bind or validate is answered with the invalid fields in the details of
+// the response, and the response is the data of a successful one.
+//
+// swag can't infer the types of generic functions, so the handlers document
+// the data of their responses, e.g.
+//
+//	// @Success  200  {object}  handler.Response{data=entity.Environment}  "Success"
+//	func (h *Handler) GetEnvironment(c *gin.Context, log logrus.FieldLogger, req *GetEnvironmentRequest) (*entity.Environment, error)
+//
+//	apiv1.GET("/environment/:id", handler.WrapT(environmentHandler.GetEnvironment))
+func WrapT[Req any, Resp any](f HandlerTypedFunc[Req, Resp]) gin.HandlerFunc {
+	name := handlerName(f)
+
+	return func(c *gin.Context) {
+		// Create a logger for current request
+		log := newRequestLogger(c, name)
+		// Inject the logger to context
+		c.Request = c.Request.WithContext(ctxutil.CtxWithLogger(c.Request.Context(), log))
+
+		// Bind, validate and handle the request
+		handleDataRequest(c, log, func(c *gin.Context, log logrus.FieldLogger) (any, error) {
+			var req Req
+			if err := Bind(c, &req); err != nil {
+				return nil, err
+			}
+			log.Infof("Request payload: %v", kdump.FormatN(req))
+
+			return f(c, log, &req)
+		})
+	}
+}
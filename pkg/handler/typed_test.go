@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type typedRequest struct {
+	Pagination
+	ID       uint   `uri:"id" binding:"required"`
+	Verbose  bool   `form:"verbose"`
+	Operator string `header:"X-Operator"`
+	Items    []item `json:"items" binding:"dive"`
+}
+
+func (r *typedRequest) Validate() error {
+	if r.Operator == "nobody" {
+		return errors.New("operator is not allowed")
+	}
+	return nil
+}
+
+type typedResponse struct {
+	ID       uint   `json:"id"`
+	Verbose  bool   `json:"verbose"`
+	Operator string `json:"operator"`
+	Page     int    `json:"page"`
+}
+
+func TestWrapT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/item/:id", WrapT(func(c *gin.Context, log logrus.FieldLogger, req *typedRequest) (*typedResponse, error) {
+		return &typedResponse{ID: req.ID, Verbose: req.Verbose, Operator: req.Operator, Page: req.Page}, nil
+	}))
+
+	do := func(path, operator, body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, path, strings.NewReader(body))
+		if operator != "" {
+			req.Header.Set("x-operator", operator)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	t.Run("Bind the path, query, header and body", func(t *testing.T) {
+		code, resp := do("/item/3?verbose=true", "alice", `{"page": 2, "perPage": 10}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]any{"id": 3.0, "verbose": true, "operator": "alice", "page": 2.0}, resp["data"])
+	})
+
+	t.Run("Field errors", func(t *testing.T) {
+		code, resp := do("/item/3", "", `{"page": 0, "perPage": 1000, "items": [{"name": ""}]}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, "A0400", resp["code"])
+		require.Equal(t, []any{
+			map[string]any{"field": "page", "rule": "required", "message": "is required"},
+			map[string]any{"field": "perPage", "rule": "lte", "message": "must be at most 300"},
+			map[string]any{"field": "items[0].name", "rule": "required", "message": "is required"},
+		}, resp["details"])
+	})
+
+	t.Run("Type errors", func(t *testing.T) {
+		code, resp := do("/item/3", "", `{"page": "one"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, "A0404", resp["code"])
+		require.Equal(t, []any{
+			map[string]any{"field": "page", "rule": "type", "message": "must be int"},
+		}, resp["details"])
+
+		code, resp = do("/item/three", "", `{"page": 1, "perPage": 1}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Nil(t, resp["details"])
+	})
+
+	t.Run("Validate method", func(t *testing.T) {
+		code, resp := do("/item/3", "nobody", `{"page": 1, "perPage": 1}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, resp["message"], "operator is not allowed")
+	})
+}
+
+type bodyRequest struct {
+	ID       uint   `json:"id"`
+	Modifier string `json:"modifier"`
+	Verbose  bool   `form:"verbose"`
+}
+
+func TestBind(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Untagged fields are bound from the body only", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/item?ID=99&Modifier=mallory&verbose=true", strings.NewReader(`{"id": 1, "modifier": "alice"}`))
+		req.Header.Set("ID", "98")
+		req.Header.Set("Modifier", "mallory")
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = req
+		c.Params = gin.Params{{Key: "ID", Value: "97"}, {Key: "Modifier", Value: "mallory"}}
+
+		var bound bodyRequest
+		require.NoError(t, Bind(c, &bound))
+		require.Equal(t, bodyRequest{ID: 1, Modifier: "alice", Verbose: true}, bound)
+	})
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// entry is a cached lookup of a system config, either by its ID or by its
+// tenant, env and type. A nil config caches that the system config was not
+// found. The ID of a found system config is kept in the entries of both
+// lookups, so that they're invalidated together.
+type entry struct {
+	key       string
+	id        uint
+	tenant    string
+	env       entity.Env
+	typ       string
+	config    *entity.SystemConfig
+	expiresAt time.Time
+}
+
+// matches reports whether the entry caches the system config of the ID or
+// of the tenant, env and type. The key is ignored without env, and an empty
+// tenant or type matches any value.
+func (e *entry) matches(id uint, tenant string, env entity.Env, typ string) bool {
+	if id != 0 && e.id == id {
+		return true
+	}
+	return env != "" && e.env == env &&
+		(tenant == "" || e.tenant == tenant) &&
+		(typ == "" || e.typ == typ)
+}
+
+// lru is a bounded map of entries which evicts the least recently used
+// entry when it's full. It's not safe for concurrent use.
+type lru struct {
+	size     int
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:     size,
+		order:    list.New(),
+		elements: map[string]*list.Element{},
+	}
+}
+
+// get returns the entry of the key and marks it as the most recently used.
+func (l *lru) get(key string) (*entry, bool) {
+	element, ok := l.elements[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*entry), true
+}
+
+// add adds or replaces the entry of its key, and returns the number of
+// evicted entries.
+func (l *lru) add(e *entry) int {
+	if element, ok := l.elements[e.key]; ok {
+		element.Value = e
+		l.order.MoveToFront(element)
+		return 0
+	}
+
+	l.elements[e.key] = l.order.PushFront(e)
+
+	evicted := 0
+	for l.order.Len() > l.size {
+		l.remove(l.order.Back())
+		evicted++
+	}
+	return evicted
+}
+
+// removeIf removes the entries matching the function, and returns the
+// number of removed entries.
+func (l *lru) removeIf(match func(e *entry) bool) int {
+	removed := 0
+	for element := l.order.Front(); element != nil; {
+		next := element.Next()
+		if match(element.Value.(*entry)) {
+			l.remove(element)
+			removed++
+		}
+		element = next
+	}
+	return removed
+}
+
+func (l *lru) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.elements, element.Value.(*entry).key)
+}
+
+func (l *lru) len() int {
+	return l.order.Len()
+}
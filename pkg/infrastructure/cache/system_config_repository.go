@@ -0,0 +1,264 @@
+// Package cache provides the caching decorators of the repositories.
+package cache
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultSize is the default maximum number of cached lookups.
+	DefaultSize = 10000
+	// DefaultTTL is the default duration a found system config is cached.
+	DefaultTTL = time.Minute
+	// DefaultNegativeTTL is the default duration a missing system config is
+	// cached.
+	DefaultNegativeTTL = 5 * time.Second
+)
+
+// The SystemConfigRepository type implements the repository.SystemConfigRepository interface.
+// If the SystemConfigRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.SystemConfigRepository = &SystemConfigRepository{}
+
+// SystemConfigRepository caches the system configs read by their ID or by
+// their tenant, env and type in a bounded LRU, and delegates the other
+// operations to the wrapped repository.
+//
+// A cached system config expires after the TTL, and a missing one after the
+// negative TTL. The writes through the repository invalidate the changed
+// system configs at once, the writes of other replicas are invalidated by
+// Watch. A request pinned to the primary database by
+// repository.WithReadPrimary bypasses the cache.
+type SystemConfigRepository struct {
+	next        repository.SystemConfigRepository
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries *lru
+	// generation increases with every invalidation, so that a lookup racing
+	// with a write doesn't cache the system config read before the write
+	generation uint64
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	evictions     atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+// NewSystemConfigRepository wraps the repository with a cache of at most
+// size lookups, a non-positive size, TTL or negative TTL falls back to the
+// default value.
+func NewSystemConfigRepository(next repository.SystemConfigRepository, size int, ttl, negativeTTL time.Duration) *SystemConfigRepository {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if negativeTTL <= 0 {
+		negativeTTL = DefaultNegativeTTL
+	}
+
+	return &SystemConfigRepository{
+		next:        next,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		entries:     newLRU(size),
+	}
+}
+
+// Create saves a system config to the wrapped repository, and drops the
+// cached not found of its key.
+func (r *SystemConfigRepository) Create(ctx context.Context, dataEntity *entity.SystemConfig) error {
+	err := r.next.Create(ctx, dataEntity)
+	if err != nil {
+		return err
+	}
+
+	r.Invalidate(dataEntity.ID, dataEntity.Tenant, dataEntity.Env, dataEntity.Type)
+	return nil
+}
+
+// Delete removes a system config from the wrapped repository, and drops the
+// cached system config.
+func (r *SystemConfigRepository) Delete(ctx context.Context, id uint) error {
+	err := r.next.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	r.Invalidate(id, "", "", "")
+	return nil
+}
+
+// Update updates a system config in the wrapped repository, and drops the
+// cached system config and the cached not found of its new key. The zero
+// tenant or type of the update match any value, as they're left unchanged.
+func (r *SystemConfigRepository) Update(ctx context.Context, dataEntity *entity.SystemConfig) error {
+	err := r.next.Update(ctx, dataEntity)
+	if err != nil {
+		return err
+	}
+
+	r.Invalidate(dataEntity.ID, dataEntity.Tenant, dataEntity.Env, dataEntity.Type)
+	return nil
+}
+
+// Get retrieves a system config by its ID.
+func (r *SystemConfigRepository) Get(ctx context.Context, id uint) (*entity.SystemConfig, error) {
+	key := "id/" + strconv.FormatUint(uint64(id), 10)
+	return r.lookup(ctx, key, &entry{id: id}, func() (*entity.SystemConfig, error) {
+		return r.next.Get(ctx, id)
+	})
+}
+
+// GetByKey retrieves a system config by its tenant, env and type.
+func (r *SystemConfigRepository) GetByKey(ctx context.Context, tenant string, env entity.Env, typ string) (*entity.SystemConfig, error) {
+	key := "key/" + tenant + "/" + string(env) + "/" + typ
+	return r.lookup(ctx, key, &entry{tenant: tenant, env: env, typ: typ}, func() (*entity.SystemConfig, error) {
+		return r.next.GetByKey(ctx, tenant, env, typ)
+	})
+}
+
+// Find returns a list of specified system configs in the wrapped repository.
+func (r *SystemConfigRepository) Find(ctx context.Context, query repository.Query) ([]*entity.SystemConfig, error) {
+	return r.next.Find(ctx, query)
+}
+
+// Count returns the total of system configs matching the query.
+func (r *SystemConfigRepository) Count(ctx context.Context, query repository.Query) (int, error) {
+	return r.next.Count(ctx, query)
+}
+
+// FindDependents returns the system configs whose content references the
+// system config of the given tenant, env and type.
+func (r *SystemConfigRepository) FindDependents(ctx context.Context, tenant string, env entity.Env, typ string) ([]*entity.SystemConfig, error) {
+	return r.next.FindDependents(ctx, tenant, env, typ)
+}
+
+// Invalidate drops the cached lookups of the system config of the ID or of
+// the tenant, env and type. Without env the key is ignored, and an empty
+// tenant or type matches any value.
+func (r *SystemConfigRepository) Invalidate(id uint, tenant string, env entity.Env, typ string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	removed := r.entries.removeIf(func(e *entry) bool {
+		return e.matches(id, tenant, env, typ)
+	})
+	r.invalidations.Add(uint64(removed))
+}
+
+// Purge drops all cached lookups.
+func (r *SystemConfigRepository) Purge() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.invalidations.Add(uint64(r.entries.removeIf(func(*entry) bool { return true })))
+}
+
+// Stats returns the counters of the cache.
+func (r *SystemConfigRepository) Stats() map[string]any {
+	r.mu.Lock()
+	size := r.entries.len()
+	r.mu.Unlock()
+
+	return map[string]any{
+		"size":          size,
+		"hits":          r.hits.Load(),
+		"misses":        r.misses.Load(),
+		"evictions":     r.evictions.Load(),
+		"invalidations": r.invalidations.Load(),
+	}
+}
+
+// Publish exposes the counters of the cache as the expvar of the name,
+// unless the name is already published.
+func (r *SystemConfigRepository) Publish(name string) {
+	if expvar.Get(name) != nil {
+		return
+	}
+	expvar.Publish(name, expvar.Func(func() any {
+		return r.Stats()
+	}))
+}
+
+// lookup returns the cached system config of the key, or loads it and
+// caches the result. Only the not found errors are cached, the template is
+// the entry to cache without its config.
+func (r *SystemConfigRepository) lookup(
+	ctx context.Context,
+	key string,
+	template *entry,
+	load func() (*entity.SystemConfig, error),
+) (*entity.SystemConfig, error) {
+	if repository.ReadPrimaryFrom(ctx) {
+		return load()
+	}
+
+	r.mu.Lock()
+	cached, ok := r.entries.get(key)
+	generation := r.generation
+	r.mu.Unlock()
+
+	if ok && r.now().Before(cached.expiresAt) {
+		r.hits.Add(1)
+		if cached.config == nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return clone(cached.config), nil
+	}
+	r.misses.Add(1)
+
+	config, err := load()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	e := *template
+	e.key = key
+	e.expiresAt = r.now().Add(r.negativeTTL)
+	if config != nil {
+		e.id = config.ID
+		e.tenant = config.Tenant
+		e.env = config.Env
+		e.typ = config.Type
+		e.config = clone(config)
+		e.expiresAt = r.now().Add(r.ttl)
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.evictions.Add(uint64(r.entries.add(&e)))
+	}
+	r.mu.Unlock()
+
+	return config, err
+}
+
+// clone returns a copy of the system config which doesn't share the labels.
+func clone(config *entity.SystemConfig) *entity.SystemConfig {
+	copied := *config
+	if config.Labels != nil {
+		copied.Labels = make(map[string]string, len(config.Labels))
+		for k, v := range config.Labels {
+			copied.Labels[k] = v
+		}
+	}
+	return &copied
+}
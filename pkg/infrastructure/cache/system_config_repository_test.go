@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/assets"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository/repositorytest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/memory"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/migration"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// countingRepository counts the reads of the wrapped repository.
+type countingRepository struct {
+	repository.SystemConfigRepository
+	reads int
+}
+
+func (r *countingRepository) Get(ctx context.Context, id uint) (*entity.SystemConfig, error) {
+	r.reads++
+	return r.SystemConfigRepository.Get(ctx, id)
+}
+
+func (r *countingRepository) GetByKey(ctx context.Context, tenant string, env entity.Env, typ string) (*entity.SystemConfig, error) {
+	r.reads++
+	return r.SystemConfigRepository.GetByKey(ctx, tenant, env, typ)
+}
+
+// changeLog is a repository.SystemConfigChangeRepository in memory.
+type changeLog struct {
+	mu      sync.Mutex
+	changes []*entity.SystemConfigChange
+}
+
+func (l *changeLog) record(config *entity.SystemConfig) {
+	latest, _ := l.LatestSeq(context.Background())
+	l.commit(latest+1, config)
+}
+
+// commit records a change with the sequence number, which may be lower than
+// the latest one like a change committed after a later change.
+func (l *changeLog) commit(seq uint64, config *entity.SystemConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changes = append(l.changes, &entity.SystemConfigChange{
+		Seq:            seq,
+		SystemConfigID: config.ID,
+		Tenant:         config.Tenant,
+		Env:            config.Env,
+		Type:           config.Type,
+		ChangedAt:      time.Now(),
+	})
+	sort.Slice(l.changes, func(i, j int) bool { return l.changes[i].Seq < l.changes[j].Seq })
+}
+
+func (l *changeLog) FindSince(_ context.Context, seq uint64, limit int) ([]*entity.SystemConfigChange, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var found []*entity.SystemConfigChange
+	for _, change := range l.changes {
+		if change.Seq > seq && len(found) < limit {
+			found = append(found, change)
+		}
+	}
+	return found, nil
+}
+
+func (l *changeLog) LatestSeq(_ context.Context) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.changes) == 0 {
+		return 0, nil
+	}
+	return l.changes[len(l.changes)-1].Seq, nil
+}
+
+func (l *changeLog) DeleteBefore(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+func newConfig(typ string) *entity.SystemConfig {
+	return &entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: typ, Config: "{}", Labels: map[string]string{"team": "payments"}}
+}
+
+// TestSystemConfigSuite runs the conformance suite through the cache, so
+// that the cached reads behave like the ones of the wrapped repository.
+func TestSystemConfigSuite(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		repositorytest.RunSystemConfigSuite(t, func(t *testing.T) repository.SystemConfigRepository {
+			dsn := filepath.Join(t.TempDir(), "app.db") + "?_pragma=busy_timeout(5000)"
+			db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard, TranslateError: true})
+			require.NoError(t, err)
+			t.Cleanup(func() { persistence.CloseDB(t, db) })
+
+			migrations, err := migration.Load(assets.Migrations, "migrations/sqlite")
+			require.NoError(t, err)
+			_, err = migration.New(db, migrations).Up(context.Background())
+			require.NoError(t, err)
+
+			return NewSystemConfigRepository(persistence.NewSystemConfigRepository(db), 100, time.Minute, time.Minute)
+		})
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		repositorytest.RunSystemConfigSuite(t, func(t *testing.T) repository.SystemConfigRepository {
+			return NewSystemConfigRepository(memory.NewSystemConfigRepository(), 100, time.Minute, time.Minute)
+		})
+	})
+}
+
+func TestSystemConfigRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Cache found system configs", func(t *testing.T) {
+		next := &countingRepository{SystemConfigRepository: memory.NewSystemConfigRepository()}
+		repo := NewSystemConfigRepository(next, 10, time.Minute, time.Minute)
+		config := newConfig("redis")
+		require.NoError(t, repo.Create(ctx, config))
+
+		actual, err := repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		actual.Labels["team"] = "search"
+		actual, err = repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, "payments", actual.Labels["team"])
+		_, err = repo.GetByKey(ctx, "MAIN_SITE", entity.EnvProd, "redis")
+		require.NoError(t, err)
+		require.Equal(t, 2, next.reads)
+		require.Equal(t, map[string]any{"size": 2, "hits": uint64(1), "misses": uint64(2), "evictions": uint64(0), "invalidations": uint64(0)}, repo.Stats())
+
+		// A request pinned to the primary reads through
+		_, err = repo.Get(repository.WithReadPrimary(ctx), config.ID)
+		require.NoError(t, err)
+		require.Equal(t, 3, next.reads)
+	})
+
+	t.Run("Cache missing system configs", func(t *testing.T) {
+		next := &countingRepository{SystemConfigRepository: memory.NewSystemConfigRepository()}
+		repo := NewSystemConfigRepository(next, 10, time.Minute, time.Minute)
+		for i := 0; i < 2; i++ {
+			_, err := repo.GetByKey(ctx, "MAIN_SITE", entity.EnvProd, "redis")
+			require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		}
+		require.Equal(t, 1, next.reads)
+
+		// Creating the system config drops the cached not found
+		require.NoError(t, repo.Create(ctx, newConfig("redis")))
+		_, err := repo.GetByKey(ctx, "MAIN_SITE", entity.EnvProd, "redis")
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalidate on writes", func(t *testing.T) {
+		repo := NewSystemConfigRepository(memory.NewSystemConfigRepository(), 10, time.Minute, time.Minute)
+		config := newConfig("redis")
+		require.NoError(t, repo.Create(ctx, config))
+		_, err := repo.GetByKey(ctx, "MAIN_SITE", entity.EnvGray, "redis")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+
+		// Moving the system config drops both keys
+		require.NoError(t, repo.Update(ctx, &entity.SystemConfig{ID: config.ID, Env: entity.EnvGray, Config: `{"db": 3}`}))
+		actual, err := repo.GetByKey(ctx, "MAIN_SITE", entity.EnvGray, "redis")
+		require.NoError(t, err)
+		require.Equal(t, `{"db": 3}`, actual.Config)
+
+		require.NoError(t, repo.Delete(ctx, config.ID))
+		_, err = repo.Get(ctx, config.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.GetByKey(ctx, "MAIN_SITE", entity.EnvGray, "redis")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Evict the least recently used", func(t *testing.T) {
+		next := &countingRepository{SystemConfigRepository: memory.NewSystemConfigRepository()}
+		repo := NewSystemConfigRepository(next, 2, time.Minute, time.Minute)
+		for _, id := range []uint{1, 2, 1, 3, 1} {
+			_, _ = repo.Get(ctx, id)
+		}
+		require.Equal(t, 3, next.reads)
+		require.Equal(t, uint64(1), repo.Stats()["evictions"])
+	})
+
+	t.Run("Expire after the TTL", func(t *testing.T) {
+		next := &countingRepository{SystemConfigRepository: memory.NewSystemConfigRepository()}
+		repo := NewSystemConfigRepository(next, 10, time.Minute, time.Second)
+		now := time.Now()
+		repo.now = func() time.Time { return now }
+		config := newConfig("redis")
+		require.NoError(t, next.Create(ctx, config))
+
+		_, _ = repo.Get(ctx, config.ID)
+		_, _ = repo.Get(ctx, 100)
+		now = now.Add(2 * time.Second)
+		_, _ = repo.Get(ctx, config.ID)
+		_, _ = repo.Get(ctx, 100)
+		require.Equal(t, 3, next.reads)
+	})
+
+	t.Run("Watch the changes of other replicas", func(t *testing.T) {
+		shared := memory.NewSystemConfigRepository()
+		changes := &changeLog{}
+		local := NewSystemConfigRepository(shared, 10, time.Hour, time.Hour)
+		config := newConfig("redis")
+		require.NoError(t, shared.Create(ctx, config))
+		_, err := local.Get(ctx, config.ID)
+		require.NoError(t, err)
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go local.Watch(watchCtx, changes, 10*time.Millisecond)
+
+		// Another replica updates the system config
+		config.Config = `{"db": 3}`
+		require.NoError(t, shared.Update(ctx, config))
+		changes.record(config)
+		require.Eventually(t, func() bool {
+			actual, err := local.Get(ctx, config.ID)
+			return err == nil && actual.Config == `{"db": 3}`
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Poll changes committed out of order", func(t *testing.T) {
+		next := &countingRepository{SystemConfigRepository: memory.NewSystemConfigRepository()}
+		repo := NewSystemConfigRepository(next, 10, time.Hour, time.Hour)
+		now := time.Now()
+		repo.now = func() time.Time { return now }
+		redis, mysql := newConfig("redis"), newConfig("mysql")
+		require.NoError(t, next.Create(ctx, redis))
+		require.NoError(t, next.Create(ctx, mysql))
+
+		// The change 2 isn't committed yet when the change 3 is polled
+		changes := &changeLog{}
+		changes.commit(1, redis)
+		changes.commit(3, redis)
+		cur := newCursor(0)
+		require.NoError(t, repo.poll(ctx, changes, cur))
+		require.Equal(t, uint64(1), cur.from())
+
+		_, _ = repo.Get(ctx, mysql.ID)
+		_, _ = repo.Get(ctx, mysql.ID)
+		require.Equal(t, 1, next.reads)
+
+		changes.commit(2, mysql)
+		require.NoError(t, repo.poll(ctx, changes, cur))
+		require.Equal(t, uint64(3), cur.from())
+		_, _ = repo.Get(ctx, mysql.ID)
+		require.Equal(t, 2, next.reads)
+
+		// The polled changes aren't invalidated again
+		require.NoError(t, repo.poll(ctx, changes, cur))
+		_, _ = repo.Get(ctx, mysql.ID)
+		require.Equal(t, 2, next.reads)
+
+		// A gap which never shows up is given up
+		changes.commit(5, redis)
+		require.NoError(t, repo.poll(ctx, changes, cur))
+		require.Equal(t, uint64(3), cur.from())
+		now = now.Add(2 * gapTimeout)
+		require.NoError(t, repo.poll(ctx, changes, cur))
+		require.Equal(t, uint64(5), cur.from())
+	})
+
+	t.Run("Publish", func(t *testing.T) {
+		repo := NewSystemConfigRepository(memory.NewSystemConfigRepository(), 10, time.Minute, time.Minute)
+		repo.Publish("testSystemConfigCache")
+		repo.Publish("testSystemConfigCache")
+		require.Contains(t, expvar.Get("testSystemConfigCache").String(), `"hits":0`)
+	})
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPollInterval is the default interval between two polls of the
+	// system config changes.
+	DefaultPollInterval = 2 * time.Second
+	// changeRetention is how long the changes are kept, a replica which
+	// hasn't polled for longer drops its whole cache.
+	changeRetention = time.Hour
+	// pruneEvery is the number of polls between two prunes of the changes.
+	pruneEvery = 100
+	// changeBatchSize is the maximum number of changes read in a poll.
+	changeBatchSize = 500
+	// gapTimeout is how long a skipped sequence number is polled again
+	// before it's given up, e.g. the transaction of its change was rolled
+	// back.
+	gapTimeout = time.Minute
+	// maxGaps is the maximum number of skipped sequence numbers tracked.
+	maxGaps = 1000
+)
+
+// Watch polls the changes of system configs made by every replica and
+// invalidates the changed system configs, until the context is done. The
+// changes older than an hour are pruned from time to time. A non-positive
+// interval falls back to the default value.
+func (r *SystemConfigRepository) Watch(ctx context.Context, changes repository.SystemConfigChangeRepository, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	log := logrus.WithField("component", "cache")
+	log.Infof("System config cache started, polling changes every %s", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		cur        *cursor
+		ready      bool
+		lastPolled time.Time
+		polls      int
+	)
+	for {
+		// The changes missed for longer than the retention may have been
+		// pruned, so polling starts over
+		if ready && r.now().Sub(lastPolled) > changeRetention {
+			log.Warnf("System config changes not polled since %s, purging the cache", lastPolled)
+			ready = false
+		}
+
+		var err error
+		if !ready {
+			// Polling starts from the latest change, the lookups cached
+			// before it may have missed earlier changes
+			var latest uint64
+			latest, err = changes.LatestSeq(ctx)
+			if ready = err == nil; ready {
+				cur = newCursor(latest)
+				r.Purge()
+			}
+		} else {
+			err = r.poll(ctx, changes, cur)
+		}
+		if err != nil {
+			log.Errorf("Failed to poll system config changes: %v", err)
+		} else {
+			lastPolled = r.now()
+		}
+
+		polls++
+		if polls%pruneEvery == 0 {
+			pruned, err := changes.DeleteBefore(ctx, r.now().Add(-changeRetention))
+			if err != nil {
+				log.Errorf("Failed to prune system config changes: %v", err)
+			} else if pruned > 0 {
+				log.Debugf("Pruned %d system config changes", pruned)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("System config cache stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll invalidates the system configs changed after the cursor, and moves
+// the cursor to the latest change.
+func (r *SystemConfigRepository) poll(ctx context.Context, changes repository.SystemConfigChangeRepository, cur *cursor) error {
+	seq := cur.from()
+	for {
+		found, err := changes.FindSince(ctx, seq, changeBatchSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find changes since %d", seq)
+		}
+
+		now := r.now()
+		for _, change := range found {
+			if cur.observe(change.Seq, now) {
+				r.Invalidate(change.SystemConfigID, change.Tenant, change.Env, change.Type)
+			}
+			seq = change.Seq
+		}
+
+		if len(found) < changeBatchSize {
+			cur.expire(now.Add(-gapTimeout))
+			return nil
+		}
+	}
+}
+
+// cursor is the position of a replica in the changes of system configs.
+//
+// The sequence number of a change is allocated when it's inserted, but the
+// change is visible only once its transaction commits, so a change may
+// show up after the ones with greater numbers. The numbers skipped by a
+// poll are kept as gaps and polled again until their changes show up or
+// they time out.
+type cursor struct {
+	// seq is the sequence number of the latest polled change.
+	seq uint64
+	// gaps are the skipped sequence numbers and when they were skipped.
+	gaps map[uint64]time.Time
+}
+
+// newCursor creates a cursor positioned at the sequence number.
+func newCursor(seq uint64) *cursor {
+	return &cursor{seq: seq, gaps: map[uint64]time.Time{}}
+}
+
+// from returns the sequence number to poll the changes after.
+func (c *cursor) from() uint64 {
+	from := c.seq
+	for gap := range c.gaps {
+		if gap <= from {
+			from = gap - 1
+		}
+	}
+	return from
+}
+
+// observe moves the cursor to the polled change, and returns false if the
+// change has been polled before.
+func (c *cursor) observe(seq uint64, now time.Time) bool {
+	if seq <= c.seq {
+		if _, ok := c.gaps[seq]; !ok {
+			return false
+		}
+		delete(c.gaps, seq)
+		return true
+	}
+
+	// Only the numbers within maxGaps before the latest change are tracked,
+	// which bounds the gaps after a jump of the sequence
+	gap := c.seq + 1
+	if seq-gap > maxGaps {
+		gap = seq - maxGaps
+	}
+	for ; gap < seq; gap++ {
+		c.gaps[gap] = now
+	}
+	for gap := range c.gaps {
+		if gap+maxGaps < seq {
+			delete(c.gaps, gap)
+		}
+	}
+	c.seq = seq
+	return true
+}
+
+// expire gives up the gaps skipped before the time.
+func (c *cursor) expire(before time.Time) {
+	for gap, skippedAt := range c.gaps {
+		if skippedAt.Before(before) {
+			delete(c.gaps, gap)
+		}
+	}
+}
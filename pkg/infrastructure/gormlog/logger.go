@@ -0,0 +1,225 @@
+package gormlog
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The Logger type implements the gorm logger.Interface and the gorm.Plugin
+// interface.
+var (
+	_ logger.Interface = &Logger{}
+	_ gorm.Plugin      = &Logger{}
+)
+
+// statementKey is the context key of the SQL of the running statement.
+type statementKey struct{}
+
+// statement is the SQL of a statement with the placeholders of its bound
+// parameters, recorded by the callbacks of the Logger.
+type statement struct {
+	sql string
+}
+
+// levels are the names of the SQL log levels.
+var levels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// ParseLevel returns the SQL log level of the name, one of silent, error,
+// warn and info.
+func ParseLevel(name string) (logger.LogLevel, error) {
+	level, ok := levels[strings.ToLower(name)]
+	if !ok {
+		return 0, errors.Errorf("invalid SQL log level %q, must be one of silent, error, warn and info", name)
+	}
+	return level, nil
+}
+
+// Logger is a gorm logger writing through the logger of the context, so
+// that the SQL logs carry the traceID of the request. The bound parameters
+// are left out of the SQL, as they may hold sensitive config content.
+//
+// gorm hands the SQL to Trace with the parameters already rendered in it,
+// so the Logger is also a plugin of the database recording the SQL of every
+// statement before it's rendered. The statements which weren't recorded,
+// e.g. when the plugin isn't registered, are logged without their SQL.
+//
+// The level decides which SQL logs are written regardless of the logrus
+// level: failed statements at error, the statements slower than the
+// threshold at warn, and every statement at info. They're written by a
+// logger of their own sharing the output, formatter and hooks of the logger
+// of the context, which would drop them below its level.
+type Logger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// New creates a new Logger of the level, the statements slower than the
+// threshold are logged at warn level, a zero threshold disables it.
+func New(level logger.LogLevel, slowThreshold time.Duration) *Logger {
+	return &Logger{
+		level:         level,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// LogMode returns a copy of the logger with the level.
+func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
+	newLogger := *l
+	newLogger.level = level
+	return &newLogger
+}
+
+// Info logs the message at info level.
+func (l *Logger) Info(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Info {
+		sqlLogger(ctx).Infof(msg, data...)
+	}
+}
+
+// Warn logs the message at warn level.
+func (l *Logger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Warn {
+		sqlLogger(ctx).Warnf(msg, data...)
+	}
+}
+
+// Error logs the message at error level.
+func (l *Logger) Error(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Error {
+		sqlLogger(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace logs the executed statement with its cost time and affected rows.
+// Not finding a record is an expected result rather than a failure.
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	fields := func() logrus.Fields {
+		// The SQL rendered by fc holds the parameters, only its rows are used
+		_, rows := fc()
+		fields := logrus.Fields{
+			"rows":    rows,
+			"elapsed": elapsed.String(),
+			"source":  source(),
+		}
+		if stmt, ok := ctx.Value(statementKey{}).(*statement); ok {
+			fields["sql"] = stmt.sql
+		}
+		return fields
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		sqlLogger(ctx).WithFields(fields()).WithError(err).Error("SQL failed")
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		sqlLogger(ctx).WithFields(fields()).Warnf("Slow SQL over %v", l.slowThreshold)
+	case l.level >= logger.Info:
+		sqlLogger(ctx).WithFields(fields()).Info("SQL executed")
+	}
+}
+
+// sqlLogger returns the entry writing the SQL logs with the fields of the
+// logger of the context, e.g. the traceID, at any level.
+func sqlLogger(ctx context.Context) *logrus.Entry {
+	var entry *logrus.Entry
+	switch log := ctxutil.GetLogger(ctx).(type) {
+	case *logrus.Entry:
+		entry = log
+	case *logrus.Logger:
+		entry = logrus.NewEntry(log)
+	default:
+		entry = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	base := entry.Logger
+	return (&logrus.Logger{
+		Out:          base.Out,
+		Hooks:        base.Hooks,
+		Formatter:    base.Formatter,
+		ReportCaller: base.ReportCaller,
+		Level:        logrus.TraceLevel,
+		ExitFunc:     base.ExitFunc,
+		BufferPool:   base.BufferPool,
+	}).WithContext(entry.Context).WithFields(entry.Data)
+}
+
+// Name returns the name of the Logger as a gorm plugin.
+func (l *Logger) Name() string {
+	return "gormlog"
+}
+
+// Initialize registers the callbacks recording the SQL of the statements
+// with the placeholders of their parameters, which Trace logs. It's called
+// when the Logger is registered as a plugin of the database, e.g. in the
+// Plugins of the gorm config.
+func (l *Logger) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("gormlog:begin", beginStatement),
+		callbacks.Create().After("*").Register("gormlog:end", endStatement),
+		callbacks.Query().Before("*").Register("gormlog:begin", beginStatement),
+		callbacks.Query().After("*").Register("gormlog:end", endStatement),
+		callbacks.Update().Before("*").Register("gormlog:begin", beginStatement),
+		callbacks.Update().After("*").Register("gormlog:end", endStatement),
+		callbacks.Delete().Before("*").Register("gormlog:begin", beginStatement),
+		callbacks.Delete().After("*").Register("gormlog:end", endStatement),
+		callbacks.Row().Before("*").Register("gormlog:begin", beginStatement),
+		callbacks.Row().After("*").Register("gormlog:end", endStatement),
+		callbacks.Raw().Before("*").Register("gormlog:begin", beginStatement),
+		callbacks.Raw().After("*").Register("gormlog:end", endStatement),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// beginStatement puts the record of the SQL in the context of the statement,
+// which is also the context given to Trace.
+func beginStatement(db *gorm.DB) {
+	if _, ok := db.Statement.Context.Value(statementKey{}).(*statement); !ok {
+		db.Statement.Context = context.WithValue(db.Statement.Context, statementKey{}, &statement{})
+	}
+}
+
+// endStatement records the SQL of the statement before it's reset.
+func endStatement(db *gorm.DB) {
+	if stmt, ok := db.Statement.Context.Value(statementKey{}).(*statement); ok {
+		stmt.sql = db.Statement.SQL.String()
+	}
+}
+
+// source returns the file and line of the code running the statement, the
+// first caller outside gorm and this package.
+func source() string {
+	for i := 2; i < 20; i++ {
+		_, file, line, ok := runtime.Caller(i)
+		if !ok {
+			break
+		}
+		if !strings.Contains(file, "gorm.io/") && !strings.Contains(file, "/gormlog/") {
+			return filepath.Base(file) + ":" + strconv.Itoa(line)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package gormlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/glebarez/sqlite"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLogger(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	logCtx := ctxutil.CtxWithLogger(context.Background(), log.WithField("traceID", "abc"))
+	ctx := context.WithValue(logCtx, statementKey{}, &statement{sql: "SELECT * FROM `system_config`"})
+	fc := func() (string, int64) { return "SELECT * FROM `system_config` WHERE id = 3", 2 }
+
+	t.Run("Parse level", func(t *testing.T) {
+		level, err := ParseLevel("Info")
+		require.NoError(t, err)
+		require.Equal(t, logger.Info, level)
+
+		_, err = ParseLevel("debug")
+		require.Error(t, err)
+	})
+
+	t.Run("Log slow SQL at warn level", func(t *testing.T) {
+		hook.Reset()
+		l := New(logger.Warn, 100*time.Millisecond)
+		l.Trace(ctx, time.Now(), fc, nil)
+		require.Empty(t, hook.AllEntries())
+
+		l.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		require.Equal(t, logrus.WarnLevel, entry.Level)
+		require.Equal(t, "abc", entry.Data["traceID"])
+		require.Equal(t, "SELECT * FROM `system_config`", entry.Data["sql"])
+		require.Equal(t, int64(2), entry.Data["rows"])
+	})
+
+	t.Run("Log failed SQL at error level", func(t *testing.T) {
+		hook.Reset()
+		l := New(logger.Error, 0)
+		l.Trace(ctx, time.Now(), fc, gorm.ErrRecordNotFound)
+		require.Empty(t, hook.AllEntries())
+
+		l.Trace(ctx, time.Now(), fc, errors.New("connection refused"))
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		require.Equal(t, logrus.ErrorLevel, entry.Level)
+		require.Equal(t, "abc", entry.Data["traceID"])
+	})
+
+	t.Run("Log regardless of the logrus level", func(t *testing.T) {
+		hook.Reset()
+		log.SetLevel(logrus.WarnLevel)
+		t.Cleanup(func() { log.SetLevel(logrus.InfoLevel) })
+
+		l := New(logger.Info, 0)
+		l.Trace(ctx, time.Now(), fc, nil)
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		require.Equal(t, logrus.InfoLevel, entry.Level)
+		require.Equal(t, "SQL executed", entry.Message)
+		require.Equal(t, "abc", entry.Data["traceID"])
+
+		// The logs of the application stay at their level
+		hook.Reset()
+		ctxutil.GetLogger(ctx).Info("not logged")
+		require.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("Silent", func(t *testing.T) {
+		hook.Reset()
+		l := New(logger.Info, 0).LogMode(logger.Silent)
+		l.Trace(ctx, time.Now(), fc, errors.New("connection refused"))
+		require.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("Redact bound parameters", func(t *testing.T) {
+		hook.Reset()
+		l := New(logger.Info, 0)
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+			Logger:  l,
+			Plugins: map[string]gorm.Plugin{l.Name(): l},
+		})
+		require.NoError(t, err)
+
+		var count int64
+		err = db.WithContext(ctx).Raw("SELECT COUNT(*) WHERE ? = ?", "password", "s3cret").Find(&count).Error
+		require.NoError(t, err)
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		require.Equal(t, logrus.InfoLevel, entry.Level)
+		require.Equal(t, "SELECT COUNT(*) WHERE ? = ?", entry.Data["sql"])
+
+		// Scan logs the statement with the rendered SQL, bypassing the
+		// params filter of gorm
+		hook.Reset()
+		err = db.WithContext(ctx).Raw("SELECT COUNT(*) WHERE ? = ?", "password", "s3cret").Scan(&count).Error
+		require.NoError(t, err)
+		entry = hook.LastEntry()
+		require.NotNil(t, entry)
+		require.Equal(t, "SELECT COUNT(*) WHERE ? = ?", entry.Data["sql"])
+		for _, entry := range hook.AllEntries() {
+			require.NotContains(t, entry.Data["sql"], "s3cret")
+		}
+	})
+
+	t.Run("Leave out the SQL which isn't recorded", func(t *testing.T) {
+		hook.Reset()
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: New(logger.Info, 0)})
+		require.NoError(t, err)
+
+		var count int64
+		err = db.WithContext(logCtx).Raw("SELECT COUNT(*) WHERE ? = ?", "password", "s3cret").Scan(&count).Error
+		require.NoError(t, err)
+		require.NotEmpty(t, hook.AllEntries())
+		for _, entry := range hook.AllEntries() {
+			require.NotContains(t, entry.Data, "sql")
+		}
+	})
+}
@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/util/refutil"
+	"gorm.io/gorm"
+)
+
+// The systemConfigRepository type implements the repository.SystemConfigRepository interface.
+// If the systemConfigRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.SystemConfigRepository = &systemConfigRepository{}
+
+// systemConfigRecord is a stored system config, deleted records are kept
+// like the soft deleted rows of the database.
+type systemConfigRecord struct {
+	config    entity.SystemConfig
+	deletedAt time.Time
+}
+
+func (r *systemConfigRecord) deleted() bool {
+	return !r.deletedAt.IsZero()
+}
+
+// systemConfigRepository is a repository that stores systemConfigs in memory,
+// it backs the memory driver and the tests of the handlers. It's safe for
+// concurrent use and stores the system configs like the gorm repository:
+// deleted system configs are soft deleted, the tenant, env and type of the
+// system configs are unique, and missing system configs are reported with
+// gorm.ErrRecordNotFound. Unlike the gorm repository it doesn't enforce the
+// rules kept in other tables: the environments, the tenants and their quotas
+// and the freeze windows aren't checked, the changes aren't recorded, and
+// the environments and tenants are never seen as in use.
+type systemConfigRepository struct {
+	mu      sync.RWMutex
+	lastID  uint
+	records map[uint]*systemConfigRecord
+}
+
+// NewSystemConfigRepository creates a new empty systemConfig repository.
+func NewSystemConfigRepository() repository.SystemConfigRepository {
+	return &systemConfigRepository{records: map[uint]*systemConfigRecord{}}
+}
+
+// Create saves a system config to the repository.
+func (r *systemConfigRepository) Create(_ context.Context, dataEntity *entity.SystemConfig) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err = r.checkConflict(dataEntity, 0); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	record := &systemConfigRecord{config: clone(dataEntity)}
+	if record.config.ID == 0 {
+		record.config.ID = r.lastID + 1
+	}
+	if _, ok := r.records[record.config.ID]; ok {
+		return errors.Errorf("system config %d already exists", record.config.ID)
+	}
+	if record.config.CreatedAt.IsZero() {
+		record.config.CreatedAt = now
+	}
+	if record.config.UpdatedAt.IsZero() {
+		record.config.UpdatedAt = now
+	}
+	if record.config.ID > r.lastID {
+		r.lastID = record.config.ID
+	}
+	r.records[record.config.ID] = record
+
+	*dataEntity = clone(&record.config)
+
+	return nil
+}
+
+// Delete removes a system config from the repository.
+func (r *systemConfigRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, err := r.get(id)
+	if err != nil {
+		return err
+	}
+	record.deletedAt = time.Now()
+
+	return nil
+}
+
+// Update updates an existing system config in the repository.
+// Like the gorm updates, the zero fields are left unchanged, and the labels
+// are replaced as a whole unless they are nil.
+func (r *systemConfigRepository) Update(_ context.Context, dataEntity *entity.SystemConfig) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, err := r.get(dataEntity.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := record.config
+	setIfNotZero(&updated.Tenant, dataEntity.Tenant)
+	setIfNotZero(&updated.Env, dataEntity.Env)
+	setIfNotZero(&updated.Type, dataEntity.Type)
+	setIfNotZero(&updated.Config, dataEntity.Config)
+	setIfNotZero(&updated.Description, dataEntity.Description)
+	setIfNotZero(&updated.Creator, dataEntity.Creator)
+	setIfNotZero(&updated.Modifier, dataEntity.Modifier)
+	if !dataEntity.CreatedAt.IsZero() {
+		updated.CreatedAt = dataEntity.CreatedAt
+	}
+	updated.UpdatedAt = time.Now()
+	if dataEntity.Labels != nil {
+		updated.Labels = cloneLabels(dataEntity.Labels)
+	}
+
+	if err = r.checkConflict(&updated, updated.ID); err != nil {
+		return err
+	}
+	record.config = updated
+
+	return nil
+}
+
+// Get retrieves a system config by its ID.
+func (r *systemConfigRepository) Get(_ context.Context, id uint) (*entity.SystemConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, err := r.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	config := clone(&record.config)
+	return &config, nil
+}
+
+// GetByKey retrieves a system config by its tenant, env and type.
+func (r *systemConfigRepository) GetByKey(_ context.Context, tenant string, env entity.Env, typ string) (*entity.SystemConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, record := range r.sorted() {
+		if record.config.Tenant == tenant && record.config.Env == env && record.config.Type == typ {
+			config := clone(&record.config)
+			return &config, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Find returns a list of specified system configs in the repository. A
+// negative limit returns all system configs after the offset.
+func (r *systemConfigRepository) Find(_ context.Context, query repository.Query) ([]*entity.SystemConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := r.match(query)
+	if query.Offset > 0 {
+		if query.Offset > len(matched) {
+			query.Offset = len(matched)
+		}
+		matched = matched[query.Offset:]
+	}
+	if query.Limit >= 0 && query.Limit < len(matched) {
+		matched = matched[:query.Limit]
+	}
+
+	systemConfigEntities := make([]*entity.SystemConfig, 0, len(matched))
+	for _, record := range matched {
+		config := clone(&record.config)
+		systemConfigEntities = append(systemConfigEntities, &config)
+	}
+	return systemConfigEntities, nil
+}
+
+// Count returns the total of system configs matching the query.
+func (r *systemConfigRepository) Count(_ context.Context, query repository.Query) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.match(query)), nil
+}
+
+// FindDependents returns the system configs whose content references the
+// system config of the given tenant, env and type.
+func (r *systemConfigRepository) FindDependents(_ context.Context, tenant string, env entity.Env, typ string) ([]*entity.SystemConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := refutil.Reference{Tenant: tenant, Env: string(env), Type: typ}.Key()
+	systemConfigEntities := []*entity.SystemConfig{}
+	for _, record := range r.sorted() {
+		for _, ref := range refutil.Parse(record.config.Config) {
+			if ref.Key() == key {
+				config := clone(&record.config)
+				systemConfigEntities = append(systemConfigEntities, &config)
+				break
+			}
+		}
+	}
+	return systemConfigEntities, nil
+}
+
+// get returns the live record of the ID, or gorm.ErrRecordNotFound.
+func (r *systemConfigRepository) get(id uint) (*systemConfigRecord, error) {
+	record, ok := r.records[id]
+	if !ok || record.deleted() {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return record, nil
+}
+
+// sorted returns the live records ordered by ID.
+func (r *systemConfigRepository) sorted() []*systemConfigRecord {
+	records := make([]*systemConfigRecord, 0, len(r.records))
+	for _, record := range r.records {
+		if !record.deleted() {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].config.ID < records[j].config.ID
+	})
+	return records
+}
+
+// match returns the live records matching the keyword and the label
+// selector of the query, ordered by ID.
+func (r *systemConfigRepository) match(query repository.Query) []*systemConfigRecord {
+	var matched []*systemConfigRecord
+	for _, record := range r.sorted() {
+		if query.Keyword != "" && !strings.Contains(record.config.Config, query.Keyword) {
+			continue
+		}
+		if !query.LabelSelector.Matches(record.config.Labels) {
+			continue
+		}
+		matched = append(matched, record)
+	}
+	return matched
+}
+
+// checkConflict returns entity.ErrConfigConflict if another live system
+// config than the one of the ID has the same tenant, env and type.
+func (r *systemConfigRepository) checkConflict(dataEntity *entity.SystemConfig, id uint) error {
+	for _, record := range r.records {
+		if record.deleted() || record.config.ID == id {
+			continue
+		}
+		if record.config.Tenant == dataEntity.Tenant && record.config.Env == dataEntity.Env && record.config.Type == dataEntity.Type {
+			return errors.Wrapf(entity.ErrConfigConflict, "%s/%s/%s", dataEntity.Tenant, dataEntity.Env, dataEntity.Type)
+		}
+	}
+	return nil
+}
+
+// clone returns a copy of the system config which doesn't share the labels.
+func clone(config *entity.SystemConfig) entity.SystemConfig {
+	copied := *config
+	copied.Labels = cloneLabels(config.Labels)
+	return copied
+}
+
+// cloneLabels returns a copy of the labels, empty labels are nil like the
+// labels read from the database.
+func cloneLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
+
+// setIfNotZero sets the field to the value unless the value is zero.
+func setIfNotZero[T comparable](field *T, value T) {
+	var zero T
+	if value != zero {
+		*field = value
+	}
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository/repositorytest"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newConfig(typ string) *entity.SystemConfig {
+	return &entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: typ, Config: "{}", Labels: map[string]string{"team": "payments"}}
+}
+
+func TestSystemConfigRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create and get a copy", func(t *testing.T) {
+		repo := NewSystemConfigRepository()
+		config := newConfig("cache")
+		require.NoError(t, repo.Create(ctx, config))
+		require.Equal(t, uint(1), config.ID)
+		require.False(t, config.CreatedAt.IsZero())
+
+		// Changing the returned entity doesn't change the stored one
+		actual, err := repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		actual.Labels["team"] = "search"
+		actual, err = repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, "payments", actual.Labels["team"])
+	})
+
+	t.Run("Update keeps zero fields and nil labels", func(t *testing.T) {
+		repo := NewSystemConfigRepository()
+		config := newConfig("cache")
+		config.Description = "redis"
+		require.NoError(t, repo.Create(ctx, config))
+
+		err := repo.Update(ctx, &entity.SystemConfig{ID: config.ID, Env: entity.EnvProd, Config: `{"db": 3}`})
+		require.NoError(t, err)
+		actual, err := repo.Get(ctx, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, `{"db": 3}`, actual.Config)
+		require.Equal(t, "redis", actual.Description)
+		require.Equal(t, map[string]string{"team": "payments"}, actual.Labels)
+
+		err = repo.Update(ctx, &entity.SystemConfig{ID: 100, Env: entity.EnvProd})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Concurrent writes", func(t *testing.T) {
+		repo := NewSystemConfigRepository()
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				require.NoError(t, repo.Create(ctx, newConfig(fmt.Sprintf("type-%d", i))))
+			}(i)
+		}
+		wg.Wait()
+
+		total, err := repo.Count(ctx, repository.Query{})
+		require.NoError(t, err)
+		require.Equal(t, 50, total)
+	})
+}
+
+func TestSystemConfigSuite(t *testing.T) {
+	repositorytest.RunSystemConfigSuite(t, func(t *testing.T) repository.SystemConfigRepository {
+		return NewSystemConfigRepository()
+	})
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/elliotxx/errors"
+	"gorm.io/gorm"
+)
+
+// ErrLockTimeout is returned when another instance holds the migration
+// lock for longer than the lock timeout.
+var ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+const (
+	// lockName is the name of the mysql named lock.
+	lockName = "schema_migrations"
+	// lockKey is the key of the postgres advisory lock, derived from
+	// "schema_migrations" so that it doesn't collide with other locks.
+	lockKey int64 = 0x736368656d61
+	// lockRetryInterval is how often the postgres lock is retried.
+	lockRetryInterval = time.Second
+)
+
+// lock acquires the migration lock on the connection and returns the
+// function releasing it. The lock belongs to the connection, so the caller
+// must run the migrations on the same connection.
+func lock(conn *gorm.DB, timeout time.Duration) (func(), error) {
+	switch conn.Dialector.Name() {
+	case "mysql":
+		var acquired int
+		err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int(timeout.Seconds())).Scan(&acquired).Error
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to acquire the migration lock")
+		}
+		if acquired != 1 {
+			return nil, ErrLockTimeout
+		}
+
+		return func() { conn.Exec("SELECT RELEASE_LOCK(?)", lockName) }, nil
+	case "postgres":
+		deadline := time.Now().Add(timeout)
+		for {
+			var acquired bool
+			err := conn.Raw("SELECT pg_try_advisory_lock(?)", lockKey).Scan(&acquired).Error
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to acquire the migration lock")
+			}
+			if acquired {
+				return func() { conn.Exec("SELECT pg_advisory_unlock(?)", lockKey) }, nil
+			}
+			if time.Now().After(deadline) {
+				return nil, ErrLockTimeout
+			}
+			time.Sleep(lockRetryInterval)
+		}
+	default:
+		// SQLite allows a single writer on the database file, the
+		// migrations are serialized by the transactions
+		return func() {}, nil
+	}
+}
@@ -0,0 +1,228 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elliotxx/errors"
+)
+
+// filenameRegexp matches the migration files like "000001_init_schema.up.sql".
+var filenameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change with the SQL to apply and to
+// revert it.
+type Migration struct {
+	// Version orders the migrations, it's unique among the migrations.
+	Version uint64
+	// Name describes the schema change.
+	Name string
+	// Up is the SQL applying the schema change.
+	Up string
+	// Down is the SQL reverting the schema change.
+	Down string
+}
+
+// Checksum returns the SHA-256 of the up SQL, which is recorded when the
+// migration is applied to detect an applied migration being edited.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// String returns the migration in the "<version>_<name>" form.
+func (m Migration) String() string {
+	return strconv.FormatUint(m.Version, 10) + "_" + m.Name
+}
+
+// Load reads the migrations in the directory of the file system, sorted by
+// version. Every migration must have both an up and a down file.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read migrations from %s", dir)
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		m := filenameRegexp.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, errors.Errorf("invalid migration file name %q, expected <version>_<name>.<up|down>.sql", entry.Name())
+		}
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, errors.Errorf("invalid migration version in %q, expected a positive integer", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read migration %s", entry.Name())
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, errors.Errorf("migration version %d is used by both %q and %q", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, errors.Errorf("migration %s must have both a non-empty up and down file", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// splitStatements splits the SQL script into statements on the semicolons
+// which aren't inside quotes, comments, dollar-quoted strings or BEGIN ...
+// END blocks, so that each statement can be executed on its own, e.g. a
+// trigger whose body holds several statements. Statements with nothing but
+// comments are dropped.
+func splitStatements(script string) []string {
+	var (
+		stmts   []string
+		start   int
+		hasCode bool
+		// depth is the number of BEGIN ... END and CASE ... END blocks the
+		// scanner is in
+		depth int
+	)
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			// Skip the quoted string, a doubled quote is an escaped quote
+			// and a backslash escapes the next character in mysql
+			for i++; i < len(script); i++ {
+				if script[i] == '\\' && c != '`' {
+					i++
+				} else if script[i] == c {
+					if i+1 < len(script) && script[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			hasCode = true
+		case c == '-' && strings.HasPrefix(script[i:], "--"), c == '#':
+			// Skip the line comment
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			// Skip the block comment
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+		case c == '$':
+			// Skip the postgres dollar-quoted string like $body$...$body$
+			tag := dollarTag(script[i:])
+			if tag == "" {
+				hasCode = true
+				continue
+			}
+			if end := strings.Index(script[i+len(tag):], tag); end >= 0 {
+				i += len(tag) + end + len(tag) - 1
+			} else {
+				i = len(script)
+			}
+			hasCode = true
+		case isWordChar(c) && (c < '0' || c > '9'):
+			// A BEGIN opens a block unless it starts a transaction, the END
+			// of an IF, LOOP, WHILE or REPEAT doesn't close any block
+			end := i + wordLength(script[i:])
+			switch word := strings.ToUpper(script[i:end]); {
+			case word == "BEGIN" && (hasCode || depth > 0), word == "CASE":
+				depth++
+			case word == "END" && depth > 0:
+				next := strings.TrimLeft(script[end:], " \t\r\n")
+				switch strings.ToUpper(next[:wordLength(next)]) {
+				case "IF", "LOOP", "WHILE", "REPEAT":
+					// The keyword is skipped, as it closes no block
+					end = len(script) - len(next) + wordLength(next)
+				case "CASE":
+					// The keyword is skipped, so that it opens no block
+					end = len(script) - len(next) + wordLength(next)
+					depth--
+				default:
+					depth--
+				}
+			}
+			i = end - 1
+			hasCode = true
+		case c == ';' && depth == 0:
+			if hasCode {
+				stmts = append(stmts, strings.TrimSpace(script[start:i]))
+			}
+			start, hasCode = i+1, false
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			hasCode = true
+		}
+	}
+	if hasCode && start < len(script) {
+		stmts = append(stmts, strings.TrimSpace(script[start:]))
+	}
+
+	return stmts
+}
+
+// isWordChar returns true if the character may be part of a keyword or an
+// unquoted identifier.
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// wordLength returns the length of the keyword or unquoted identifier at the
+// start of s.
+func wordLength(s string) int {
+	for i := 0; i < len(s); i++ {
+		if !isWordChar(s[i]) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// dollarTag returns the opening tag of a dollar-quoted string at the start
+// of s, such as "$$" or "$body$", or an empty string if there is none.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/elliotxx/go-web-template/assets"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "multiple-statements",
+			script: "CREATE TABLE a (id int);\nCREATE TABLE b (id int);\n",
+			want:   []string{"CREATE TABLE a (id int)", "CREATE TABLE b (id int)"},
+		},
+		{
+			name:   "semicolon-in-quotes",
+			script: "INSERT INTO a VALUES ('x;y', \"a;b\", 'it''s;'); INSERT INTO `a;b` VALUES ('\\';')",
+			want:   []string{"INSERT INTO a VALUES ('x;y', \"a;b\", 'it''s;')", "INSERT INTO `a;b` VALUES ('\\';')"},
+		},
+		{
+			name:   "semicolon-in-comments",
+			script: "-- drop; the table\nDROP TABLE a; /* ; */ # ;\n-- trailing;",
+			want:   []string{"-- drop; the table\nDROP TABLE a"},
+		},
+		{
+			name:   "dollar-quoted-body",
+			script: "CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.a := 1; RETURN NEW; END; $body$ LANGUAGE plpgsql; SELECT $1",
+			want: []string{
+				"CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.a := 1; RETURN NEW; END; $body$ LANGUAGE plpgsql",
+				"SELECT $1",
+			},
+		},
+		{
+			name: "dollar-quoted-function",
+			script: "CREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN\n  NEW.update_timestamp := now();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\n" +
+				"CREATE TRIGGER touch BEFORE UPDATE ON a FOR EACH ROW EXECUTE FUNCTION touch();",
+			want: []string{
+				"CREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN\n  NEW.update_timestamp := now();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+				"CREATE TRIGGER touch BEFORE UPDATE ON a FOR EACH ROW EXECUTE FUNCTION touch()",
+			},
+		},
+		{
+			name: "mysql-trigger",
+			script: "CREATE TRIGGER clamp BEFORE UPDATE ON a FOR EACH ROW\nBEGIN\n  IF NEW.x < 0 THEN\n    SET NEW.x = 0;\n  END IF;\n" +
+				"  SET NEW.y = CASE WHEN NEW.x > 1 THEN 1 ELSE 0 END;\n  CASE NEW.x WHEN 0 THEN SET NEW.z = 0; ELSE BEGIN SET NEW.z = 1; END; END CASE;\nEND;\n" +
+				"INSERT INTO a (x) VALUES (1);",
+			want: []string{
+				"CREATE TRIGGER clamp BEFORE UPDATE ON a FOR EACH ROW\nBEGIN\n  IF NEW.x < 0 THEN\n    SET NEW.x = 0;\n  END IF;\n" +
+					"  SET NEW.y = CASE WHEN NEW.x > 1 THEN 1 ELSE 0 END;\n  CASE NEW.x WHEN 0 THEN SET NEW.z = 0; ELSE BEGIN SET NEW.z = 1; END; END CASE;\nEND",
+				"INSERT INTO a (x) VALUES (1)",
+			},
+		},
+		{
+			name:   "sqlite-trigger",
+			script: "CREATE TRIGGER log AFTER DELETE ON a BEGIN INSERT INTO b VALUES (OLD.id); DELETE FROM c WHERE a_id = OLD.id; END; DROP TABLE d",
+			want: []string{
+				"CREATE TRIGGER log AFTER DELETE ON a BEGIN INSERT INTO b VALUES (OLD.id); DELETE FROM c WHERE a_id = OLD.id; END",
+				"DROP TABLE d",
+			},
+		},
+		{
+			name:   "transaction-and-keywords-in-identifiers",
+			script: "BEGIN; UPDATE a SET begin_at = 1, end_at = 2 WHERE \"end\" = 'END'; COMMIT;",
+			want:   []string{"BEGIN", "UPDATE a SET begin_at = 1, end_at = 2 WHERE \"end\" = 'END'", "COMMIT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, splitStatements(tt.script))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("sorted-by-version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"m/000010_add_b.up.sql":   {Data: []byte("CREATE TABLE b (id int);")},
+			"m/000010_add_b.down.sql": {Data: []byte("DROP TABLE b;")},
+			"m/000002_add_a.up.sql":   {Data: []byte("CREATE TABLE a (id int);")},
+			"m/000002_add_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		}
+		migrations, err := Load(fsys, "m")
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		require.Equal(t, uint64(2), migrations[0].Version)
+		require.Equal(t, "add_a", migrations[0].Name)
+		require.Equal(t, "DROP TABLE a;", migrations[0].Down)
+		require.Equal(t, "10_add_b", migrations[1].String())
+	})
+
+	t.Run("failed-missing-down", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"m/000001_add_a.up.sql": {Data: []byte("CREATE TABLE a (id int);")},
+		}
+		_, err := Load(fsys, "m")
+		require.Error(t, err)
+	})
+
+	t.Run("failed-invalid-name", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"m/add_a.sql": {Data: []byte("CREATE TABLE a (id int);")},
+		}
+		_, err := Load(fsys, "m")
+		require.Error(t, err)
+	})
+
+	t.Run("embedded-migrations", func(t *testing.T) {
+		for _, driver := range []string{"mysql", "postgres", "sqlite"} {
+			migrations, err := Load(assets.Migrations, "migrations/"+driver)
+			require.NoError(t, err, driver)
+			require.NotEmpty(t, migrations, driver)
+		}
+	})
+}
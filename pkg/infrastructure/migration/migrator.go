@@ -0,0 +1,317 @@
+package migration
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrChecksumMismatch is returned when an applied migration has been
+	// edited since it was applied.
+	ErrChecksumMismatch = errors.New("checksum of applied migration doesn't match")
+	// ErrUnknownVersion is returned when the database has a migration
+	// applied which this binary doesn't know, e.g. after a rollback of the
+	// binary, or when migrating to a version which doesn't exist.
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// DefaultLockTimeout is how long to wait for another instance to finish
+// migrating the database.
+const DefaultLockTimeout = time.Minute
+
+// SchemaMigration records an applied migration in the schema_migrations
+// table.
+type SchemaMigration struct {
+	Version   uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255;not null"`
+	Checksum  string `gorm:"size:64;not null"`
+	AppliedAt time.Time
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status is the state of a migration in the database.
+type Status struct {
+	Migration
+	// Applied is true if the migration has been applied.
+	Applied bool
+	// AppliedAt is when the migration was applied.
+	AppliedAt *time.Time
+	// Modified is true if the migration has been edited since it was
+	// applied.
+	Modified bool
+	// Missing is true if the migration is applied but unknown to this
+	// binary.
+	Missing bool
+}
+
+// Migrator applies and reverts the migrations, recording the applied ones
+// in the schema_migrations table. Every operation holds a database lock so
+// that several instances starting at the same time migrate only once.
+type Migrator struct {
+	db          *gorm.DB
+	migrations  []Migration
+	lockTimeout time.Duration
+}
+
+// New creates a new Migrator of the migrations sorted by version.
+func New(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:          db,
+		migrations:  migrations,
+		lockTimeout: DefaultLockTimeout,
+	}
+}
+
+// Up applies all pending migrations and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB, records map[uint64]SchemaMigration) error {
+		pending, err := m.pending(records, m.latest())
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			if err = apply(conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last applied migration and returns it, or nil if no
+// migration is applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var reverted *Migration
+	err := m.withLock(ctx, func(conn *gorm.DB, records map[uint64]SchemaMigration) error {
+		var last uint64
+		for version := range records {
+			if version > last {
+				last = version
+			}
+		}
+		if last == 0 {
+			return nil
+		}
+
+		migration, ok := m.find(last)
+		if !ok {
+			return errors.Wrapf(ErrUnknownVersion, "can't revert version %d", last)
+		}
+		if err := revert(conn, migration); err != nil {
+			return err
+		}
+		reverted = &migration
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// To applies or reverts the migrations so that the database is at the
+// version, 0 reverts all migrations. It returns the migrations applied or
+// reverted.
+func (m *Migrator) To(ctx context.Context, version uint64) ([]Migration, error) {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return nil, errors.Wrapf(ErrUnknownVersion, "can't migrate to version %d", version)
+	}
+
+	var changed []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB, records map[uint64]SchemaMigration) error {
+		for _, record := range records {
+			if _, ok := m.find(record.Version); !ok && record.Version > version {
+				return errors.Wrapf(ErrUnknownVersion, "can't revert version %d (%s)", record.Version, record.Name)
+			}
+		}
+
+		// Revert the applied migrations after the version, latest first
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := records[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+			if err := revert(conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration)
+		}
+		if len(changed) > 0 {
+			return nil
+		}
+
+		pending, err := m.pending(records, version)
+		if err != nil {
+			return err
+		}
+		for _, migration := range pending {
+			if err = apply(conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration)
+		}
+
+		return nil
+	})
+
+	return changed, err
+}
+
+// Status returns the status of every known migration, followed by the
+// applied migrations unknown to this binary.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *gorm.DB, records map[uint64]SchemaMigration) error {
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if record, ok := records[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Modified = record.Checksum != migration.Checksum()
+				delete(records, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+
+		missing := make([]SchemaMigration, 0, len(records))
+		for _, record := range records {
+			missing = append(missing, record)
+		}
+		sort.Slice(missing, func(i, j int) bool {
+			return missing[i].Version < missing[j].Version
+		})
+		for _, record := range missing {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, Status{
+				Migration: Migration{Version: record.Version, Name: record.Name},
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock runs the function on a single connection holding the migration
+// lock, with the records of the applied migrations.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB, records map[uint64]SchemaMigration) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		unlock, err := lock(conn, m.lockTimeout)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		err = conn.AutoMigrate(&SchemaMigration{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create schema_migrations table")
+		}
+
+		var records []SchemaMigration
+		err = conn.Find(&records).Error
+		if err != nil {
+			return errors.Wrap(err, "failed to read schema_migrations table")
+		}
+
+		byVersion := make(map[uint64]SchemaMigration, len(records))
+		for _, record := range records {
+			byVersion[record.Version] = record
+		}
+
+		return fn(conn, byVersion)
+	})
+}
+
+// pending returns the migrations up to the version which aren't applied. It
+// refuses to migrate a database whose applied migrations were edited or are
+// unknown to this binary.
+func (m *Migrator) pending(records map[uint64]SchemaMigration, version uint64) ([]Migration, error) {
+	for _, record := range records {
+		migration, ok := m.find(record.Version)
+		if !ok {
+			return nil, errors.Wrapf(ErrUnknownVersion, "version %d (%s) is applied", record.Version, record.Name)
+		}
+		if record.Checksum != migration.Checksum() {
+			return nil, errors.Wrapf(ErrChecksumMismatch, "migration %s", migration)
+		}
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := records[migration.Version]; !ok && migration.Version <= version {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// find returns the migration of the version.
+func (m *Migrator) find(version uint64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// latest returns the latest version of the migrations.
+func (m *Migrator) latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// apply executes the up SQL of the migration and records it in a
+// transaction. MySQL commits DDL implicitly, so a migration failing halfway
+// there has to be fixed by hand.
+func apply(conn *gorm.DB, migration Migration) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range splitStatements(migration.Up) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return errors.Wrapf(err, "failed to apply migration %s", migration)
+			}
+		}
+
+		return tx.Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum(),
+			AppliedAt: time.Now(),
+		}).Error
+	})
+}
+
+// revert executes the down SQL of the migration and removes its record in
+// a transaction.
+func revert(conn *gorm.DB, migration Migration) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range splitStatements(migration.Down) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return errors.Wrapf(err, "failed to revert migration %s", migration)
+			}
+		}
+
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	})
+}
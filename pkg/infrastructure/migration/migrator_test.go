@@ -0,0 +1,192 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/assets"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "app.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "add_a", Up: "CREATE TABLE a (id int);", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "add_b", Up: "CREATE TABLE b (id int); INSERT INTO b VALUES (1);", Down: "DROP TABLE b;"},
+		{Version: 3, Name: "add_c", Up: "CREATE TABLE c (id int);", Down: "DROP TABLE c;"},
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Up, Down and To", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		m := New(db, testMigrations())
+
+		applied, err := m.Up(ctx)
+		require.NoError(t, err)
+		require.Len(t, applied, 3)
+		require.True(t, db.Migrator().HasTable("c"))
+
+		// Applying again is a no-op
+		applied, err = m.Up(ctx)
+		require.NoError(t, err)
+		require.Empty(t, applied)
+
+		reverted, err := m.Down(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), reverted.Version)
+		require.False(t, db.Migrator().HasTable("c"))
+
+		changed, err := m.To(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, changed, 1)
+		require.False(t, db.Migrator().HasTable("b"))
+
+		changed, err = m.To(ctx, 3)
+		require.NoError(t, err)
+		require.Len(t, changed, 2)
+
+		statuses, err := m.Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 3)
+		for _, status := range statuses {
+			require.True(t, status.Applied)
+			require.False(t, status.Modified)
+		}
+
+		_, err = m.To(ctx, 4)
+		require.ErrorIs(t, err, ErrUnknownVersion)
+	})
+
+	t.Run("Refuse edited migrations", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		_, err := New(db, testMigrations()[:1]).Up(ctx)
+		require.NoError(t, err)
+
+		edited := testMigrations()
+		edited[0].Up = "CREATE TABLE a (id bigint);"
+		m := New(db, edited)
+		_, err = m.Up(ctx)
+		require.ErrorIs(t, err, ErrChecksumMismatch)
+
+		statuses, err := m.Status(ctx)
+		require.NoError(t, err)
+		require.True(t, statuses[0].Modified)
+		require.False(t, statuses[1].Applied)
+	})
+
+	t.Run("Refuse unknown applied migrations", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		_, err := New(db, testMigrations()).Up(ctx)
+		require.NoError(t, err)
+
+		m := New(db, testMigrations()[:2])
+		_, err = m.Up(ctx)
+		require.ErrorIs(t, err, ErrUnknownVersion)
+
+		statuses, err := m.Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 3)
+		require.True(t, statuses[2].Missing)
+	})
+
+	t.Run("Failed migration is rolled back", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		migrations := append(testMigrations()[:1], Migration{
+			Version: 2, Name: "broken", Up: "CREATE TABLE b (id int); INSERT INTO missing VALUES (1);", Down: "DROP TABLE b;",
+		})
+		m := New(db, migrations)
+		_, err := m.Up(ctx)
+		require.Error(t, err)
+		require.True(t, db.Migrator().HasTable("a"))
+		require.False(t, db.Migrator().HasTable("b"))
+
+		statuses, err := m.Status(ctx)
+		require.NoError(t, err)
+		require.True(t, statuses[0].Applied)
+		require.False(t, statuses[1].Applied)
+	})
+
+	t.Run("Embedded sqlite migrations", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		migrations, err := Load(assets.Migrations, "migrations/sqlite")
+		require.NoError(t, err)
+
+		m := New(db, migrations)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+		require.True(t, db.Migrator().HasTable("system_config"))
+
+		_, err = m.To(ctx, 0)
+		require.NoError(t, err)
+		require.False(t, db.Migrator().HasTable("system_config_label"))
+		// The baseline table is kept with its data
+		require.True(t, db.Migrator().HasTable("system_config"))
+	})
+
+	t.Run("Embedded sqlite migrations on a baseline database", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		migrations, err := Load(assets.Migrations, "migrations/sqlite")
+		require.NoError(t, err)
+
+		// The database was created by the baseline schema before the migrations
+		for _, stmt := range splitStatements(migrations[0].Up) {
+			require.NoError(t, db.Exec(stmt).Error)
+		}
+		// The baseline allowed duplicate configs of a key
+		for _, config := range []string{"old", "new"} {
+			require.NoError(t, db.Exec("INSERT INTO system_config (tenant, env, type, config) VALUES ('MAIN_SITE', 'prod', 'redis', ?)", config).Error)
+		}
+		require.NoError(t, db.Exec("INSERT INTO system_config (tenant, env, type, config) VALUES ('MAIN_SITE', 'dev', 'redis', 'dev')").Error)
+
+		m := New(db, migrations)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+		require.True(t, db.Migrator().HasIndex("system_config", "uniq_system_config_key"))
+
+		// The latest duplicate is kept, the others are soft deleted
+		var live []string
+		require.NoError(t, db.Table("system_config").Where("deleted_at IS NULL").Order("id").Pluck("config", &live).Error)
+		require.Equal(t, []string{"new", "dev"}, live)
+		var deleted []string
+		require.NoError(t, db.Table("system_config").Where("deleted_at IS NOT NULL").Pluck("config", &deleted).Error)
+		require.Equal(t, []string{"old"}, deleted)
+
+		// The deleted duplicates are recorded as revisions
+		var revisions []struct {
+			SystemConfigID uint
+			Revision       int
+			Snapshot       string
+			Reason         string
+		}
+		require.NoError(t, db.Table("system_config_revision").Find(&revisions).Error)
+		require.Len(t, revisions, 1)
+		require.Equal(t, uint(1), revisions[0].SystemConfigID)
+		require.Equal(t, 1, revisions[0].Revision)
+		require.Equal(t, "migration/11/duplicate-of/2", revisions[0].Reason)
+		var snapshot entity.SystemConfig
+		require.NoError(t, json.Unmarshal([]byte(revisions[0].Snapshot), &snapshot))
+		require.Equal(t, entity.SystemConfig{ID: 1, Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: "redis", Config: "old"}, snapshot)
+
+		_, err = m.To(ctx, 0)
+		require.NoError(t, err)
+		var count int64
+		require.NoError(t, db.Table("system_config").Count(&count).Error)
+		require.Equal(t, int64(3), count)
+	})
+}
@@ -48,7 +48,7 @@ func (s MultiString) GormDataType() string {
 func (s MultiString) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 	// returns different database type based on driver name
 	switch db.Dialector.Name() {
-	case "mysql", "sqlite":
+	case "mysql", "postgres", "sqlite":
 		return "text"
 	}
 	return ""
@@ -72,6 +72,7 @@ func GetMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
 		SkipInitializeWithVersion: false,
 	}), &gorm.Config{
 		SkipDefaultTransaction: true,
+		TranslateError:         true,
 	})
 	if err != nil {
 		return nil, nil, err
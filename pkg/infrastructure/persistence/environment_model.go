@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// EnvironmentModel is a DO used to map the entity to the database.
+// Environments are hard deleted so that a name can be reused.
+type EnvironmentModel struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Description string
+	SortOrder   int
+	Protected   bool
+	Creator     string
+	Modifier    string
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *EnvironmentModel) TableName() string {
+	return "environment"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *EnvironmentModel) ToEntity() (*entity.Environment, error) {
+	if m == nil {
+		return nil, ErrEnvironmentModelNil
+	}
+
+	name, err := entity.ParseEnv(m.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse env")
+	}
+
+	return &entity.Environment{
+		ID:          m.ID,
+		Name:        name,
+		Description: m.Description,
+		Order:       m.SortOrder,
+		Protected:   m.Protected,
+		Creator:     m.Creator,
+		Modifier:    m.Modifier,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *EnvironmentModel) FromEntity(e *entity.Environment) error {
+	if m == nil {
+		return ErrEnvironmentModelNil
+	}
+
+	m.ID = e.ID
+	m.Name = string(e.Name)
+	m.Description = e.Description
+	m.SortOrder = e.Order
+	m.Protected = e.Protected
+	m.Creator = e.Creator
+	m.Modifier = e.Modifier
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+// The environmentRepository type implements the repository.EnvironmentRepository interface.
+// If the environmentRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.EnvironmentRepository = &environmentRepository{}
+
+// environmentRepository is a repository that stores environments in a gorm database.
+type environmentRepository struct {
+	// db is the underlying gorm database where environments are stored.
+	db *gorm.DB
+}
+
+// NewEnvironmentRepository creates a new environment repository.
+func NewEnvironmentRepository(db *gorm.DB) repository.EnvironmentRepository {
+	return &environmentRepository{db: db}
+}
+
+// Create saves an environment to the repository.
+func (r *environmentRepository) Create(ctx context.Context, dataEntity *entity.Environment) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel EnvironmentModel
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Create new record in the store
+	err = dbFrom(ctx, r.db).Create(&dataModel).Error
+	if err != nil {
+		return err
+	}
+
+	// Map fresh record's data into Entity
+	newEntity, err := dataModel.ToEntity()
+	if err != nil {
+		return err
+	}
+	*dataEntity = *newEntity
+
+	return nil
+}
+
+// Delete removes an environment from the repository. Protected environments
+// and environments still referenced by system configs can't be deleted.
+func (r *environmentRepository) Delete(ctx context.Context, id uint) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var dataModel EnvironmentModel
+		err := tx.First(&dataModel, id).Error
+		if err != nil {
+			return err
+		}
+
+		if dataModel.Protected {
+			return errors.Wrapf(entity.ErrEnvProtected, "failed to delete environment %q", dataModel.Name)
+		}
+
+		var total int64
+		err = tx.Model(&SystemConfigModel{}).Where("env = ?", dataModel.Name).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if total > 0 {
+			return errors.Wrapf(entity.ErrEnvInUse, "failed to delete environment %q referenced by %d system configs", dataModel.Name, total)
+		}
+
+		return tx.Delete(&dataModel).Error
+	})
+}
+
+// Update updates an existing environment in the repository.
+// The name and the creator of the environment are immutable.
+func (r *environmentRepository) Update(ctx context.Context, dataEntity *entity.Environment) error {
+	// Map the data from Entity to DO
+	var dataModel EnvironmentModel
+	err := dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Select the columns explicitly so that zero values are updated as well
+	result := dbFrom(ctx, r.db).
+		Model(&dataModel).
+		Select("description", "sort_order", "protected", "modifier").
+		Updates(&dataModel)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Get retrieves an environment by its ID.
+func (r *environmentRepository) Get(ctx context.Context, id uint) (*entity.Environment, error) {
+	var dataModel EnvironmentModel
+	err := dbFrom(ctx, r.db).First(&dataModel, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// GetByName retrieves an environment by its name.
+func (r *environmentRepository) GetByName(ctx context.Context, name entity.Env) (*entity.Environment, error) {
+	var dataModel EnvironmentModel
+	err := dbFrom(ctx, r.db).Where("name = ?", string(name)).First(&dataModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// List returns all environments in the repository ordered by their order.
+func (r *environmentRepository) List(ctx context.Context) ([]*entity.Environment, error) {
+	var environmentModels []*EnvironmentModel
+	if err := dbFrom(ctx, r.db).
+		Order("sort_order").
+		Order("id").
+		Find(&environmentModels).Error; err != nil {
+		return nil, err
+	}
+
+	environmentEntities := make([]*entity.Environment, 0, len(environmentModels))
+	for _, model := range environmentModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", model.ID)
+		}
+
+		environmentEntities = append(environmentEntities, newEntity)
+	}
+	return environmentEntities, nil
+}
+
+// ensureEnvExists returns entity.ErrEnvNotFound if the environment is not
+// defined in the environment table.
+func ensureEnvExists(tx *gorm.DB, env entity.Env) error {
+	var total int64
+	err := tx.Model(&EnvironmentModel{}).Where("name = ?", string(env)).Count(&total).Error
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return errors.Wrapf(entity.ErrEnvNotFound, "environment %q", env)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestEnvironmentRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		var (
+			expectedID uint = 7
+			actual          = entity.Environment{Name: "staging", Order: 35}
+		)
+		sqlMock.ExpectExec("INSERT INTO `environment`").
+			WillReturnResult(sqlmock.NewResult(int64(expectedID), 1))
+		err = repo.Create(context.Background(), &actual)
+		require.NoError(t, err)
+		require.Equal(t, expectedID, actual.ID)
+	})
+
+	t.Run("Create with invalid name", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.Environment{Name: "Staging Env"}
+		err = repo.Create(context.Background(), &actual)
+		require.Error(t, err)
+	})
+
+	t.Run("Delete protected record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "protected"}).
+				AddRow(1, "prod", true))
+		sqlMock.ExpectRollback()
+		err = repo.Delete(context.Background(), 1)
+		require.ErrorIs(t, err, entity.ErrEnvProtected)
+	})
+
+	t.Run("Delete record in use", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "protected"}).
+				AddRow(1, "dev", false))
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `system_config`").
+			WithArgs("dev").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		sqlMock.ExpectRollback()
+		err = repo.Delete(context.Background(), 1)
+		require.ErrorIs(t, err, entity.ErrEnvInUse)
+	})
+
+	t.Run("Delete unused record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "protected"}).
+				AddRow(1, "dev", false))
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `system_config`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		sqlMock.ExpectExec("DELETE FROM `environment`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectCommit()
+		err = repo.Delete(context.Background(), 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("Update not existing record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectExec("UPDATE `environment`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		err = repo.Update(context.Background(), &entity.Environment{ID: 1, Name: "dev"})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewEnvironmentRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `environment` ORDER BY sort_order,id").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "sort_order"}).
+				AddRow(1, "dev", 10).
+				AddRow(2, "prod", 60))
+		actuals, err := repo.List(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, len(actuals))
+		require.Equal(t, entity.EnvProd, actuals[1].Name)
+	})
+}
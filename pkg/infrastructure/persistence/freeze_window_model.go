@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// FreezeWindowModel is a DO used to map the entity to the database.
+// An empty tenant, env or type matches all system configs.
+type FreezeWindowModel struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Tenant      string
+	Env         string
+	Type        string
+	StartAt     time.Time
+	EndAt       time.Time
+	Description string
+	Creator     string
+	Modifier    string
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *FreezeWindowModel) TableName() string {
+	return "freeze_window"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *FreezeWindowModel) ToEntity() (*entity.FreezeWindow, error) {
+	if m == nil {
+		return nil, ErrFreezeWindowModelNil
+	}
+
+	return &entity.FreezeWindow{
+		ID:          m.ID,
+		Name:        m.Name,
+		Tenant:      m.Tenant,
+		Env:         entity.Env(m.Env),
+		Type:        m.Type,
+		StartAt:     m.StartAt,
+		EndAt:       m.EndAt,
+		Description: m.Description,
+		Creator:     m.Creator,
+		Modifier:    m.Modifier,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *FreezeWindowModel) FromEntity(e *entity.FreezeWindow) error {
+	if m == nil {
+		return ErrFreezeWindowModelNil
+	}
+
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Tenant = e.Tenant
+	m.Env = string(e.Env)
+	m.Type = e.Type
+	m.StartAt = e.StartAt
+	m.EndAt = e.EndAt
+	m.Description = e.Description
+	m.Creator = e.Creator
+	m.Modifier = e.Modifier
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+
+	return nil
+}
+
+// FreezeOverrideModel is a DO used to map the entity to the database.
+type FreezeOverrideModel struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	FreezeWindowID uint
+	SystemConfigID uint
+	Action         string
+	Reason         string
+	Operator       string
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *FreezeOverrideModel) TableName() string {
+	return "freeze_override"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *FreezeOverrideModel) ToEntity() (*entity.FreezeOverride, error) {
+	if m == nil {
+		return nil, ErrFreezeOverrideModelNil
+	}
+
+	return &entity.FreezeOverride{
+		ID:             m.ID,
+		FreezeWindowID: m.FreezeWindowID,
+		SystemConfigID: m.SystemConfigID,
+		Action:         m.Action,
+		Reason:         m.Reason,
+		Operator:       m.Operator,
+		CreatedAt:      m.CreatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *FreezeOverrideModel) FromEntity(e *entity.FreezeOverride) error {
+	if m == nil {
+		return ErrFreezeOverrideModelNil
+	}
+
+	m.ID = e.ID
+	m.FreezeWindowID = e.FreezeWindowID
+	m.SystemConfigID = e.SystemConfigID
+	m.Action = e.Action
+	m.Reason = e.Reason
+	m.Operator = e.Operator
+	m.CreatedAt = e.CreatedAt
+
+	return nil
+}
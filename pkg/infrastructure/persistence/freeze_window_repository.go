@@ -0,0 +1,243 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+// The freezeWindowRepository type implements the repository.FreezeWindowRepository interface.
+// If the freezeWindowRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.FreezeWindowRepository = &freezeWindowRepository{}
+
+// freezeWindowRepository is a repository that stores freeze windows in a gorm database.
+type freezeWindowRepository struct {
+	// db is the underlying gorm database where freeze windows are stored.
+	db *gorm.DB
+}
+
+// NewFreezeWindowRepository creates a new freeze window repository.
+func NewFreezeWindowRepository(db *gorm.DB) repository.FreezeWindowRepository {
+	return &freezeWindowRepository{db: db}
+}
+
+// Create saves a freeze window to the repository.
+func (r *freezeWindowRepository) Create(ctx context.Context, dataEntity *entity.FreezeWindow) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel FreezeWindowModel
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Create new record in the store
+	err = dbFrom(ctx, r.db).Create(&dataModel).Error
+	if err != nil {
+		return err
+	}
+
+	// Map fresh record's data into Entity
+	newEntity, err := dataModel.ToEntity()
+	if err != nil {
+		return err
+	}
+	*dataEntity = *newEntity
+
+	return nil
+}
+
+// Delete removes a freeze window from the repository.
+func (r *freezeWindowRepository) Delete(ctx context.Context, id uint) error {
+	result := dbFrom(ctx, r.db).Delete(&FreezeWindowModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Update updates an existing freeze window in the repository.
+func (r *freezeWindowRepository) Update(ctx context.Context, dataEntity *entity.FreezeWindow) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel FreezeWindowModel
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Select the columns explicitly so that a scope can be widened to all
+	result := dbFrom(ctx, r.db).
+		Model(&dataModel).
+		Select("name", "tenant", "env", "type", "start_at", "end_at", "description", "modifier").
+		Updates(&dataModel)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Get retrieves a freeze window by its ID.
+func (r *freezeWindowRepository) Get(ctx context.Context, id uint) (*entity.FreezeWindow, error) {
+	var dataModel FreezeWindowModel
+	err := dbFrom(ctx, r.db).First(&dataModel, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// Find returns a list of freeze windows in the repository.
+func (r *freezeWindowRepository) Find(ctx context.Context, query repository.Query) ([]*entity.FreezeWindow, error) {
+	var dataModels []FreezeWindowModel
+	db := dbFrom(ctx, r.db)
+	if query.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+query.Keyword+"%")
+	}
+	if err := db.
+		Order("start_at DESC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	return freezeWindowsToEntities(dataModels)
+}
+
+// FindActive returns the freeze windows covering the given scope at the
+// given time.
+func (r *freezeWindowRepository) FindActive(ctx context.Context, tenant string, env entity.Env, typ string, at time.Time) ([]*entity.FreezeWindow, error) {
+	dataModels, err := findActiveFreezeWindows(dbFrom(ctx, r.db), tenant, env, typ, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return freezeWindowsToEntities(dataModels)
+}
+
+// FindOverrides returns the overrides recorded for a freeze window.
+func (r *freezeWindowRepository) FindOverrides(ctx context.Context, windowID uint, query repository.Query) ([]*entity.FreezeOverride, error) {
+	var dataModels []*FreezeOverrideModel
+	if err := dbFrom(ctx, r.db).
+		Where("freeze_window_id = ?", windowID).
+		Order("id DESC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	dataEntities := make([]*entity.FreezeOverride, 0, len(dataModels))
+	for _, model := range dataModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", model.ID)
+		}
+
+		dataEntities = append(dataEntities, newEntity)
+	}
+	return dataEntities, nil
+}
+
+// findActiveFreezeWindows returns the freeze windows covering the given
+// scope at the given time.
+func findActiveFreezeWindows(tx *gorm.DB, tenant string, env entity.Env, typ string, at time.Time) ([]FreezeWindowModel, error) {
+	var dataModels []FreezeWindowModel
+	err := tx.
+		Where("start_at <= ? AND end_at > ?", at, at).
+		Where("tenant = '' OR tenant = ?", tenant).
+		Where("env = '' OR env = ?", string(env)).
+		Where("type = '' OR type = ?", typ).
+		Find(&dataModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModels, nil
+}
+
+// checkFreeze returns entity.ErrConfigFrozen if the system configs of the
+// given scope are frozen and the context doesn't carry a freeze override.
+// Otherwise it returns the bypassed freeze windows, which have to be passed
+// to recordFreezeOverrides once the system config is written.
+func checkFreeze(ctx context.Context, tx *gorm.DB, tenant string, env entity.Env, typ string) ([]FreezeWindowModel, error) {
+	windows, err := findActiveFreezeWindows(tx, tenant, env, typ, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	if override := repository.FreezeOverrideFrom(ctx); override == nil {
+		names := make([]string, 0, len(windows))
+		for _, window := range windows {
+			names = append(names, window.Name)
+		}
+		return nil, errors.Wrapf(entity.ErrConfigFrozen, "%s/%s/%s is frozen by %s",
+			tenant, env, typ, strings.Join(names, ", "))
+	}
+
+	return windows, nil
+}
+
+// recordFreezeOverrides records the override of the context for each of the
+// bypassed freeze windows.
+func recordFreezeOverrides(ctx context.Context, tx *gorm.DB, windows []FreezeWindowModel, systemConfigID uint, action string) error {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	override := repository.FreezeOverrideFrom(ctx)
+	dataModels := make([]FreezeOverrideModel, 0, len(windows))
+	for _, window := range windows {
+		dataModels = append(dataModels, FreezeOverrideModel{
+			FreezeWindowID: window.ID,
+			SystemConfigID: systemConfigID,
+			Action:         action,
+			Reason:         override.Reason,
+			Operator:       override.Operator,
+		})
+	}
+
+	return tx.Create(&dataModels).Error
+}
+
+// freezeWindowsToEntities converts the DOs to entities.
+func freezeWindowsToEntities(dataModels []FreezeWindowModel) ([]*entity.FreezeWindow, error) {
+	dataEntities := make([]*entity.FreezeWindow, 0, len(dataModels))
+	for i := range dataModels {
+		newEntity, err := dataModels[i].ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", dataModels[i].ID)
+		}
+
+		dataEntities = append(dataEntities, newEntity)
+	}
+
+	return dataEntities, nil
+}
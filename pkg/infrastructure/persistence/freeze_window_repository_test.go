@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFreezeWindowRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewFreezeWindowRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		startAt := time.Now()
+		actual := entity.FreezeWindow{
+			Name:    "spring festival",
+			Env:     entity.EnvProd,
+			StartAt: startAt,
+			EndAt:   startAt.Add(7 * 24 * time.Hour),
+		}
+		sqlMock.ExpectExec("INSERT INTO `freeze_window`").
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		err = repo.Create(context.Background(), &actual)
+		require.NoError(t, err)
+		require.Equal(t, uint(2), actual.ID)
+	})
+
+	t.Run("Create with end before start", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewFreezeWindowRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		startAt := time.Now()
+		actual := entity.FreezeWindow{Name: "spring festival", StartAt: startAt, EndAt: startAt}
+		err = repo.Create(context.Background(), &actual)
+		require.Error(t, err)
+	})
+
+	t.Run("Delete not existing record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewFreezeWindowRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectExec("DELETE FROM `freeze_window`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		err = repo.Delete(context.Background(), 2)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("FindActive", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewFreezeWindowRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		now := time.Now()
+		sqlMock.ExpectQuery("SELECT \\* FROM `freeze_window` WHERE \\(start_at <= \\? AND end_at > \\?\\) AND \\(tenant = '' OR tenant = \\?\\) AND \\(env = '' OR env = \\?\\) AND \\(type = '' OR type = \\?\\)").
+			WithArgs(now, now, "MAIN_SITE", "prod", "cache").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "env"}).
+				AddRow(2, "spring festival", "prod"))
+		actual, err := repo.FindActive(context.Background(), "MAIN_SITE", entity.EnvProd, "cache", now)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		require.Equal(t, "spring festival", actual[0].Name)
+		require.Empty(t, actual[0].Tenant)
+	})
+}
@@ -0,0 +1,268 @@
+package persistence
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLabelSelectorNotSupported means the repository can't filter by labels.
+var ErrLabelSelectorNotSupported = errors.New("label selector is not supported")
+
+// Model is the constraint of the DOs stored by a Repository: a pointer to
+// the model struct M, which converts from and to the entity E.
+type Model[E any, M any] interface {
+	*M
+	// ToEntity converts the DO to an entity.
+	ToEntity() (*E, error)
+	// FromEntity converts an entity to a DO.
+	FromEntity(e *E) error
+}
+
+// RepositoryOption configures a Repository.
+type RepositoryOption func(o *repositoryOptions)
+
+type repositoryOptions struct {
+	keywordColumns []string
+	order          string
+}
+
+// WithKeywordColumns searches the keyword of the queries in the columns.
+func WithKeywordColumns(columns ...string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.keywordColumns = columns
+	}
+}
+
+// WithOrder orders the found entities by the SQL order, e.g. "name, id".
+// They're ordered by ID by default.
+func WithOrder(order string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.order = order
+	}
+}
+
+// Repository is a generic repository that stores the entities E in a gorm
+// database as the models M, through the ToEntity and FromEntity methods of
+// the models. PM is the pointer type of M and is inferred by
+// NewRepository. The entities implementing Validate() error are validated
+// before they're written.
+type Repository[E any, M any, PM Model[E, M]] struct {
+	// db is the underlying gorm database where entities are stored.
+	db *gorm.DB
+	repositoryOptions
+}
+
+// NewRepository creates a new repository of the entity E stored as the
+// model M.
+//
+// Example:
+//
+//	repo := persistence.NewRepository[entity.Widget, persistence.WidgetModel](db,
+//		persistence.WithKeywordColumns("name", "description"))
+func NewRepository[E any, M any, PM Model[E, M]](db *gorm.DB, opts ...RepositoryOption) repository.Repository[E] {
+	r := &Repository[E, M, PM]{
+		db:                db,
+		repositoryOptions: repositoryOptions{order: "id"},
+	}
+	for _, opt := range opts {
+		opt(&r.repositoryOptions)
+	}
+	return r
+}
+
+// Create saves an entity to the repository.
+func (r *Repository[E, M, PM]) Create(ctx context.Context, dataEntity *E) error {
+	err := validate(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	dataModel := PM(new(M))
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Create new record in the store
+	err = dbFrom(ctx, r.db).Omit(clause.Associations).Create(dataModel).Error
+	if err != nil {
+		return err
+	}
+
+	// Map fresh record's data into Entity
+	newEntity, err := dataModel.ToEntity()
+	if err != nil {
+		return err
+	}
+	*dataEntity = *newEntity
+
+	return nil
+}
+
+// Delete removes an entity from the repository.
+func (r *Repository[E, M, PM]) Delete(ctx context.Context, id uint) error {
+	result := dbFrom(ctx, r.db).Delete(PM(new(M)), id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Update updates an existing entity in the repository, the zero fields are
+// left unchanged. The entity is refreshed with the updated record.
+func (r *Repository[E, M, PM]) Update(ctx context.Context, dataEntity *E) error {
+	err := validate(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	dataModel := PM(new(M))
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+	id, err := primaryKey(dbFrom(ctx, r.db), dataModel)
+	if err != nil {
+		return err
+	}
+
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current := PM(new(M))
+		err := tx.First(current, id).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(current).Omit(clause.Associations).Updates(dataModel).Error
+		if err != nil {
+			return err
+		}
+
+		// Map updated record's data into Entity
+		err = tx.First(current, id).Error
+		if err != nil {
+			return err
+		}
+		newEntity, err := current.ToEntity()
+		if err != nil {
+			return err
+		}
+		*dataEntity = *newEntity
+
+		return nil
+	})
+}
+
+// Get retrieves an entity by its ID.
+func (r *Repository[E, M, PM]) Get(ctx context.Context, id uint) (*E, error) {
+	dataModel := PM(new(M))
+	err := readDB(ctx, r.db).First(dataModel, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// Find returns a list of specified entities in the repository.
+func (r *Repository[E, M, PM]) Find(ctx context.Context, query repository.Query) ([]*E, error) {
+	if len(query.LabelSelector) > 0 {
+		return nil, ErrLabelSelectorNotSupported
+	}
+
+	var dataModels []PM
+	if err := readDB(ctx, r.db).
+		Scopes(r.withKeyword(query.Keyword)).
+		Order(r.order).
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	dataEntities := make([]*E, 0, len(dataModels))
+	for i, model := range dataModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (index: %d) to entity", query.Offset+i)
+		}
+
+		dataEntities = append(dataEntities, newEntity)
+	}
+	return dataEntities, nil
+}
+
+// Count returns the total of entities matching the query.
+func (r *Repository[E, M, PM]) Count(ctx context.Context, query repository.Query) (int, error) {
+	if len(query.LabelSelector) > 0 {
+		return 0, ErrLabelSelectorNotSupported
+	}
+
+	var total int64
+	err := readDB(ctx, r.db).
+		Model(PM(new(M))).
+		Scopes(r.withKeyword(query.Keyword)).
+		Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(total), nil
+}
+
+// withKeyword returns a gorm scope which filters the records containing the
+// keyword in any of the keyword columns.
+func (r *Repository[E, M, PM]) withKeyword(keyword string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if keyword == "" || len(r.keywordColumns) == 0 {
+			return db
+		}
+
+		conditions := make([]string, 0, len(r.keywordColumns))
+		args := make([]any, 0, len(r.keywordColumns))
+		for _, column := range r.keywordColumns {
+			conditions = append(conditions, column+" LIKE ?")
+			args = append(args, "%"+keyword+"%")
+		}
+		return db.Where(strings.Join(conditions, " OR "), args...)
+	}
+}
+
+// validate validates the entity if it implements Validate() error.
+func validate(dataEntity any) error {
+	if v, ok := dataEntity.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// primaryKey returns the primary key value of the model, or
+// gorm.ErrRecordNotFound if it's zero.
+func primaryKey(db *gorm.DB, model any) (any, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil, errors.Errorf("table %s has no primary key", stmt.Schema.Table)
+	}
+
+	value, zero := field.ValueOf(db.Statement.Context, reflect.ValueOf(model).Elem())
+	if zero {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,138 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// widget is the entity of the generic repository tests.
+type widget struct {
+	ID          uint
+	Name        string
+	Description string
+	UpdatedAt   time.Time
+}
+
+func (w *widget) Validate() error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// widgetModel is the soft deleted DO of widgets.
+type widgetModel struct {
+	gorm.Model
+	Name        string
+	Description string
+}
+
+func (m *widgetModel) TableName() string {
+	return "widget"
+}
+
+func (m *widgetModel) ToEntity() (*widget, error) {
+	return &widget{ID: m.ID, Name: m.Name, Description: m.Description, UpdatedAt: m.UpdatedAt}, nil
+}
+
+func (m *widgetModel) FromEntity(e *widget) error {
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Description = e.Description
+	return nil
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func(t *testing.T) repository.Repository[widget] {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+		require.NoError(t, err)
+		t.Cleanup(func() { CloseDB(t, db) })
+		require.NoError(t, db.AutoMigrate(&widgetModel{}))
+		return NewRepository[widget, widgetModel](db, WithKeywordColumns("name", "description"), WithOrder("name, id"))
+	}
+
+	t.Run("Create and get", func(t *testing.T) {
+		repo := newRepo(t)
+		w := &widget{Name: "gear", Description: "a small gear"}
+		require.NoError(t, repo.Create(ctx, w))
+		require.NotZero(t, w.ID)
+
+		actual, err := repo.Get(ctx, w.ID)
+		require.NoError(t, err)
+		require.Equal(t, "a small gear", actual.Description)
+
+		require.Error(t, repo.Create(ctx, &widget{}))
+		_, err = repo.Get(ctx, 100)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		w := &widget{Name: "gear", Description: "a small gear"}
+		require.NoError(t, repo.Create(ctx, w))
+
+		// The zero fields are left unchanged and the entity is refreshed
+		updated := &widget{ID: w.ID, Name: "cog"}
+		require.NoError(t, repo.Update(ctx, updated))
+		require.Equal(t, "a small gear", updated.Description)
+		require.False(t, updated.UpdatedAt.IsZero())
+
+		err := repo.Update(ctx, &widget{ID: 100, Name: "cog"})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		err = repo.Update(ctx, &widget{Name: "cog"})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		w := &widget{Name: "gear"}
+		require.NoError(t, repo.Create(ctx, w))
+		require.NoError(t, repo.Delete(ctx, w.ID))
+
+		_, err := repo.Get(ctx, w.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.ErrorIs(t, repo.Delete(ctx, w.ID), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Find and count", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, w := range []*widget{
+			{Name: "spring", Description: "steel"},
+			{Name: "gear", Description: "brass"},
+			{Name: "bolt", Description: "steel gear pin"},
+			{Name: "nut"},
+		} {
+			require.NoError(t, repo.Create(ctx, w))
+		}
+		deleted := &widget{Name: "gear wheel"}
+		require.NoError(t, repo.Create(ctx, deleted))
+		require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+		actual, err := repo.Find(ctx, repository.Query{Limit: 10, Keyword: "gear"})
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		require.Equal(t, "bolt", actual[0].Name)
+		require.Equal(t, "gear", actual[1].Name)
+
+		actual, err = repo.Find(ctx, repository.Query{Offset: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"gear", "nut"}, []string{actual[0].Name, actual[1].Name})
+
+		total, err := repo.Count(ctx, repository.Query{Keyword: "steel", Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+
+		_, err = repo.Find(ctx, repository.Query{Limit: 10, LabelSelector: labelutil.MustParse("team=a")})
+		require.ErrorIs(t, err, ErrLabelSelectorNotSupported)
+	})
+}
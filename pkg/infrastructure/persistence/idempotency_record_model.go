@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// IdempotencyRecordModel is a DO used to map the entity to the database.
+type IdempotencyRecordModel struct {
+	ID          uint   `gorm:"primarykey"`
+	Key         string `gorm:"column:idempotency_key"`
+	Principal   string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *IdempotencyRecordModel) TableName() string {
+	return "idempotency_record"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *IdempotencyRecordModel) ToEntity() (*entity.IdempotencyRecord, error) {
+	if m == nil {
+		return nil, ErrIdempotencyRecordModelNil
+	}
+
+	return &entity.IdempotencyRecord{
+		Key:         m.Key,
+		Principal:   m.Principal,
+		Fingerprint: m.Fingerprint,
+		StatusCode:  m.StatusCode,
+		ContentType: m.ContentType,
+		Body:        m.Body,
+		CreatedAt:   m.CreatedAt,
+		ExpiresAt:   m.ExpiresAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *IdempotencyRecordModel) FromEntity(e *entity.IdempotencyRecord) error {
+	if m == nil {
+		return ErrIdempotencyRecordModelNil
+	}
+
+	m.Key = e.Key
+	m.Principal = e.Principal
+	m.Fingerprint = e.Fingerprint
+	m.StatusCode = e.StatusCode
+	m.ContentType = e.ContentType
+	m.Body = e.Body
+	m.CreatedAt = e.CreatedAt
+	m.ExpiresAt = e.ExpiresAt
+
+	return nil
+}
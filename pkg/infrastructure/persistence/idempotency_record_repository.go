@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+// The idempotencyRecordRepository type implements the repository.IdempotencyRecordRepository interface.
+// If the idempotencyRecordRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.IdempotencyRecordRepository = &idempotencyRecordRepository{}
+
+// idempotencyRecordRepository is a repository that stores the responses of the requests sent with an idempotency key
+// in a gorm database. The records are always read from the primary, as a lagging replica would miss the request
+// in progress.
+type idempotencyRecordRepository struct {
+	// db is the underlying gorm database where records are stored.
+	db *gorm.DB
+}
+
+// NewIdempotencyRecordRepository creates a new idempotency record repository.
+func NewIdempotencyRecordRepository(db *gorm.DB) repository.IdempotencyRecordRepository {
+	return &idempotencyRecordRepository{db: db}
+}
+
+// Create records a request in progress.
+func (r *idempotencyRecordRepository) Create(ctx context.Context, dataEntity *entity.IdempotencyRecord) error {
+	var dataModel IdempotencyRecordModel
+	if err := dataModel.FromEntity(dataEntity); err != nil {
+		return err
+	}
+
+	err := dbFrom(ctx, r.db).Create(&dataModel).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errors.Wrapf(entity.ErrIdempotencyKeyInUse, "%s", dataEntity.Key)
+	}
+	if err != nil {
+		return err
+	}
+
+	dataEntity.CreatedAt = dataModel.CreatedAt
+	return nil
+}
+
+// Get retrieves the record of the key of the principal.
+func (r *idempotencyRecordRepository) Get(ctx context.Context, principal, key string) (*entity.IdempotencyRecord, error) {
+	var dataModel IdempotencyRecordModel
+	err := dbFrom(ctx, r.db).
+		Where("principal = ? AND idempotency_key = ?", principal, key).
+		First(&dataModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// Replace records a request in progress in place of the expired record of
+// its key. The record is only replaced if it's still expired, so that only
+// one of the concurrent retries takes it over.
+func (r *idempotencyRecordRepository) Replace(ctx context.Context, dataEntity *entity.IdempotencyRecord, now time.Time) error {
+	result := dbFrom(ctx, r.db).
+		Model(&IdempotencyRecordModel{}).
+		Where("principal = ? AND idempotency_key = ? AND expires_at <= ?", dataEntity.Principal, dataEntity.Key, now).
+		Updates(map[string]any{
+			"fingerprint":  dataEntity.Fingerprint,
+			"status_code":  0,
+			"content_type": "",
+			"body":         nil,
+			"created_at":   now,
+			"expires_at":   dataEntity.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(entity.ErrIdempotencyKeyInUse, "%s", dataEntity.Key)
+	}
+
+	dataEntity.CreatedAt = now
+	return nil
+}
+
+// Extend moves the expiration of the request in progress to the time.
+func (r *idempotencyRecordRepository) Extend(ctx context.Context, principal, key string, expiresAt time.Time) error {
+	result := dbFrom(ctx, r.db).
+		Model(&IdempotencyRecordModel{}).
+		Where("principal = ? AND idempotency_key = ? AND status_code = 0", principal, key).
+		Update("expires_at", expiresAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Complete records the response of the request in progress and its
+// expiration time.
+func (r *idempotencyRecordRepository) Complete(ctx context.Context, dataEntity *entity.IdempotencyRecord) error {
+	result := dbFrom(ctx, r.db).
+		Model(&IdempotencyRecordModel{}).
+		Where("principal = ? AND idempotency_key = ?", dataEntity.Principal, dataEntity.Key).
+		Updates(map[string]any{
+			"status_code":  dataEntity.StatusCode,
+			"content_type": dataEntity.ContentType,
+			"body":         dataEntity.Body,
+			"expires_at":   dataEntity.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the record of the key of the principal.
+func (r *idempotencyRecordRepository) Delete(ctx context.Context, principal, key string) error {
+	return dbFrom(ctx, r.db).
+		Where("principal = ? AND idempotency_key = ?", principal, key).
+		Delete(&IdempotencyRecordModel{}).Error
+}
+
+// DeleteExpired removes the records expired at the time.
+func (r *idempotencyRecordRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result := dbFrom(ctx, r.db).
+		Where("expires_at <= ?", now).
+		Delete(&IdempotencyRecordModel{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
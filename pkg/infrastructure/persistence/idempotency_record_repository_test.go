@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyRecordRepository(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "app.db") + "?_pragma=busy_timeout(5000)"
+	db := openMigratedDB(t, "sqlite", sqlite.Open(dsn))
+	repo := NewIdempotencyRecordRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	newRecord := func(principal, key string, expiresAt time.Time) *entity.IdempotencyRecord {
+		return &entity.IdempotencyRecord{
+			Key:         key,
+			Principal:   principal,
+			Fingerprint: "fingerprint",
+			ExpiresAt:   expiresAt,
+		}
+	}
+
+	t.Run("Create and complete", func(t *testing.T) {
+		record := newRecord("alice", "key-1", now.Add(time.Hour))
+		require.NoError(t, repo.Create(ctx, record))
+		require.False(t, record.CreatedAt.IsZero())
+
+		// The same key of the principal is in use, not of another one
+		require.ErrorIs(t, repo.Create(ctx, newRecord("alice", "key-1", now.Add(time.Hour))), entity.ErrIdempotencyKeyInUse)
+		require.NoError(t, repo.Create(ctx, newRecord("bob", "key-1", now.Add(time.Hour))))
+
+		actual, err := repo.Get(ctx, "alice", "key-1")
+		require.NoError(t, err)
+		require.False(t, actual.Completed())
+
+		record.StatusCode = 201
+		record.ContentType = "application/json; charset=utf-8"
+		record.Body = []byte(`{"success":true}`)
+		record.ExpiresAt = now.Add(2 * time.Hour)
+		require.NoError(t, repo.Complete(ctx, record))
+		actual, err = repo.Get(ctx, "alice", "key-1")
+		require.NoError(t, err)
+		require.True(t, actual.Completed())
+		require.Equal(t, 201, actual.StatusCode)
+		require.Equal(t, "application/json; charset=utf-8", actual.ContentType)
+		require.Equal(t, []byte(`{"success":true}`), actual.Body)
+		require.Equal(t, "fingerprint", actual.Fingerprint)
+		require.WithinDuration(t, now.Add(2*time.Hour), actual.ExpiresAt, time.Second)
+	})
+
+	t.Run("Complete missing record", func(t *testing.T) {
+		err := repo.Complete(ctx, &entity.IdempotencyRecord{Principal: "alice", Key: "missing", StatusCode: 200})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Replace expired record", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, newRecord("alice", "key-5", now.Add(time.Hour))))
+		record := newRecord("alice", "key-5", now.Add(time.Minute))
+		record.Fingerprint = "another"
+		require.ErrorIs(t, repo.Replace(ctx, record, now), entity.ErrIdempotencyKeyInUse)
+
+		// Only the first of the retries finding the record expired replaces it
+		later := now.Add(2 * time.Hour)
+		record.ExpiresAt = later.Add(time.Minute)
+		require.NoError(t, repo.Replace(ctx, record, later))
+		require.ErrorIs(t, repo.Replace(ctx, newRecord("alice", "key-5", later.Add(time.Minute)), later), entity.ErrIdempotencyKeyInUse)
+		actual, err := repo.Get(ctx, "alice", "key-5")
+		require.NoError(t, err)
+		require.Equal(t, "another", actual.Fingerprint)
+		require.False(t, actual.Completed())
+		require.WithinDuration(t, later.Add(time.Minute), actual.ExpiresAt, time.Second)
+	})
+
+	t.Run("Extend", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, newRecord("alice", "key-6", now.Add(time.Minute))))
+		require.NoError(t, repo.Extend(ctx, "alice", "key-6", now.Add(time.Hour)))
+		actual, err := repo.Get(ctx, "alice", "key-6")
+		require.NoError(t, err)
+		require.WithinDuration(t, now.Add(time.Hour), actual.ExpiresAt, time.Second)
+
+		// The completed requests aren't in progress
+		require.ErrorIs(t, repo.Extend(ctx, "alice", "key-1", now.Add(time.Hour)), gorm.ErrRecordNotFound)
+		require.ErrorIs(t, repo.Extend(ctx, "alice", "missing", now.Add(time.Hour)), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, newRecord("alice", "key-2", now.Add(time.Hour))))
+		require.NoError(t, repo.Delete(ctx, "alice", "key-2"))
+		_, err := repo.Get(ctx, "alice", "key-2")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Delete expired", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, newRecord("alice", "key-3", now.Add(-time.Minute))))
+		require.NoError(t, repo.Create(ctx, newRecord("alice", "key-4", now.Add(-time.Second))))
+		deleted, err := repo.DeleteExpired(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, 2, deleted)
+		_, err = repo.Get(ctx, "alice", "key-1")
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,157 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// replicaSetPluginName is the name the ReplicaSet is registered with in the
+// primary gorm.DB.
+const replicaSetPluginName = "app:replicas"
+
+// replicaPingTimeout is how long a replica may take to answer the health
+// check before it's ejected.
+const replicaPingTimeout = 2 * time.Second
+
+// The ReplicaSet type implements the gorm.Plugin interface.
+var _ gorm.Plugin = &ReplicaSet{}
+
+// replica is a read replica of the primary database.
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// ReplicaSet spreads the read-only queries over the healthy read replicas of
+// the primary database in round robin. It's registered to the primary
+// gorm.DB as a plugin, so that the repositories created from the primary
+// find it.
+//
+// Example:
+//
+//	err = db.Use(persistence.NewReplicaSet(replica1, replica2))
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// NewReplicaSet creates a new ReplicaSet of the replicas, which are healthy
+// until the first health check.
+func NewReplicaSet(dbs ...*gorm.DB) *ReplicaSet {
+	s := &ReplicaSet{}
+	for i, db := range dbs {
+		r := &replica{name: fmt.Sprintf("replica #%d", i), db: db}
+		r.healthy.Store(true)
+		s.replicas = append(s.replicas, r)
+	}
+
+	return s
+}
+
+// ReplicasOf returns the ReplicaSet registered to the primary database, or
+// nil if it has no replicas.
+func ReplicasOf(db *gorm.DB) *ReplicaSet {
+	if db == nil || db.Config == nil {
+		return nil
+	}
+	s, _ := db.Config.Plugins[replicaSetPluginName].(*ReplicaSet)
+	return s
+}
+
+// Name returns the name of the gorm plugin.
+func (s *ReplicaSet) Name() string {
+	return replicaSetPluginName
+}
+
+// Initialize implements the gorm.Plugin interface, the replicas are opened
+// before being registered so there is nothing to do.
+func (s *ReplicaSet) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// CheckHealth pings every replica, ejecting the ones which don't answer and
+// bringing back the ones which recovered. It returns the number of healthy
+// replicas.
+func (s *ReplicaSet) CheckHealth(ctx context.Context) int {
+	healthy := 0
+	for _, r := range s.replicas {
+		err := ping(ctx, r.db)
+		if r.healthy.Swap(err == nil) != (err == nil) {
+			if err != nil {
+				logrus.Warnf("Ejected unhealthy %s: %v", r.name, err)
+			} else {
+				logrus.Infof("Restored recovered %s", r.name)
+			}
+		}
+		if err == nil {
+			healthy++
+		}
+	}
+
+	return healthy
+}
+
+// Stats returns the health and the connection pool statistics of every
+// replica by its name.
+func (s *ReplicaSet) Stats() map[string]any {
+	stats := make(map[string]any, len(s.replicas))
+	for _, r := range s.replicas {
+		replicaStats := map[string]any{"healthy": r.healthy.Load()}
+		if sqlDB, err := r.db.DB(); err == nil {
+			replicaStats["pool"] = sqlDB.Stats()
+		}
+		stats[r.name] = replicaStats
+	}
+
+	return stats
+}
+
+// pick returns the next healthy replica, or nil if none is healthy.
+func (s *ReplicaSet) pick() *gorm.DB {
+	n := uint64(len(s.replicas))
+	for i := uint64(0); i < n; i++ {
+		r := s.replicas[(s.next.Add(1)-1)%n]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+
+	return nil
+}
+
+// ping checks the replica answers within replicaPingTimeout.
+func ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// readDB returns the database the read-only queries go to: the transaction
+// of the context, a healthy replica of the primary, or the primary itself if
+// it has no healthy replicas or the context requires reading from the
+// primary.
+func readDB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := txFrom(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+
+	if replicas := ReplicasOf(db); replicas != nil && !repository.ReadPrimaryFrom(ctx) {
+		if replica := replicas.pick(); replica != nil {
+			return replica.WithContext(ctx)
+		}
+	}
+
+	return db.WithContext(ctx)
+}
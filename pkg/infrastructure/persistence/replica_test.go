@@ -0,0 +1,114 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// getMockReplica creates a mock database connection whose pings are
+// expected like the queries.
+func getMockReplica(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	fakeDB, sqlMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+
+	fakeGDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      fakeDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{SkipDefaultTransaction: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	return fakeGDB, sqlMock
+}
+
+func expectGet(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectQuery("SELECT \\* FROM `system_config`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "env"}).AddRow(1, "prod"))
+	sqlMock.ExpectQuery("SELECT \\* FROM `system_config_label`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "system_config_id", "label_key", "label_value"}))
+}
+
+func TestReplicaSet(t *testing.T) {
+	t.Run("Read from replicas in round robin", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+		replica1, replicaMock1 := getMockReplica(t)
+		defer CloseDB(t, replica1)
+		defer replicaMock1.ExpectClose()
+		replica2, replicaMock2 := getMockReplica(t)
+		defer CloseDB(t, replica2)
+		defer replicaMock2.ExpectClose()
+		require.NoError(t, fakeGDB.Use(NewReplicaSet(replica1, replica2)))
+		repo := NewSystemConfigRepository(fakeGDB)
+
+		expectGet(replicaMock1)
+		expectGet(replicaMock2)
+		for i := 0; i < 2; i++ {
+			_, err = repo.Get(context.Background(), 1)
+			require.NoError(t, err)
+		}
+		require.NoError(t, replicaMock1.ExpectationsWereMet())
+		require.NoError(t, replicaMock2.ExpectationsWereMet())
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Read your writes from the primary", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+		replica, replicaMock := getMockReplica(t)
+		defer CloseDB(t, replica)
+		defer replicaMock.ExpectClose()
+		require.NoError(t, fakeGDB.Use(NewReplicaSet(replica)))
+		repo := NewSystemConfigRepository(fakeGDB)
+
+		expectGet(sqlMock)
+		_, err = repo.Get(repository.WithReadPrimary(context.Background()), 1)
+		require.NoError(t, err)
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+		require.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("Eject unhealthy replicas", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+		replica, replicaMock := getMockReplica(t)
+		defer CloseDB(t, replica)
+		defer replicaMock.ExpectClose()
+		replicas := NewReplicaSet(replica)
+		require.NoError(t, fakeGDB.Use(replicas))
+		require.Same(t, replicas, ReplicasOf(fakeGDB))
+		repo := NewSystemConfigRepository(fakeGDB)
+
+		// Reads fall back to the primary while the replica is down
+		replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		require.Equal(t, 0, replicas.CheckHealth(context.Background()))
+		require.Equal(t, false, replicas.Stats()["replica #0"].(map[string]any)["healthy"])
+		expectGet(sqlMock)
+		_, err = repo.Get(context.Background(), 1)
+		require.NoError(t, err)
+
+		// and go back to the replica once it recovers
+		replicaMock.ExpectPing()
+		require.Equal(t, 1, replicas.CheckHealth(context.Background()))
+		require.Equal(t, true, replicas.Stats()["replica #0"].(map[string]any)["healthy"])
+		require.Contains(t, replicas.Stats()["replica #0"], "pool")
+		expectGet(replicaMock)
+		_, err = repo.Get(context.Background(), 1)
+		require.NoError(t, err)
+
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+		require.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+}
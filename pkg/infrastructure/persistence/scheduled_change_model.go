@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// ScheduledChangeModel is a DO used to map the entity to the database.
+type ScheduledChangeModel struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	SystemConfigID uint
+	// Target and Previous are system config snapshots encoded in JSON
+	Target      string
+	Previous    string
+	EffectiveAt time.Time
+	ExpiresAt   *time.Time
+	Status      string
+	Message     string
+	Creator     string
+	AppliedAt   *time.Time
+	RevertedAt  *time.Time
+	// LockOwner and LockedUntil hold the lease of the replica processing
+	// the change
+	LockOwner   string
+	LockedUntil *time.Time
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *ScheduledChangeModel) TableName() string {
+	return "system_config_schedule"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *ScheduledChangeModel) ToEntity() (*entity.ScheduledChange, error) {
+	if m == nil {
+		return nil, ErrScheduledChangeModelNil
+	}
+
+	target, err := decodeSnapshot(m.Target)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode target")
+	}
+	previous, err := decodeSnapshot(m.Previous)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode previous")
+	}
+
+	return &entity.ScheduledChange{
+		ID:             m.ID,
+		SystemConfigID: m.SystemConfigID,
+		Target:         target,
+		Previous:       previous,
+		EffectiveAt:    m.EffectiveAt,
+		ExpiresAt:      m.ExpiresAt,
+		Status:         entity.ScheduleStatus(m.Status),
+		Message:        m.Message,
+		Creator:        m.Creator,
+		AppliedAt:      m.AppliedAt,
+		RevertedAt:     m.RevertedAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *ScheduledChangeModel) FromEntity(e *entity.ScheduledChange) error {
+	if m == nil {
+		return ErrScheduledChangeModelNil
+	}
+
+	target, err := encodeSnapshot(e.Target)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode target")
+	}
+	previous, err := encodeSnapshot(e.Previous)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode previous")
+	}
+
+	m.ID = e.ID
+	m.SystemConfigID = e.SystemConfigID
+	m.Target = target
+	m.Previous = previous
+	m.EffectiveAt = e.EffectiveAt
+	m.ExpiresAt = e.ExpiresAt
+	m.Status = string(e.Status)
+	m.Message = e.Message
+	m.Creator = e.Creator
+	m.AppliedAt = e.AppliedAt
+	m.RevertedAt = e.RevertedAt
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+
+	return nil
+}
+
+// encodeSnapshot encodes the system config snapshot in JSON, a nil snapshot
+// is encoded as an empty string.
+func encodeSnapshot(s *entity.SystemConfig) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// decodeSnapshot decodes the system config snapshot from JSON, an empty
+// string is decoded as a nil snapshot.
+func decodeSnapshot(data string) (*entity.SystemConfig, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var s entity.SystemConfig
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
@@ -0,0 +1,206 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+// The scheduledChangeRepository type implements the repository.ScheduledChangeRepository interface.
+// If the scheduledChangeRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.ScheduledChangeRepository = &scheduledChangeRepository{}
+
+// scheduledChangeRepository is a repository that stores scheduled changes in a gorm database.
+//
+// Multiple replicas may process the scheduled changes at the same time, a
+// replica leases a change with a conditional update before processing it,
+// so that each change is processed by a single replica at a time.
+type scheduledChangeRepository struct {
+	// db is the underlying gorm database where scheduled changes are stored.
+	db *gorm.DB
+}
+
+// NewScheduledChangeRepository creates a new scheduled change repository.
+func NewScheduledChangeRepository(db *gorm.DB) repository.ScheduledChangeRepository {
+	return &scheduledChangeRepository{db: db}
+}
+
+// Create saves a scheduled change to the repository.
+func (r *scheduledChangeRepository) Create(ctx context.Context, dataEntity *entity.ScheduledChange) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel ScheduledChangeModel
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Create new record in the store
+	err = dbFrom(ctx, r.db).Create(&dataModel).Error
+	if err != nil {
+		return err
+	}
+
+	// Map fresh record's data into Entity
+	newEntity, err := dataModel.ToEntity()
+	if err != nil {
+		return err
+	}
+	*dataEntity = *newEntity
+
+	return nil
+}
+
+// Get retrieves a scheduled change by its ID.
+func (r *scheduledChangeRepository) Get(ctx context.Context, id uint) (*entity.ScheduledChange, error) {
+	var dataModel ScheduledChangeModel
+	err := dbFrom(ctx, r.db).First(&dataModel, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// FindPending returns the scheduled changes which are waiting to be applied
+// or reverted.
+func (r *scheduledChangeRepository) FindPending(ctx context.Context, query repository.Query) ([]*entity.ScheduledChange, error) {
+	var dataModels []*ScheduledChangeModel
+	if err := dbFrom(ctx, r.db).
+		Where("status = ? OR (status = ? AND expires_at IS NOT NULL)",
+			entity.ScheduleStatusPending, entity.ScheduleStatusApplied).
+		Order("effective_at").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	return scheduledChangesToEntities(dataModels)
+}
+
+// FindDue returns the unclaimed scheduled changes which have to be applied
+// or reverted at the given time.
+func (r *scheduledChangeRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledChange, error) {
+	var dataModels []*ScheduledChangeModel
+	if err := dbFrom(ctx, r.db).
+		Where("(status = ? AND effective_at <= ?) OR (status = ? AND expires_at <= ?)",
+			entity.ScheduleStatusPending, now, entity.ScheduleStatusApplied, now).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Order("id").
+		Limit(limit).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	return scheduledChangesToEntities(dataModels)
+}
+
+// Claim leases the scheduled change to the owner until the given time. The
+// expiry of a lease is checked against the clock of the caller.
+func (r *scheduledChangeRepository) Claim(ctx context.Context, dataEntity *entity.ScheduledChange, owner string, now, until time.Time) (bool, error) {
+	result := dbFrom(ctx, r.db).
+		Model(&ScheduledChangeModel{}).
+		Where("id = ? AND status = ?", dataEntity.ID, dataEntity.Status).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Updates(map[string]any{
+			"lock_owner":   owner,
+			"locked_until": until,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Complete saves the result of processing a claimed scheduled change and
+// releases the lease held by the owner.
+func (r *scheduledChangeRepository) Complete(ctx context.Context, dataEntity *entity.ScheduledChange, owner string) error {
+	// Map the data from Entity to DO
+	var dataModel ScheduledChangeModel
+	err := dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+	dataModel.LockOwner = ""
+	dataModel.LockedUntil = nil
+
+	// Select the columns explicitly so that the lease is cleared as well
+	result := dbFrom(ctx, r.db).
+		Model(&dataModel).
+		Where("lock_owner = ?", owner).
+		Select("previous", "status", "message", "applied_at", "reverted_at", "lock_owner", "locked_until").
+		Updates(&dataModel)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(entity.ErrScheduleLeaseLost, "scheduled change %d", dataEntity.ID)
+	}
+
+	return nil
+}
+
+// Cancel cancels a scheduled change which is waiting to be applied and
+// isn't being processed. An applied change is made due to be reverted now
+// instead, so the scheduler restores the values it has written.
+func (r *scheduledChangeRepository) Cancel(ctx context.Context, id uint) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&ScheduledChangeModel{}).
+			Where("id = ? AND status = ?", id, entity.ScheduleStatusPending).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Update("status", entity.ScheduleStatusCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		result = tx.Model(&ScheduledChangeModel{}).
+			Where("id = ? AND status = ?", id, entity.ScheduleStatusApplied).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Update("expires_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		// Tell a missing change from a change which can't be cancelled
+		var dataModel ScheduledChangeModel
+		err := tx.First(&dataModel, id).Error
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrapf(entity.ErrScheduleNotCancellable, "scheduled change %d is %s", id, dataModel.Status)
+	})
+}
+
+// scheduledChangesToEntities converts the DOs to entities.
+func scheduledChangesToEntities(dataModels []*ScheduledChangeModel) ([]*entity.ScheduledChange, error) {
+	dataEntities := make([]*entity.ScheduledChange, 0, len(dataModels))
+	for _, model := range dataModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", model.ID)
+		}
+
+		dataEntities = append(dataEntities, newEntity)
+	}
+
+	return dataEntities, nil
+}
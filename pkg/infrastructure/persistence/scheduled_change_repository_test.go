@@ -0,0 +1,197 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestScheduledChangeRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		var (
+			expectedID uint = 3
+			actual          = entity.ScheduledChange{
+				SystemConfigID: 1,
+				Target:         &entity.SystemConfig{ID: 1, Config: "{}"},
+				EffectiveAt:    time.Now().Add(time.Hour),
+				Status:         entity.ScheduleStatusPending,
+			}
+		)
+		sqlMock.ExpectExec("INSERT INTO `system_config_schedule`").
+			WillReturnResult(sqlmock.NewResult(int64(expectedID), 1))
+		err = repo.Create(context.Background(), &actual)
+		require.NoError(t, err)
+		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, "{}", actual.Target.Config)
+	})
+
+	t.Run("Create with expiry before effective time", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		effectiveAt := time.Now()
+		expiresAt := effectiveAt.Add(-time.Minute)
+		actual := entity.ScheduledChange{
+			SystemConfigID: 1,
+			Target:         &entity.SystemConfig{ID: 1},
+			EffectiveAt:    effectiveAt,
+			ExpiresAt:      &expiresAt,
+		}
+		err = repo.Create(context.Background(), &actual)
+		require.Error(t, err)
+	})
+
+	t.Run("FindDue", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_schedule` WHERE \\(\\(status = \\? AND effective_at <= \\?\\) OR \\(status = \\? AND expires_at <= \\?\\)\\) AND \\(locked_until IS NULL OR locked_until < \\?\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "system_config_id", "target", "status"}).
+				AddRow(3, 1, `{"id":1,"config":"{}"}`, "pending").
+				AddRow(4, 2, `{"id":2,"config":"{}"}`, "applied"))
+		actual, err := repo.FindDue(context.Background(), time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		require.Equal(t, entity.ScheduleStatusApplied, actual[1].Status)
+		require.Equal(t, uint(2), actual[1].Target.ID)
+	})
+
+	t.Run("Claim", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		change := &entity.ScheduledChange{ID: 3, Status: entity.ScheduleStatusPending}
+		now := time.Date(2023, 6, 1, 2, 0, 0, 0, time.UTC)
+		until := now.Add(time.Minute)
+		sqlMock.ExpectExec("UPDATE `system_config_schedule` SET .+ WHERE \\(id = \\? AND status = \\?\\) AND \\(locked_until IS NULL OR locked_until < \\?\\)").
+			WithArgs("replica-a", until, sqlmock.AnyArg(), 3, entity.ScheduleStatusPending, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		claimed, err := repo.Claim(context.Background(), change, "replica-a", now, until)
+		require.NoError(t, err)
+		require.True(t, claimed)
+	})
+
+	t.Run("Claim change leased by another owner", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		change := &entity.ScheduledChange{ID: 3, Status: entity.ScheduleStatusPending}
+		sqlMock.ExpectExec("UPDATE `system_config_schedule`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		claimed, err := repo.Claim(context.Background(), change, "replica-b", time.Now(), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.False(t, claimed)
+	})
+
+	t.Run("Complete after lease is lost", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		change := &entity.ScheduledChange{
+			ID:             3,
+			SystemConfigID: 1,
+			Target:         &entity.SystemConfig{ID: 1},
+			Status:         entity.ScheduleStatusApplied,
+		}
+		sqlMock.ExpectExec("UPDATE `system_config_schedule` SET .+ WHERE lock_owner = \\? AND `id` = \\?").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		err = repo.Complete(context.Background(), change, "replica-a")
+		require.ErrorIs(t, err, entity.ErrScheduleLeaseLost)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("UPDATE `system_config_schedule` SET `status`=\\?").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		sqlMock.ExpectCommit()
+		err = repo.Cancel(context.Background(), 3)
+		require.NoError(t, err)
+	})
+
+	t.Run("Cancel applied change", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("UPDATE `system_config_schedule` SET `status`=\\?").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectExec("UPDATE `system_config_schedule` SET `expires_at`=\\?").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		sqlMock.ExpectCommit()
+		err = repo.Cancel(context.Background(), 3)
+		require.NoError(t, err)
+	})
+
+	t.Run("Cancel reverted change", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("UPDATE `system_config_schedule`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectExec("UPDATE `system_config_schedule`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_schedule`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(3, "reverted"))
+		sqlMock.ExpectRollback()
+		err = repo.Cancel(context.Background(), 3)
+		require.ErrorIs(t, err, entity.ErrScheduleNotCancellable)
+	})
+
+	t.Run("Cancel not existing record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewScheduledChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("UPDATE `system_config_schedule`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectExec("UPDATE `system_config_schedule`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_schedule`").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		sqlMock.ExpectRollback()
+		err = repo.Cancel(context.Background(), 3)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
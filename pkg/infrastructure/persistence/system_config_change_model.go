@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// SystemConfigChangeModel is a DO used to map the entity to the database.
+type SystemConfigChangeModel struct {
+	Seq            uint64 `gorm:"primarykey"`
+	ChangedAt      time.Time
+	SystemConfigID uint
+	Tenant         string
+	Env            string
+	Type           string
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *SystemConfigChangeModel) TableName() string {
+	return "system_config_change"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *SystemConfigChangeModel) ToEntity() (*entity.SystemConfigChange, error) {
+	if m == nil {
+		return nil, ErrSystemConfigChangeModelNil
+	}
+
+	return &entity.SystemConfigChange{
+		Seq:            m.Seq,
+		SystemConfigID: m.SystemConfigID,
+		Tenant:         m.Tenant,
+		Env:            entity.Env(m.Env),
+		Type:           m.Type,
+		ChangedAt:      m.ChangedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *SystemConfigChangeModel) FromEntity(e *entity.SystemConfigChange) error {
+	if m == nil {
+		return ErrSystemConfigChangeModelNil
+	}
+
+	m.Seq = e.Seq
+	m.SystemConfigID = e.SystemConfigID
+	m.Tenant = e.Tenant
+	m.Env = string(e.Env)
+	m.Type = e.Type
+	m.ChangedAt = e.ChangedAt
+
+	return nil
+}
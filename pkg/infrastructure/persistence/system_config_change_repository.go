@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+// The systemConfigChangeRepository type implements the repository.SystemConfigChangeRepository interface.
+// If the systemConfigChangeRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.SystemConfigChangeRepository = &systemConfigChangeRepository{}
+
+// systemConfigChangeRepository is a repository that reads the change sequence of system configs from a gorm database.
+// The changes are always read from the primary, as a lagging replica would
+// hide the latest changes.
+type systemConfigChangeRepository struct {
+	// db is the underlying gorm database where changes are stored.
+	db *gorm.DB
+}
+
+// NewSystemConfigChangeRepository creates a new system config change repository.
+func NewSystemConfigChangeRepository(db *gorm.DB) repository.SystemConfigChangeRepository {
+	return &systemConfigChangeRepository{db: db}
+}
+
+// FindSince returns at most limit changes after the sequence number, oldest first.
+func (r *systemConfigChangeRepository) FindSince(ctx context.Context, seq uint64, limit int) ([]*entity.SystemConfigChange, error) {
+	var dataModels []*SystemConfigChangeModel
+	if err := dbFrom(ctx, r.db).
+		Where("seq > ?", seq).
+		Order("seq").
+		Limit(limit).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	dataEntities := make([]*entity.SystemConfigChange, 0, len(dataModels))
+	for _, model := range dataModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (Seq: %d) to entity", model.Seq)
+		}
+
+		dataEntities = append(dataEntities, newEntity)
+	}
+	return dataEntities, nil
+}
+
+// LatestSeq returns the sequence number of the latest change.
+func (r *systemConfigChangeRepository) LatestSeq(ctx context.Context) (uint64, error) {
+	var latest uint64
+	err := dbFrom(ctx, r.db).
+		Model(&SystemConfigChangeModel{}).
+		Select("COALESCE(MAX(seq), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return latest, nil
+}
+
+// DeleteBefore removes the changes made before the time.
+func (r *systemConfigChangeRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	result := dbFrom(ctx, r.db).
+		Where("changed_at < ?", before).
+		Delete(&SystemConfigChangeModel{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// recordChanges records a change of the system config for each distinct key
+// in the transaction, so that the caches of every replica drop the key.
+func recordChanges(tx *gorm.DB, systemConfigID uint, keys ...SystemConfigModel) error {
+	now := time.Now()
+	changes := make([]SystemConfigChangeModel, 0, len(keys))
+	for i, key := range keys {
+		if i > 0 && key.Tenant == keys[0].Tenant && key.Env == keys[0].Env && key.Type == keys[0].Type {
+			continue
+		}
+		changes = append(changes, SystemConfigChangeModel{
+			ChangedAt:      now,
+			SystemConfigID: systemConfigID,
+			Tenant:         key.Tenant,
+			Env:            key.Env,
+			Type:           key.Type,
+		})
+	}
+
+	return tx.Create(&changes).Error
+}
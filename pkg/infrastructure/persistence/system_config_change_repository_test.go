@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemConfigChangeRepository(t *testing.T) {
+	t.Run("FindSince", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_change` WHERE seq > \\? ORDER BY seq LIMIT 100").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"seq", "system_config_id", "tenant", "env", "type"}).
+				AddRow(8, 1, "MAIN_SITE", "prod", "redis").
+				AddRow(9, 2, "MAIN_SITE", "gray", "redis"))
+		actual, err := repo.FindSince(context.Background(), 7, 100)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		require.Equal(t, uint64(8), actual[0].Seq)
+		require.Equal(t, entity.EnvGray, actual[1].Env)
+	})
+
+	t.Run("LatestSeq", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT COALESCE\\(MAX\\(seq\\), 0\\) FROM `system_config_change`").
+			WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(42))
+		actual, err := repo.LatestSeq(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), actual)
+	})
+
+	t.Run("DeleteBefore", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigChangeRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectExec("DELETE FROM `system_config_change` WHERE changed_at < \\?").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		actual, err := repo.DeleteBefore(context.Background(), time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 3, actual)
+	})
+
+	t.Run("Record the keys of changed system configs", func(t *testing.T) {
+		dsn := filepath.Join(t.TempDir(), "app.db") + "?_pragma=busy_timeout(5000)"
+		db := openMigratedDB(t, "sqlite", sqlite.Open(dsn))
+		configs := NewSystemConfigRepository(db)
+		repo := NewSystemConfigChangeRepository(db)
+		ctx := context.Background()
+
+		config := &entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: "redis", Config: "{}"}
+		require.NoError(t, configs.Create(ctx, config))
+		latest, err := repo.LatestSeq(ctx)
+		require.NoError(t, err)
+
+		// Moving the system config changes both keys
+		require.NoError(t, configs.Update(ctx, &entity.SystemConfig{ID: config.ID, Tenant: "MAIN_SITE", Env: entity.EnvGray}))
+		require.NoError(t, configs.Delete(ctx, config.ID))
+		actual, err := repo.FindSince(ctx, latest, 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+		require.Equal(t, entity.EnvProd, actual[0].Env)
+		require.Equal(t, entity.EnvGray, actual[1].Env)
+		require.Equal(t, entity.EnvGray, actual[2].Env)
+		require.Equal(t, config.ID, actual[2].SystemConfigID)
+		require.Greater(t, actual[0].Seq, latest)
+
+		deleted, err := repo.DeleteBefore(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 4, deleted)
+	})
+}
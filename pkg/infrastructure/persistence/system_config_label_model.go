@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
+)
+
+// SystemConfigLabelModel is a DO used to map a single label of the system
+// config to the database.
+type SystemConfigLabelModel struct {
+	ID             uint `gorm:"primarykey"`
+	SystemConfigID uint
+	LabelKey       string
+	LabelValue     string
+	CreatedAt      time.Time
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *SystemConfigLabelModel) TableName() string {
+	return "system_config_label"
+}
+
+// labelsToModels converts the labels map to the label DOs, sorted by key.
+func labelsToModels(systemConfigID uint, labels map[string]string) []SystemConfigLabelModel {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	models := make([]SystemConfigLabelModel, 0, len(labels))
+	for _, key := range labelutil.SortedKeys(labels) {
+		models = append(models, SystemConfigLabelModel{
+			SystemConfigID: systemConfigID,
+			LabelKey:       key,
+			LabelValue:     labels[key],
+		})
+	}
+
+	return models
+}
+
+// labelsFromModels converts the label DOs to the labels map.
+func labelsFromModels(models []SystemConfigLabelModel) map[string]string {
+	if len(models) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(models))
+	for _, m := range models {
+		labels[m.LabelKey] = m.LabelValue
+	}
+
+	return labels
+}
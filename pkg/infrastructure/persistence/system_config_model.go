@@ -1,7 +1,6 @@
 package persistence
 
 import (
-	"github.com/elliotxx/errors"
 	"github.com/elliotxx/go-web-template/pkg/domain/entity"
 	"gorm.io/gorm"
 )
@@ -16,6 +15,8 @@ type SystemConfigModel struct {
 	Description string
 	Creator     string
 	Modifier    string
+	Labels      []SystemConfigLabelModel     `gorm:"foreignKey:SystemConfigID"`
+	References  []SystemConfigReferenceModel `gorm:"foreignKey:SystemConfigID"`
 }
 
 // The TableName method returns the name of the database table that the struct is mapped to.
@@ -24,23 +25,22 @@ func (m *SystemConfigModel) TableName() string {
 }
 
 // ToEntity converts the DO to an entity.
+// The env is taken as it is stored, it's checked against the environment
+// table on write, so a row whose environment was removed or renamed
+// out-of-band doesn't break reading the other rows.
 func (m *SystemConfigModel) ToEntity() (*entity.SystemConfig, error) {
 	if m == nil {
 		return nil, ErrSystemConfigModelNil
 	}
 
-	env, err := entity.ParseEnv(m.Env)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse env")
-	}
-
 	return &entity.SystemConfig{
 		ID:          m.ID,
 		Tenant:      m.Tenant,
-		Env:         env,
+		Env:         entity.Env(m.Env),
 		Type:        m.Type,
 		Config:      m.Config,
 		Description: m.Description,
+		Labels:      labelsFromModels(m.Labels),
 		Creator:     m.Creator,
 		Modifier:    m.Modifier,
 		CreatedAt:   m.CreatedAt,
@@ -60,6 +60,8 @@ func (m *SystemConfigModel) FromEntity(e *entity.SystemConfig) error {
 	m.Type = e.Type
 	m.Config = e.Config
 	m.Description = e.Description
+	m.Labels = labelsToModels(e.ID, e.Labels)
+	m.References = referencesToModels(e.ID, e.Config)
 	m.Creator = e.Creator
 	m.Modifier = e.Modifier
 	m.CreatedAt = e.CreatedAt
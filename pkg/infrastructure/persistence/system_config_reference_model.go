@@ -0,0 +1,51 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/util/refutil"
+)
+
+// SystemConfigReferenceModel is a DO used to map a system config referenced
+// by the content of another system config to the database. It indexes the
+// references so that the dependents of a system config can be queried.
+type SystemConfigReferenceModel struct {
+	ID             uint `gorm:"primarykey"`
+	SystemConfigID uint
+	RefTenant      string
+	RefEnv         string
+	RefType        string
+	CreatedAt      time.Time
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *SystemConfigReferenceModel) TableName() string {
+	return "system_config_reference"
+}
+
+// referencesToModels parses the references in the content and converts
+// them to the reference DOs, one per referenced system config.
+func referencesToModels(systemConfigID uint, content string) []SystemConfigReferenceModel {
+	refs := refutil.Parse(content)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	models := make([]SystemConfigReferenceModel, 0, len(refs))
+	seen := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		if _, ok := seen[ref.Key()]; ok {
+			continue
+		}
+		seen[ref.Key()] = struct{}{}
+
+		models = append(models, SystemConfigReferenceModel{
+			SystemConfigID: systemConfigID,
+			RefTenant:      ref.Tenant,
+			RefEnv:         ref.Env,
+			RefType:        ref.Type,
+		})
+	}
+
+	return models
+}
@@ -3,10 +3,12 @@ package persistence
 import (
 	"context"
 
+	"github.com/elliotxx/errors"
 	"github.com/elliotxx/go-web-template/pkg/domain/entity"
 	"github.com/elliotxx/go-web-template/pkg/domain/repository"
-	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // The systemConfigRepository type implements the repository.SystemConfigRepository interface.
@@ -39,9 +41,38 @@ func (r *systemConfigRepository) Create(ctx context.Context, dataEntity *entity.
 		return err
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// The environment must be defined in the environment table
+		err = ensureEnvExists(tx.WithContext(ctx), dataEntity.Env)
+		if err != nil {
+			return err
+		}
+
+		// The tenant must be writable and within its quotas
+		err = checkTenantQuota(tx.WithContext(ctx), dataEntity, true)
+		if err != nil {
+			return err
+		}
+
+		// The scope must not be frozen unless the freeze is overridden
+		windows, err := checkFreeze(ctx, tx.WithContext(ctx), dataEntity.Tenant, dataEntity.Env, dataEntity.Type)
+		if err != nil {
+			return err
+		}
+
 		// Create new record in the store
 		err = tx.WithContext(ctx).Create(&dataModel).Error
+		if err != nil {
+			return translateConflict(err, dataEntity)
+		}
+
+		err = recordFreezeOverrides(ctx, tx.WithContext(ctx), windows, dataModel.ID, entity.FreezeActionCreate)
+		if err != nil {
+			return err
+		}
+
+		// A cached not found of the key must be dropped
+		err = recordChanges(tx.WithContext(ctx), dataModel.ID, dataModel)
 		if err != nil {
 			return err
 		}
@@ -59,33 +90,156 @@ func (r *systemConfigRepository) Create(ctx context.Context, dataEntity *entity.
 
 // Delete removes a system config from the repository.
 func (r *systemConfigRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		var dataModel SystemConfigModel
 		err := tx.WithContext(ctx).First(&dataModel, id).Error
 		if err != nil {
 			return err
 		}
 
-		return tx.WithContext(ctx).Delete(&dataModel).Error
+		// Suspended tenants are read-only, configs of undefined tenants
+		// can still be cleaned up
+		_, err = getWritableTenant(tx.WithContext(ctx), dataModel.Tenant)
+		if err != nil && !errors.Is(err, entity.ErrTenantNotFound) {
+			return err
+		}
+
+		// The scope must not be frozen unless the freeze is overridden
+		windows, err := checkFreeze(ctx, tx.WithContext(ctx), dataModel.Tenant, entity.Env(dataModel.Env), dataModel.Type)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WithContext(ctx).Delete(&dataModel).Error
+		if err != nil {
+			return err
+		}
+
+		// A deleted config no longer depends on other configs
+		err = replaceReferences(tx.WithContext(ctx), dataModel.ID, nil)
+		if err != nil {
+			return err
+		}
+
+		err = recordFreezeOverrides(ctx, tx.WithContext(ctx), windows, dataModel.ID, entity.FreezeActionDelete)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(tx.WithContext(ctx), dataModel.ID, dataModel)
 	})
 }
 
 // Update updates an existing system config in the repository.
+// The labels are replaced as a whole unless they are nil.
 func (r *systemConfigRepository) Update(ctx context.Context, dataEntity *entity.SystemConfig) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
 	// Map the data from Entity to DO
 	var dataModel SystemConfigModel
-	err := dataModel.FromEntity(dataEntity)
+	err = dataModel.FromEntity(dataEntity)
 	if err != nil {
 		return err
 	}
 
-	return r.db.WithContext(ctx).Updates(&dataModel).Error
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var current SystemConfigModel
+		err := tx.First(&current, dataModel.ID).Error
+		if err != nil {
+			return err
+		}
+
+		// The environment must be defined in the environment table
+		err = ensureEnvExists(tx, dataEntity.Env)
+		if err != nil {
+			return err
+		}
+
+		// The tenant must be writable and within its quotas, a config moved
+		// from another tenant counts against the quota of the new tenant
+		movedTenant := current.Tenant != dataEntity.Tenant
+		if movedTenant {
+			_, err = getWritableTenant(tx, current.Tenant)
+			if err != nil && !errors.Is(err, entity.ErrTenantNotFound) {
+				return err
+			}
+		}
+		err = checkTenantQuota(tx, dataEntity, movedTenant)
+		if err != nil {
+			return err
+		}
+
+		// Neither the current nor the new scope may be frozen unless the
+		// freeze is overridden
+		windows, err := checkFreeze(ctx, tx, current.Tenant, entity.Env(current.Env), current.Type)
+		if err != nil {
+			return err
+		}
+		if current.Tenant != dataModel.Tenant || current.Env != dataModel.Env || current.Type != dataModel.Type {
+			moved, err := checkFreeze(ctx, tx, dataEntity.Tenant, dataEntity.Env, dataEntity.Type)
+			if err != nil {
+				return err
+			}
+			windows = append(windows, moved...)
+		}
+
+		// Labels live in a side table, so they are written separately
+		err = tx.Omit(clause.Associations).Updates(&dataModel).Error
+		if err != nil {
+			return translateConflict(err, dataEntity)
+		}
+
+		err = recordFreezeOverrides(ctx, tx, windows, dataModel.ID, entity.FreezeActionUpdate)
+		if err != nil {
+			return err
+		}
+
+		// Both the current and the new key of a moved config are changed,
+		// the zero fields of the update are left unchanged
+		moved := current
+		setIfNotEmpty(&moved.Tenant, dataModel.Tenant)
+		setIfNotEmpty(&moved.Env, dataModel.Env)
+		setIfNotEmpty(&moved.Type, dataModel.Type)
+		err = recordChanges(tx, dataModel.ID, current, moved)
+		if err != nil {
+			return err
+		}
+
+		// Re-index the references as the content may have changed
+		err = replaceReferences(tx, dataModel.ID, dataModel.References)
+		if err != nil {
+			return err
+		}
+
+		if dataEntity.Labels == nil {
+			return nil
+		}
+
+		return replaceLabels(tx, dataModel.ID, dataModel.Labels)
+	})
 }
 
 // Find retrieves a system config by its ID.
 func (r *systemConfigRepository) Get(ctx context.Context, id uint) (*entity.SystemConfig, error) {
 	var dataModel SystemConfigModel
-	err := r.db.WithContext(ctx).First(&dataModel, id).Error
+	err := readDB(ctx, r.db).Preload("Labels").First(&dataModel, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// GetByKey retrieves a system config by its tenant, env and type.
+func (r *systemConfigRepository) GetByKey(ctx context.Context, tenant string, env entity.Env, typ string) (*entity.SystemConfig, error) {
+	var dataModel SystemConfigModel
+	err := readDB(ctx, r.db).
+		Preload("Labels").
+		Where("tenant = ? AND env = ? AND type = ?", tenant, string(env), typ).
+		First(&dataModel).Error
 	if err != nil {
 		return nil, err
 	}
@@ -96,8 +250,10 @@ func (r *systemConfigRepository) Get(ctx context.Context, id uint) (*entity.Syst
 // Find returns a list of specified system configs in the repository.
 func (r *systemConfigRepository) Find(ctx context.Context, query repository.Query) ([]*entity.SystemConfig, error) {
 	var systemConfigModels []*SystemConfigModel
-	if err := r.db.WithContext(ctx).
-		Where("config LIKE ?", "%"+query.Keyword+"%").
+	if err := readDB(ctx, r.db).
+		Scopes(withQuery(query)).
+		Preload("Labels").
+		Order("id").
 		Limit(query.Limit).
 		Offset(query.Offset).
 		Find(&systemConfigModels).Error; err != nil {
@@ -116,13 +272,148 @@ func (r *systemConfigRepository) Find(ctx context.Context, query repository.Quer
 	return systemConfigEntities, nil
 }
 
-// Count returns the total of system configs.
-func (r *systemConfigRepository) Count(ctx context.Context) (int, error) {
+// Count returns the total of system configs matching the query.
+func (r *systemConfigRepository) Count(ctx context.Context, query repository.Query) (int, error) {
 	var total int64
-	err := r.db.WithContext(ctx).Model(&SystemConfigModel{}).Count(&total).Error
+	err := readDB(ctx, r.db).
+		Model(&SystemConfigModel{}).
+		Scopes(withQuery(query)).
+		Count(&total).Error
 	if err != nil {
 		return 0, err
 	}
 
 	return int(total), nil
 }
+
+// FindDependents returns the system configs whose content references the
+// system config of the given tenant, env and type.
+func (r *systemConfigRepository) FindDependents(ctx context.Context, tenant string, env entity.Env, typ string) ([]*entity.SystemConfig, error) {
+	reader := readDB(ctx, r.db)
+	sub := reader.
+		Model(&SystemConfigReferenceModel{}).
+		Select("system_config_id").
+		Where("ref_tenant = ? AND ref_env = ? AND ref_type = ?", tenant, string(env), typ)
+
+	var systemConfigModels []*SystemConfigModel
+	if err := reader.
+		Preload("Labels").
+		Where("id IN (?)", sub).
+		Order("id").
+		Find(&systemConfigModels).Error; err != nil {
+		return nil, err
+	}
+
+	systemConfigEntities := make([]*entity.SystemConfig, 0, len(systemConfigModels))
+	for _, model := range systemConfigModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", model.ID)
+		}
+
+		systemConfigEntities = append(systemConfigEntities, newEntity)
+	}
+	return systemConfigEntities, nil
+}
+
+// withQuery returns a gorm scope which filters system configs by the
+// keyword and the label selector of the query.
+func withQuery(query repository.Query) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Keyword != "" {
+			db = db.Where("config LIKE ?", "%"+query.Keyword+"%")
+		}
+
+		for _, req := range query.LabelSelector {
+			// Select the system configs which own the label
+			sub := db.Session(&gorm.Session{NewDB: true}).
+				Model(&SystemConfigLabelModel{}).
+				Select("system_config_id").
+				Where("label_key = ?", req.Key)
+			if len(req.Values) > 0 {
+				sub = sub.Where("label_value IN ?", req.Values)
+			}
+
+			switch req.Operator {
+			case labelutil.Equals, labelutil.In, labelutil.Exists:
+				db = db.Where("id IN (?)", sub)
+			case labelutil.NotEquals, labelutil.NotIn, labelutil.DoesNotExist:
+				db = db.Where("id NOT IN (?)", sub)
+			}
+		}
+
+		return db
+	}
+}
+
+// checkTenantQuota checks the tenant of the system config is writable and
+// the system config fits in the tenant quotas. The number of configs is
+// only checked when the system config is added to the tenant.
+func checkTenantQuota(tx *gorm.DB, dataEntity *entity.SystemConfig, added bool) error {
+	tenant, err := getWritableTenant(tx, dataEntity.Tenant)
+	if err != nil {
+		return err
+	}
+
+	err = tenant.CheckConfigSize(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	if !added || tenant.Quota.MaxConfigs == 0 {
+		return nil
+	}
+
+	var total int64
+	err = tx.Model(&SystemConfigModel{}).Where("tenant = ?", dataEntity.Tenant).Count(&total).Error
+	if err != nil {
+		return err
+	}
+
+	return tenant.CheckConfigQuota(int(total))
+}
+
+// translateConflict returns entity.ErrConfigConflict if the error is caused
+// by the unique key of tenant, env and type.
+func translateConflict(err error, dataEntity *entity.SystemConfig) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errors.Wrapf(entity.ErrConfigConflict, "%s/%s/%s", dataEntity.Tenant, dataEntity.Env, dataEntity.Type)
+	}
+	return err
+}
+
+// replaceLabels replaces all labels of the specified system config.
+func replaceLabels(tx *gorm.DB, systemConfigID uint, labels []SystemConfigLabelModel) error {
+	err := tx.Where("system_config_id = ?", systemConfigID).Delete(&SystemConfigLabelModel{}).Error
+	if err != nil {
+		return err
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return tx.Create(&labels).Error
+}
+
+// replaceReferences replaces all reference index entries of the specified
+// system config.
+func replaceReferences(tx *gorm.DB, systemConfigID uint, references []SystemConfigReferenceModel) error {
+	err := tx.Where("system_config_id = ?", systemConfigID).Delete(&SystemConfigReferenceModel{}).Error
+	if err != nil {
+		return err
+	}
+
+	if len(references) == 0 {
+		return nil
+	}
+
+	return tx.Create(&references).Error
+}
+
+// setIfNotEmpty sets the field to the value unless the value is empty.
+func setIfNotEmpty(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
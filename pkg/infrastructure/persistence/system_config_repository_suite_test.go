@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/assets"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository/repositorytest"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/migration"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openMigratedDB opens the database and applies the migrations of the
+// driver to it.
+func openMigratedDB(t *testing.T, driver string, dialector gorm.Dialector) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { CloseDB(t, db) })
+
+	migrations, err := migration.Load(assets.Migrations, path.Join("migrations", driver))
+	require.NoError(t, err)
+	_, err = migration.New(db, migrations).Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+// clearSystemConfigs removes all system configs of the shared database, so
+// that every test starts with an empty repository.
+func clearSystemConfigs(t *testing.T, db *gorm.DB) {
+	for _, model := range []any{&SystemConfigReferenceModel{}, &SystemConfigLabelModel{}, &SystemConfigModel{}} {
+		err := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(model).Error
+		require.NoError(t, err)
+	}
+}
+
+func TestSystemConfigSuite(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		repositorytest.RunSystemConfigSuite(t, func(t *testing.T) repository.SystemConfigRepository {
+			dsn := filepath.Join(t.TempDir(), "app.db") + "?_pragma=busy_timeout(5000)"
+			return NewSystemConfigRepository(openMigratedDB(t, "sqlite", sqlite.Open(dsn)))
+		})
+	})
+
+	// The servers are only available where their DSNs are given, e.g.
+	// TEST_MYSQL_DSN="root:root@tcp(127.0.0.1:3306)/test?parseTime=true"
+	for _, server := range []struct {
+		driver string
+		dsnEnv string
+		open   func(dsn string) gorm.Dialector
+	}{
+		{driver: "mysql", dsnEnv: "TEST_MYSQL_DSN", open: mysql.Open},
+		{driver: "postgres", dsnEnv: "TEST_POSTGRES_DSN", open: postgres.Open},
+	} {
+		server := server
+		t.Run(server.driver, func(t *testing.T) {
+			dsn := os.Getenv(server.dsnEnv)
+			if dsn == "" {
+				t.Skipf("%s isn't set", server.dsnEnv)
+			}
+
+			db := openMigratedDB(t, server.driver, server.open(dsn))
+			repositorytest.RunSystemConfigSuite(t, func(t *testing.T) repository.SystemConfigRepository {
+				clearSystemConfigs(t, db)
+				return NewSystemConfigRepository(db)
+			})
+		})
+	}
+}
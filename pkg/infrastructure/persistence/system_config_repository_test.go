@@ -4,9 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/elliotxx/go-web-template/pkg/domain/entity"
 	"github.com/elliotxx/go-web-template/pkg/domain/repository"
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/util/labelutil"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
@@ -24,14 +26,156 @@ func TestSystemConfigRepository(t *testing.T) {
 			actual                        = entity.SystemConfig{Env: entity.EnvProd}
 		)
 		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectNotFrozen(sqlMock)
 		sqlMock.ExpectExec("INSERT").
 			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
+		expectChangeRecorded(sqlMock)
 		sqlMock.ExpectCommit()
 		err = repo.Create(context.Background(), &actual)
 		require.NoError(t, err)
 		require.Equal(t, expectedID, actual.ID)
 	})
 
+	t.Run("Create conflicting record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: "cache"}
+		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectNotFrozen(sqlMock)
+		sqlMock.ExpectExec("INSERT INTO `system_config`").
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		sqlMock.ExpectRollback()
+		err = repo.Create(context.Background(), &actual)
+		require.ErrorIs(t, err, entity.ErrConfigConflict)
+	})
+
+	t.Run("Create in frozen scope", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: "cache"}
+		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectFrozen(sqlMock)
+		sqlMock.ExpectRollback()
+		err = repo.Create(context.Background(), &actual)
+		require.ErrorIs(t, err, entity.ErrConfigFrozen)
+	})
+
+	t.Run("Create in frozen scope with override", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: "cache"}
+		ctx := repository.WithFreezeOverride(context.Background(), &entity.FreezeOverride{
+			Reason:   "incident hotfix",
+			Operator: "admin",
+		})
+		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectFrozen(sqlMock)
+		sqlMock.ExpectExec("INSERT INTO `system_config`").
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		sqlMock.ExpectExec("INSERT INTO `freeze_override`").
+			WithArgs(sqlmock.AnyArg(), 1, 5, entity.FreezeActionCreate, "incident hotfix", "admin").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectChangeRecorded(sqlMock)
+		sqlMock.ExpectCommit()
+		err = repo.Create(ctx, &actual)
+		require.NoError(t, err)
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Create with undefined env", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Env: "staging"}
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `environment`").
+			WithArgs("staging").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		sqlMock.ExpectRollback()
+		err = repo.Create(context.Background(), &actual)
+		require.ErrorIs(t, err, entity.ErrEnvNotFound)
+	})
+
+	t.Run("Create exceeding tenant quota", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd}
+		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		sqlMock.ExpectQuery("SELECT \\* FROM `tenant` .+ FOR UPDATE").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "max_configs"}).
+				AddRow(1, "MAIN_SITE", "active", 2))
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `system_config`").
+			WithArgs("MAIN_SITE").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		sqlMock.ExpectRollback()
+		err = repo.Create(context.Background(), &actual)
+		require.ErrorIs(t, err, entity.ErrConfigQuotaExceeded)
+	})
+
+	t.Run("Create exceeding tenant config size", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Config: "redis: 127.0.0.1:6379"}
+		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		sqlMock.ExpectQuery("SELECT \\* FROM `tenant` .+ FOR UPDATE").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "max_config_size"}).
+				AddRow(1, "MAIN_SITE", "active", 8))
+		sqlMock.ExpectRollback()
+		err = repo.Create(context.Background(), &actual)
+		require.ErrorIs(t, err, entity.ErrConfigSizeExceeded)
+	})
+
+	t.Run("Create in suspended tenant", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd}
+		sqlMock.ExpectBegin()
+		expectEnvExists(sqlMock)
+		sqlMock.ExpectQuery("SELECT \\* FROM `tenant` .+ FOR UPDATE").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).
+				AddRow(1, "MAIN_SITE", "suspended"))
+		sqlMock.ExpectRollback()
+		err = repo.Create(context.Background(), &actual)
+		require.ErrorIs(t, err, entity.ErrTenantSuspended)
+	})
+
 	t.Run("Delete existed record", func(t *testing.T) {
 		fakeGDB, sqlMock, err := GetMockDB()
 		require.NoError(t, err)
@@ -44,8 +188,13 @@ func TestSystemConfigRepository(t *testing.T) {
 		sqlMock.ExpectQuery("SELECT").
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).
 				AddRow(1))
+		expectTenantWritable(sqlMock)
+		expectNotFrozen(sqlMock)
 		sqlMock.ExpectExec("UPDATE").
 			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
+		sqlMock.ExpectExec("DELETE FROM `system_config_reference`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		expectChangeRecorded(sqlMock)
 		sqlMock.ExpectCommit()
 		err = repo.Delete(context.Background(), expectedID)
 		require.NoError(t, err)
@@ -77,10 +226,82 @@ func TestSystemConfigRepository(t *testing.T) {
 				Env: entity.EnvProd,
 			}
 		)
+		sqlMock.ExpectBegin()
+		expectCurrentRecord(sqlMock)
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectNotFrozen(sqlMock)
 		sqlMock.ExpectExec("UPDATE").
 			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
+		expectChangeRecorded(sqlMock)
+		sqlMock.ExpectExec("DELETE FROM `system_config_reference`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectCommit()
+		err = repo.Update(context.Background(), &actual)
+		require.NoError(t, err)
+	})
+
+	t.Run("Update labels of existed record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{
+			ID:     1,
+			Env:    entity.EnvProd,
+			Labels: map[string]string{"team": "payments"},
+		}
+		sqlMock.ExpectBegin()
+		expectCurrentRecord(sqlMock)
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectNotFrozen(sqlMock)
+		sqlMock.ExpectExec("UPDATE `system_config`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectChangeRecorded(sqlMock)
+		sqlMock.ExpectExec("DELETE FROM `system_config_reference`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectExec("DELETE FROM `system_config_label`").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		sqlMock.ExpectExec("INSERT INTO `system_config_label`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectCommit()
 		err = repo.Update(context.Background(), &actual)
 		require.NoError(t, err)
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Update references of existed record", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfig{
+			ID:     1,
+			Env:    entity.EnvProd,
+			Config: "addr: ${ref:MAIN_SITE/prod/redis#/addr}\nport: ${ref:MAIN_SITE/prod/redis#/port}",
+		}
+		sqlMock.ExpectBegin()
+		expectCurrentRecord(sqlMock)
+		expectEnvExists(sqlMock)
+		expectTenantWritable(sqlMock)
+		expectNotFrozen(sqlMock)
+		sqlMock.ExpectExec("UPDATE `system_config`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectChangeRecorded(sqlMock)
+		sqlMock.ExpectExec("DELETE FROM `system_config_reference`").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		sqlMock.ExpectExec("INSERT INTO `system_config_reference`").
+			WithArgs(1, "MAIN_SITE", "prod", "redis", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectCommit()
+		err = repo.Update(context.Background(), &actual)
+		require.NoError(t, err)
+		require.NoError(t, sqlMock.ExpectationsWereMet())
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
@@ -90,9 +311,13 @@ func TestSystemConfigRepository(t *testing.T) {
 		defer CloseDB(t, fakeGDB)
 		defer sqlMock.ExpectClose()
 
-		actual := entity.SystemConfig{Env: entity.EnvProd}
+		actual := entity.SystemConfig{ID: 1, Env: entity.EnvProd}
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		sqlMock.ExpectRollback()
 		err = repo.Update(context.Background(), &actual)
-		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Get", func(t *testing.T) {
@@ -109,10 +334,33 @@ func TestSystemConfigRepository(t *testing.T) {
 		sqlMock.ExpectQuery("SELECT").
 			WillReturnRows(sqlmock.NewRows([]string{"id", "env"}).
 				AddRow(expectedID, string(expectedEnv)))
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_label`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "system_config_id", "label_key", "label_value"}).
+				AddRow(1, expectedID, "team", "payments"))
 		actual, err := repo.Get(context.Background(), expectedID)
 		require.NoError(t, err)
 		require.Equal(t, expectedID, actual.ID)
 		require.Equal(t, expectedEnv, actual.Env)
+		require.Equal(t, map[string]string{"team": "payments"}, actual.Labels)
+	})
+
+	t.Run("GetByKey", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		var expectedID uint = 1400002
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config` WHERE \\(tenant = \\? AND env = \\? AND type = \\?\\)").
+			WithArgs("MAIN_SITE", "prod", "cache").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant", "env", "type"}).
+				AddRow(expectedID, "MAIN_SITE", "prod", "cache"))
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_label`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "system_config_id", "label_key", "label_value"}))
+		actual, err := repo.GetByKey(context.Background(), "MAIN_SITE", entity.EnvProd, "cache")
+		require.NoError(t, err)
+		require.Equal(t, expectedID, actual.ID)
 	})
 
 	t.Run("Find", func(t *testing.T) {
@@ -126,6 +374,8 @@ func TestSystemConfigRepository(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "env"}).
 				AddRow(1, "prod").
 				AddRow(2, "dev"))
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_label`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "system_config_id", "label_key", "label_value"}))
 		actuals, err := repo.Find(context.Background(), repository.Query{
 			Offset: 1,
 			Limit:  10,
@@ -133,4 +383,94 @@ func TestSystemConfigRepository(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 2, len(actuals))
 	})
+
+	t.Run("Find with label selector", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("WHERE id NOT IN \\(SELECT `system_config_id` FROM `system_config_label` .+\\) "+
+			"AND id IN \\(SELECT `system_config_id` FROM `system_config_label` .+\\)").
+			WithArgs("region", "us", "team", "payments").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "env"}))
+		actuals, err := repo.Find(context.Background(), repository.Query{
+			Limit:         10,
+			LabelSelector: labelutil.MustParse("team=payments,region!=us"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, len(actuals))
+	})
+
+	t.Run("FindDependents", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config` WHERE id IN \\(SELECT `system_config_id` FROM `system_config_reference` WHERE ref_tenant = \\? AND ref_env = \\? AND ref_type = \\?\\)").
+			WithArgs("MAIN_SITE", "prod", "redis").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant", "env", "type"}).
+				AddRow(2, "MAIN_SITE", "prod", "app"))
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_label`").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		actuals, err := repo.FindDependents(context.Background(), "MAIN_SITE", entity.EnvProd, "redis")
+		require.NoError(t, err)
+		require.Len(t, actuals, 1)
+		require.Equal(t, "app", actuals[0].Type)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `system_config` WHERE config LIKE").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		total, err := repo.Count(context.Background(), repository.Query{Keyword: "redis"})
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+	})
+}
+
+// expectEnvExists expects the query which checks the env is defined.
+func expectEnvExists(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `environment`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+}
+
+// expectTenantWritable expects the query which locks an active tenant
+// without quotas.
+func expectTenantWritable(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectQuery("SELECT \\* FROM `tenant` .+ FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "active"))
+}
+
+// expectCurrentRecord expects the query which loads the record to update.
+func expectCurrentRecord(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectQuery("SELECT \\* FROM `system_config`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "env"}).AddRow(1, "prod"))
+}
+
+// expectNotFrozen expects the query which finds no active freeze window.
+func expectNotFrozen(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectQuery("SELECT \\* FROM `freeze_window`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+// expectFrozen expects the query which finds an active freeze window.
+func expectFrozen(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectQuery("SELECT \\* FROM `freeze_window` WHERE \\(start_at <= \\? AND end_at > \\?\\) AND \\(tenant = '' OR tenant = \\?\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "spring festival"))
+}
+
+// expectChangeRecorded expects the insert which records the change of the
+// system config.
+func expectChangeRecorded(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectExec("INSERT INTO `system_config_change`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
 }
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// SystemConfigRevisionModel is a DO used to map the entity to the database.
+type SystemConfigRevisionModel struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	SystemConfigID uint
+	Revision       int
+	// Snapshot is the system config encoded in JSON
+	Snapshot string
+	Reason   string
+	Operator string
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *SystemConfigRevisionModel) TableName() string {
+	return "system_config_revision"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *SystemConfigRevisionModel) ToEntity() (*entity.SystemConfigRevision, error) {
+	if m == nil {
+		return nil, ErrSystemConfigRevisionModelNil
+	}
+
+	snapshot, err := decodeSnapshot(m.Snapshot)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode snapshot")
+	}
+
+	return &entity.SystemConfigRevision{
+		ID:             m.ID,
+		SystemConfigID: m.SystemConfigID,
+		Revision:       m.Revision,
+		SystemConfig:   snapshot,
+		Reason:         m.Reason,
+		Operator:       m.Operator,
+		CreatedAt:      m.CreatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *SystemConfigRevisionModel) FromEntity(e *entity.SystemConfigRevision) error {
+	if m == nil {
+		return ErrSystemConfigRevisionModelNil
+	}
+
+	snapshot, err := encodeSnapshot(e.SystemConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode snapshot")
+	}
+
+	m.ID = e.ID
+	m.SystemConfigID = e.SystemConfigID
+	m.Revision = e.Revision
+	m.Snapshot = snapshot
+	m.Reason = e.Reason
+	m.Operator = e.Operator
+	m.CreatedAt = e.CreatedAt
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+)
+
+// The systemConfigRevisionRepository type implements the repository.SystemConfigRevisionRepository interface.
+// If the systemConfigRevisionRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.SystemConfigRevisionRepository = &systemConfigRevisionRepository{}
+
+// systemConfigRevisionRepository is a repository that stores revisions of system configs in a gorm database.
+type systemConfigRevisionRepository struct {
+	// db is the underlying gorm database where revisions are stored.
+	db *gorm.DB
+}
+
+// NewSystemConfigRevisionRepository creates a new system config revision repository.
+func NewSystemConfigRevisionRepository(db *gorm.DB) repository.SystemConfigRevisionRepository {
+	return &systemConfigRevisionRepository{db: db}
+}
+
+// Create records a new revision of a system config.
+func (r *systemConfigRevisionRepository) Create(ctx context.Context, dataEntity *entity.SystemConfigRevision) error {
+	// Map the data from Entity to DO
+	var dataModel SystemConfigRevisionModel
+	err := dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Assign the next revision number of the system config
+		var latest int
+		err := tx.Model(&SystemConfigRevisionModel{}).
+			Select("COALESCE(MAX(revision), 0)").
+			Where("system_config_id = ?", dataModel.SystemConfigID).
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		dataModel.Revision = latest + 1
+
+		// Create new record in the store
+		err = tx.Create(&dataModel).Error
+		if err != nil {
+			return err
+		}
+
+		// Map fresh record's data into Entity
+		newEntity, err := dataModel.ToEntity()
+		if err != nil {
+			return err
+		}
+		*dataEntity = *newEntity
+
+		return nil
+	})
+}
+
+// Find returns the revisions of a system config, latest first.
+func (r *systemConfigRevisionRepository) Find(ctx context.Context, systemConfigID uint, query repository.Query) ([]*entity.SystemConfigRevision, error) {
+	var dataModels []*SystemConfigRevisionModel
+	if err := dbFrom(ctx, r.db).
+		Where("system_config_id = ?", systemConfigID).
+		Order("revision DESC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&dataModels).Error; err != nil {
+		return nil, err
+	}
+
+	dataEntities := make([]*entity.SystemConfigRevision, 0, len(dataModels))
+	for _, model := range dataModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", model.ID)
+		}
+
+		dataEntities = append(dataEntities, newEntity)
+	}
+	return dataEntities, nil
+}
+
+// Prune removes the oldest revisions of a system config so that at most
+// retain revisions are kept.
+func (r *systemConfigRevisionRepository) Prune(ctx context.Context, systemConfigID uint, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	// Find the oldest revision number to keep
+	var oldest []int
+	err := dbFrom(ctx, r.db).
+		Model(&SystemConfigRevisionModel{}).
+		Where("system_config_id = ?", systemConfigID).
+		Order("revision DESC").
+		Offset(retain-1).
+		Limit(1).
+		Pluck("revision", &oldest).Error
+	if err != nil {
+		return err
+	}
+	if len(oldest) == 0 {
+		return nil
+	}
+
+	return dbFrom(ctx, r.db).
+		Where("system_config_id = ? AND revision < ?", systemConfigID, oldest[0]).
+		Delete(&SystemConfigRevisionModel{}).Error
+}
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemConfigRevisionRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRevisionRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.SystemConfigRevision{
+			SystemConfigID: 1,
+			SystemConfig:   &entity.SystemConfig{ID: 1, Config: "{}"},
+			Reason:         "schedule/3/apply",
+		}
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) FROM `system_config_revision`").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(4))
+		sqlMock.ExpectExec("INSERT INTO `system_config_revision`").
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		sqlMock.ExpectCommit()
+		err = repo.Create(context.Background(), &actual)
+		require.NoError(t, err)
+		require.Equal(t, uint(9), actual.ID)
+		require.Equal(t, 5, actual.Revision)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRevisionRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `system_config_revision` WHERE system_config_id = \\? ORDER BY revision DESC").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "system_config_id", "revision", "snapshot"}).
+				AddRow(9, 1, 5, `{"id":1,"config":"{\"a\":2}"}`).
+				AddRow(8, 1, 4, `{"id":1,"config":"{\"a\":1}"}`))
+		actual, err := repo.Find(context.Background(), 1, repository.Query{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		require.Equal(t, 5, actual[0].Revision)
+		require.Equal(t, `{"a":1}`, actual[1].SystemConfig.Config)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRevisionRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT `revision` FROM `system_config_revision` WHERE system_config_id = \\? ORDER BY revision DESC LIMIT 1 OFFSET 2").
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(3))
+		sqlMock.ExpectExec("DELETE FROM `system_config_revision` WHERE system_config_id = \\? AND revision < \\?").
+			WithArgs(1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		err = repo.Prune(context.Background(), 1, 3)
+		require.NoError(t, err)
+	})
+
+	t.Run("Prune without limit", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewSystemConfigRevisionRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		err = repo.Prune(context.Background(), 1, 0)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+)
+
+// TenantModel is a DO used to map the entity to the database.
+// Tenants are hard deleted so that a name can be reused.
+type TenantModel struct {
+	ID            uint `gorm:"primarykey"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Name          string
+	DisplayName   string
+	Owners        MultiString
+	Status        string
+	MaxConfigs    int
+	MaxConfigSize int
+	MaxRevisions  int
+	Creator       string
+	Modifier      string
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *TenantModel) TableName() string {
+	return "tenant"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *TenantModel) ToEntity() (*entity.Tenant, error) {
+	if m == nil {
+		return nil, ErrTenantModelNil
+	}
+
+	status, err := entity.ParseTenantStatus(m.Status)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse tenant status")
+	}
+
+	return &entity.Tenant{
+		ID:          m.ID,
+		Name:        m.Name,
+		DisplayName: m.DisplayName,
+		Owners:      m.Owners,
+		Status:      status,
+		Quota: entity.TenantQuota{
+			MaxConfigs:    m.MaxConfigs,
+			MaxConfigSize: m.MaxConfigSize,
+			MaxRevisions:  m.MaxRevisions,
+		},
+		Creator:   m.Creator,
+		Modifier:  m.Modifier,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *TenantModel) FromEntity(e *entity.Tenant) error {
+	if m == nil {
+		return ErrTenantModelNil
+	}
+
+	m.ID = e.ID
+	m.Name = e.Name
+	m.DisplayName = e.DisplayName
+	m.Owners = e.Owners
+	m.Status = string(e.Status)
+	m.MaxConfigs = e.Quota.MaxConfigs
+	m.MaxConfigSize = e.Quota.MaxConfigSize
+	m.MaxRevisions = e.Quota.MaxRevisions
+	m.Creator = e.Creator
+	m.Modifier = e.Modifier
+	m.CreatedAt = e.CreatedAt
+	m.UpdatedAt = e.UpdatedAt
+
+	return nil
+}
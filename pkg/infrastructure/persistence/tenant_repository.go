@@ -0,0 +1,212 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The tenantRepository type implements the repository.TenantRepository interface.
+// If the tenantRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.TenantRepository = &tenantRepository{}
+
+// tenantRepository is a repository that stores tenants in a gorm database.
+type tenantRepository struct {
+	// db is the underlying gorm database where tenants are stored.
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository.
+func NewTenantRepository(db *gorm.DB) repository.TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+// Create saves a tenant to the repository.
+func (r *tenantRepository) Create(ctx context.Context, dataEntity *entity.Tenant) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel TenantModel
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Create new record in the store
+	err = dbFrom(ctx, r.db).Create(&dataModel).Error
+	if err != nil {
+		return err
+	}
+
+	// Map fresh record's data into Entity
+	newEntity, err := dataModel.ToEntity()
+	if err != nil {
+		return err
+	}
+	*dataEntity = *newEntity
+
+	return nil
+}
+
+// Delete removes a tenant from the repository. Tenants which still own
+// system configs can't be deleted.
+func (r *tenantRepository) Delete(ctx context.Context, id uint) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var dataModel TenantModel
+		err := tx.First(&dataModel, id).Error
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		err = tx.Model(&SystemConfigModel{}).Where("tenant = ?", dataModel.Name).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if total > 0 {
+			return errors.Wrapf(entity.ErrTenantInUse, "failed to delete tenant %q owning %d system configs", dataModel.Name, total)
+		}
+
+		return tx.Delete(&dataModel).Error
+	})
+}
+
+// Update updates an existing tenant in the repository.
+// The name and the creator of the tenant are immutable.
+func (r *tenantRepository) Update(ctx context.Context, dataEntity *entity.Tenant) error {
+	err := dataEntity.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel TenantModel
+	err = dataModel.FromEntity(dataEntity)
+	if err != nil {
+		return err
+	}
+
+	// Select the columns explicitly so that zero values are updated as well
+	result := dbFrom(ctx, r.db).
+		Model(&dataModel).
+		Select("display_name", "owners", "status", "max_configs", "max_config_size", "max_revisions", "modifier").
+		Updates(&dataModel)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Get retrieves a tenant by its ID.
+func (r *tenantRepository) Get(ctx context.Context, id uint) (*entity.Tenant, error) {
+	var dataModel TenantModel
+	err := dbFrom(ctx, r.db).First(&dataModel, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// GetByName retrieves a tenant by its name.
+func (r *tenantRepository) GetByName(ctx context.Context, name string) (*entity.Tenant, error) {
+	var dataModel TenantModel
+	err := dbFrom(ctx, r.db).Where("name = ?", name).First(&dataModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// Find returns a list of specified tenants in the repository.
+func (r *tenantRepository) Find(ctx context.Context, query repository.Query) ([]*entity.Tenant, error) {
+	var tenantModels []*TenantModel
+	if err := dbFrom(ctx, r.db).
+		Scopes(withTenantQuery(query)).
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&tenantModels).Error; err != nil {
+		return nil, err
+	}
+
+	tenantEntities := make([]*entity.Tenant, 0, len(tenantModels))
+	for _, model := range tenantModels {
+		newEntity, err := model.ToEntity()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert db model (ID: %d) to entity", model.ID)
+		}
+
+		tenantEntities = append(tenantEntities, newEntity)
+	}
+	return tenantEntities, nil
+}
+
+// Count returns the total of tenants matching the query.
+func (r *tenantRepository) Count(ctx context.Context, query repository.Query) (int, error) {
+	var total int64
+	err := dbFrom(ctx, r.db).
+		Model(&TenantModel{}).
+		Scopes(withTenantQuery(query)).
+		Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(total), nil
+}
+
+// withTenantQuery returns a gorm scope which filters tenants by the
+// keyword of the query.
+func withTenantQuery(query repository.Query) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Keyword != "" {
+			keyword := "%" + query.Keyword + "%"
+			db = db.Where("name LIKE ? OR display_name LIKE ?", keyword, keyword)
+		}
+
+		return db
+	}
+}
+
+// getWritableTenant locks and returns the specified tenant. It returns
+// entity.ErrTenantNotFound if the tenant is not defined and
+// entity.ErrTenantSuspended if the tenant is read-only.
+//
+// The tenant row is locked until the end of the transaction, so that the
+// quota checks of concurrent writes to the same tenant are serialized.
+func getWritableTenant(tx *gorm.DB, name string) (*entity.Tenant, error) {
+	var dataModel TenantModel
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("name = ?", name).
+		First(&dataModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrapf(entity.ErrTenantNotFound, "tenant %q", name)
+		}
+		return nil, err
+	}
+
+	tenant, err := dataModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tenant.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewTenantRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		var (
+			expectedID uint = 2
+			actual          = entity.Tenant{
+				Name:   "payments",
+				Owners: []string{"alice", "bob"},
+				Status: entity.TenantStatusActive,
+				Quota:  entity.TenantQuota{MaxConfigs: 100},
+			}
+		)
+		sqlMock.ExpectExec("INSERT INTO `tenant`").
+			WillReturnResult(sqlmock.NewResult(int64(expectedID), 1))
+		err = repo.Create(context.Background(), &actual)
+		require.NoError(t, err)
+		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, []string{"alice", "bob"}, actual.Owners)
+	})
+
+	t.Run("Create without owners", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewTenantRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		actual := entity.Tenant{Name: "payments", Status: entity.TenantStatusActive}
+		err = repo.Create(context.Background(), &actual)
+		require.Error(t, err)
+	})
+
+	t.Run("Delete record in use", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewTenantRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).
+				AddRow(1, "payments", "active"))
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `system_config`").
+			WithArgs("payments").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		sqlMock.ExpectRollback()
+		err = repo.Delete(context.Background(), 1)
+		require.ErrorIs(t, err, entity.ErrTenantInUse)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewTenantRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `tenant`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owners", "status", "max_config_size"}).
+				AddRow(1, "payments", "alice,bob", "suspended", 1024))
+		actual, err := repo.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, entity.TenantStatusSuspended, actual.Status)
+		require.Equal(t, 1024, actual.Quota.MaxConfigSize)
+		require.ErrorIs(t, actual.CheckWritable(), entity.ErrTenantSuspended)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		fakeGDB, sqlMock, err := GetMockDB()
+		require.NoError(t, err)
+		repo := NewTenantRepository(fakeGDB)
+		defer CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT \\* FROM `tenant` WHERE name LIKE \\? OR display_name LIKE \\?").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).
+				AddRow(1, "payments", "active"))
+		actuals, err := repo.Find(context.Background(), repository.Query{
+			Limit:   10,
+			Keyword: "pay",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(actuals))
+	})
+}
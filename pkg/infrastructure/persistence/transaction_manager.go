@@ -0,0 +1,112 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxTransactionRetries is the maximum number of times a transaction
+	// failed on a deadlock or a serialization failure is run again.
+	maxTransactionRetries = 3
+	// retryBackoff is the wait before the first retry, doubled on each retry.
+	retryBackoff = 20 * time.Millisecond
+)
+
+// The transactionManager type implements the repository.TransactionManager interface.
+// If the transactionManager type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.TransactionManager = &transactionManager{}
+
+// txKey is the context key of the active transaction.
+type txKey struct{}
+
+// transactionManager runs units of work in the transactions of a gorm
+// database. The active transaction is kept in the context, and the gorm
+// repositories run their statements in it, so the repositories must share
+// the database of the manager.
+type transactionManager struct {
+	// db is the underlying gorm database where transactions are started.
+	db *gorm.DB
+}
+
+// NewTransactionManager creates a new transaction manager.
+func NewTransactionManager(db *gorm.DB) repository.TransactionManager {
+	return &transactionManager{db: db}
+}
+
+// Transaction runs the function in a transaction, or in a savepoint of the
+// transaction of the context. Only the outermost transaction is retried, as
+// a deadlock aborts the whole transaction.
+func (m *transactionManager) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := txFrom(ctx); ok {
+		return tx.WithContext(ctx).Transaction(func(nested *gorm.DB) error {
+			return fn(withTx(ctx, nested))
+		})
+	}
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(withTx(ctx, tx))
+		})
+		if err == nil || attempt >= maxTransactionRetries || !isRetryable(err) {
+			return err
+		}
+
+		ctxutil.GetLogger(ctx).Warnf("Transaction failed on attempt %d, retrying in %v: %v", attempt+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// withTx returns a context carrying the transaction. The reads in the
+// transaction go to the primary, and bypass the caches so that the
+// uncommitted data isn't cached.
+func withTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(repository.WithReadPrimary(ctx), txKey{}, tx)
+}
+
+// txFrom returns the transaction of the context.
+func txFrom(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// dbFrom returns the transaction of the context, or the database if there
+// is no transaction, bound to the context.
+func dbFrom(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := txFrom(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// isRetryable returns true if the transaction failed on a deadlock or a
+// serialization failure, which succeeds when it's run again.
+func isRetryable(err error) bool {
+	// ER_LOCK_DEADLOCK of mysql, also reported for serialization failures
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213
+	}
+
+	// serialization_failure and deadlock_detected of postgres
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		return state == "40001" || state == "40P01"
+	}
+
+	return false
+}
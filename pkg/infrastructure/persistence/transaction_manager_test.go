@@ -0,0 +1,148 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/glebarez/sqlite"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// sqlStateError is an error carrying a SQLSTATE like the errors of pgx.
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestTransactionManager(t *testing.T) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+	newDB := func(t *testing.T) *gorm.DB {
+		dsn := filepath.Join(t.TempDir(), "app.db") + "?_pragma=busy_timeout(5000)"
+		return openMigratedDB(t, "sqlite", sqlite.Open(dsn))
+	}
+	newConfig := func(typ string) *entity.SystemConfig {
+		return &entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: typ, Config: "{}"}
+	}
+
+	t.Run("Commit the writes of the repositories", func(t *testing.T) {
+		db := newDB(t)
+		configs := NewSystemConfigRepository(db)
+		revisions := NewSystemConfigRevisionRepository(db)
+		config := newConfig("redis")
+
+		err := NewTransactionManager(db).Transaction(ctx, func(ctx context.Context) error {
+			if err := configs.Create(ctx, config); err != nil {
+				return err
+			}
+
+			// The uncommitted system config is read in the transaction
+			current, err := configs.Get(ctx, config.ID)
+			if err != nil {
+				return err
+			}
+			return revisions.Create(ctx, &entity.SystemConfigRevision{SystemConfigID: config.ID, SystemConfig: current})
+		})
+		require.NoError(t, err)
+
+		_, err = configs.Get(ctx, config.ID)
+		require.NoError(t, err)
+		actual, err := revisions.Find(ctx, config.ID, repository.Query{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+	})
+
+	t.Run("Roll back the writes of the repositories", func(t *testing.T) {
+		db := newDB(t)
+		configs := NewSystemConfigRepository(db)
+		revisions := NewSystemConfigRevisionRepository(db)
+		config := newConfig("redis")
+
+		err := NewTransactionManager(db).Transaction(ctx, func(ctx context.Context) error {
+			if err := configs.Create(ctx, config); err != nil {
+				return err
+			}
+			if err := revisions.Create(ctx, &entity.SystemConfigRevision{SystemConfigID: config.ID, SystemConfig: config}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		_, err = configs.Get(ctx, config.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		actual, err := revisions.Find(ctx, config.ID, repository.Query{Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, actual)
+	})
+
+	t.Run("Roll back to the savepoint of a nested transaction", func(t *testing.T) {
+		db := newDB(t)
+		configs := NewSystemConfigRepository(db)
+		tm := NewTransactionManager(db)
+		outer, inner := newConfig("redis"), newConfig("kafka")
+
+		err := tm.Transaction(ctx, func(ctx context.Context) error {
+			if err := configs.Create(ctx, outer); err != nil {
+				return err
+			}
+			err := tm.Transaction(ctx, func(ctx context.Context) error {
+				if err := configs.Create(ctx, inner); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			require.ErrorIs(t, err, errAbort)
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = configs.GetByKey(ctx, "MAIN_SITE", entity.EnvProd, "redis")
+		require.NoError(t, err)
+		_, err = configs.GetByKey(ctx, "MAIN_SITE", entity.EnvProd, "kafka")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Retry on deadlocks and serialization failures", func(t *testing.T) {
+		tm := NewTransactionManager(newDB(t))
+		for _, tc := range []struct {
+			name     string
+			err      error
+			expected int
+		}{
+			{name: "postgres serialization failure", err: sqlStateError("40001"), expected: maxTransactionRetries + 1},
+			{name: "postgres deadlock", err: sqlStateError("40P01"), expected: maxTransactionRetries + 1},
+			{name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213}, expected: maxTransactionRetries + 1},
+			{name: "unique violation", err: sqlStateError("23505"), expected: 1},
+			{name: "other error", err: errAbort, expected: 1},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				attempts := 0
+				err := tm.Transaction(ctx, func(ctx context.Context) error {
+					attempts++
+					return tc.err
+				})
+				require.ErrorIs(t, err, tc.err)
+				require.Equal(t, tc.expected, attempts)
+			})
+		}
+
+		// The transaction succeeds once the conflict is gone
+		attempts := 0
+		err := tm.Transaction(ctx, func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return sqlStateError("40001")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+	})
+}
@@ -2,4 +2,14 @@ package persistence
 
 import "github.com/elliotxx/errors"
 
-var ErrSystemConfigModelNil = errors.New("system config model can't be nil")
+var (
+	ErrSystemConfigModelNil         = errors.New("system config model can't be nil")
+	ErrEnvironmentModelNil          = errors.New("environment model can't be nil")
+	ErrTenantModelNil               = errors.New("tenant model can't be nil")
+	ErrScheduledChangeModelNil      = errors.New("scheduled change model can't be nil")
+	ErrSystemConfigRevisionModelNil = errors.New("system config revision model can't be nil")
+	ErrFreezeWindowModelNil         = errors.New("freeze window model can't be nil")
+	ErrFreezeOverrideModelNil       = errors.New("freeze override model can't be nil")
+	ErrSystemConfigChangeModelNil   = errors.New("system config change model can't be nil")
+	ErrIdempotencyRecordModelNil    = errors.New("idempotency record model can't be nil")
+)
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// IdempotencyKeyHeader identifies a mutating request and its retries,
+	// e.g. a UUID generated by the client for every new request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks the responses replayed from the record
+	// of the request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// MaxIdempotencyKeyLength is the maximum length of an idempotency key.
+	MaxIdempotencyKeyLength = 255
+)
+
+const (
+	// recordTimeout bounds the writes of the records, which are made even if
+	// the client has gone away.
+	recordTimeout = 5 * time.Second
+	// progressLease is how long a request in progress holds its key. The
+	// lease is extended while the handler runs, so that only the record of
+	// a request whose server crashed expires after the lease, and is taken
+	// over by a retry.
+	progressLease = time.Minute
+)
+
+// Idempotency returns a gin middleware which answers the retries of a POST,
+// PUT, PATCH or DELETE request sent with the IdempotencyKeyHeader with the
+// recorded response of the request, instead of handling them again. The
+// same key of a principal is rejected with errcode.RepeatedRequest for
+// another method, URL or body, or while the request is in progress. The
+// records expire after the ttl. A request in progress holds its key by a
+// lease of a minute, which is extended until the handler returns, so its
+// record only expires if the server crashed. The requests failing with a server error,
+// throttled or panicking aren't recorded, so that their retries are handled
+// again.
+func Idempotency(records repository.IdempotencyRecordRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isWrite(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > MaxIdempotencyKeyLength {
+			handler.RenderError(c, errcode.InvalidParams.Causef("the %s header is longer than %d characters", IdempotencyKeyHeader, MaxIdempotencyKeyLength))
+			return
+		}
+
+		// The body is read for the fingerprint, then restored for the handler
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			if body, err = io.ReadAll(c.Request.Body); err != nil {
+				handler.RenderError(c, errcode.InvalidParams.Causewf(err, "failed to read the request body"))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		ctx := c.Request.Context()
+		lease := progressLease
+		if ttl < lease {
+			lease = ttl
+		}
+		record := &entity.IdempotencyRecord{
+			Key:         key,
+			Principal:   ctxutil.GetPrincipal(ctx),
+			Fingerprint: fingerprint(c.Request, body),
+			ExpiresAt:   time.Now().Add(lease),
+		}
+		recorded, err := begin(ctx, records, record)
+		if err != nil {
+			handler.RenderError(c, err)
+			return
+		}
+		if recorded != nil {
+			replay(c, record, recorded)
+			return
+		}
+
+		// The records are written even if the client has gone away, so that
+		// its retries don't find the request in progress
+		log := ctxutil.GetLogger(ctx)
+		release := func() {
+			recordCtx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+			defer cancel()
+			if err := records.Delete(recordCtx, record.Principal, record.Key); err != nil {
+				log.Errorf("Failed to delete the idempotency record of %s: %v", record.Key, err)
+			}
+		}
+
+		// Handle the request and record its response. The record is deleted
+		// if the handler panics, the panic goes on to the recovery middleware
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		handled := false
+		stopExtending := extendLease(records, record, lease, log)
+		defer func() {
+			if !handled {
+				stopExtending()
+				release()
+			}
+		}()
+		c.Next()
+		handled = true
+		stopExtending()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			release()
+			return
+		}
+		record.StatusCode = status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.Body = recorder.body.Bytes()
+		record.ExpiresAt = time.Now().Add(ttl)
+		recordCtx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+		defer cancel()
+		if err := records.Complete(recordCtx, record); err != nil {
+			log.Errorf("Failed to record the response of idempotency key %s: %v", record.Key, err)
+		}
+	}
+}
+
+// PurgeIdempotencyRecords deletes the expired idempotency records every
+// interval until the context is done.
+func PurgeIdempotencyRecords(ctx context.Context, records repository.IdempotencyRecordRepository, interval time.Duration) {
+	log := logrus.WithField("component", "idempotency")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		deleted, err := records.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			log.Errorf("Failed to delete the expired idempotency records: %v", err)
+			continue
+		}
+		if deleted > 0 {
+			log.Infof("Deleted %d expired idempotency records", deleted)
+		}
+	}
+}
+
+// begin records the request in progress. It returns the record of the same
+// request if its key is recorded, the expired records are replaced, including
+// the ones of requests in progress whose lease expired. Only one of the
+// retries racing for an expired record replaces it, the others find the
+// request in progress.
+func begin(
+	ctx context.Context,
+	records repository.IdempotencyRecordRepository,
+	record *entity.IdempotencyRecord,
+) (*entity.IdempotencyRecord, error) {
+	for {
+		err := records.Create(ctx, record)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, entity.ErrIdempotencyKeyInUse) {
+			return nil, errors.Wrap(err, "failed to record the idempotent request")
+		}
+
+		recorded, err := records.Get(ctx, record.Principal, record.Key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The record has just been deleted, e.g. on a server error
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the idempotency record")
+		}
+		now := time.Now()
+		if !recorded.Expired(now) {
+			return recorded, nil
+		}
+		err = records.Replace(ctx, record, now)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, entity.ErrIdempotencyKeyInUse) {
+			return nil, errors.Wrap(err, "failed to replace the expired idempotency record")
+		}
+	}
+}
+
+// extendLease extends the lease of the request in progress every third of
+// the lease, until the returned function is called.
+func extendLease(
+	records repository.IdempotencyRecordRepository,
+	record *entity.IdempotencyRecord,
+	lease time.Duration,
+	log logrus.FieldLogger,
+) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+			err := records.Extend(ctx, record.Principal, record.Key, time.Now().Add(lease))
+			cancel()
+			if err != nil {
+				log.Errorf("Failed to extend the lease of idempotency key %s: %v", record.Key, err)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// replay answers the request with the recorded response of the same request.
+func replay(c *gin.Context, record, recorded *entity.IdempotencyRecord) {
+	switch {
+	case recorded.Fingerprint != record.Fingerprint:
+		handler.RenderError(c, errcode.RepeatedRequest.Causef("the idempotency key %s is used by another request", record.Key))
+	case !recorded.Completed():
+		handler.RenderError(c, errcode.RepeatedRequest.Causef("the request of the idempotency key %s is in progress", record.Key))
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(recorded.StatusCode, recorded.ContentType, recorded.Body)
+		c.Abort()
+	}
+}
+
+// fingerprint returns the SHA-256 of the method, the URL and the body of
+// the request.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by the handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the response and the copy.
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the response and the copy.
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,350 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeIdempotencyRecords keeps the idempotency records in memory.
+type fakeIdempotencyRecords struct {
+	mu      sync.Mutex
+	records map[string]entity.IdempotencyRecord
+}
+
+var _ repository.IdempotencyRecordRepository = &fakeIdempotencyRecords{}
+
+func (r *fakeIdempotencyRecords) Create(ctx context.Context, record *entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.records[record.Principal+"/"+record.Key]; ok {
+		return entity.ErrIdempotencyKeyInUse
+	}
+	record.CreatedAt = time.Now()
+	r.records[record.Principal+"/"+record.Key] = *record
+	return nil
+}
+
+func (r *fakeIdempotencyRecords) Get(ctx context.Context, principal, key string) (*entity.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[principal+"/"+key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func (r *fakeIdempotencyRecords) Replace(ctx context.Context, record *entity.IdempotencyRecord, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recorded, ok := r.records[record.Principal+"/"+record.Key]
+	if !ok || !recorded.Expired(now) {
+		return entity.ErrIdempotencyKeyInUse
+	}
+	record.CreatedAt = now
+	r.records[record.Principal+"/"+record.Key] = *record
+	return nil
+}
+
+func (r *fakeIdempotencyRecords) Extend(ctx context.Context, principal, key string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recorded, ok := r.records[principal+"/"+key]
+	if !ok || recorded.Completed() {
+		return gorm.ErrRecordNotFound
+	}
+	recorded.ExpiresAt = expiresAt
+	r.records[principal+"/"+key] = recorded
+	return nil
+}
+
+func (r *fakeIdempotencyRecords) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.records[record.Principal+"/"+record.Key]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.records[record.Principal+"/"+record.Key] = *record
+	return nil
+}
+
+func (r *fakeIdempotencyRecords) Delete(ctx context.Context, principal, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, principal+"/"+key)
+	return nil
+}
+
+func (r *fakeIdempotencyRecords) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, record := range r.records {
+		if record.Expired(now) {
+			delete(r.records, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	records := &fakeIdempotencyRecords{records: map[string]entity.IdempotencyRecord{}}
+	created := 0
+	engine := gin.New()
+	engine.Use(RequestContext([]netip.Prefix{netip.MustParsePrefix("192.0.2.1/32")}), gin.Recovery(), Idempotency(records, time.Hour))
+	engine.POST("/configs", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		created++
+		c.JSON(http.StatusCreated, gin.H{"id": created, "body": string(body)})
+	})
+	engine.PUT("/configs/fail", func(c *gin.Context) {
+		created++
+		c.JSON(http.StatusInternalServerError, gin.H{"id": created})
+	})
+	engine.PUT("/configs/panic", func(c *gin.Context) {
+		created++
+		panic("handler is broken")
+	})
+
+	serve := func(method, path, principal, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(PrincipalHeader, principal)
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Replay the response of the same request", func(t *testing.T) {
+		first := serve(http.MethodPost, "/configs", "alice", "key-1", `{"type":"redis"}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+		require.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+		retry := serve(http.MethodPost, "/configs", "alice", "key-1", `{"type":"redis"}`)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+		require.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+		require.Equal(t, first.Body.String(), retry.Body.String())
+		require.Equal(t, `{"body":"{\"type\":\"redis\"}","id":1}`, retry.Body.String())
+	})
+
+	t.Run("Don't replay to a principal claimed by an untrusted caller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/configs", strings.NewReader(`{"type":"redis"}`))
+		req.RemoteAddr = "203.0.113.7:40000"
+		req.Header.Set(PrincipalHeader, "alice")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+		require.NotEqual(t, `{"body":"{\"type\":\"redis\"}","id":1}`, w.Body.String())
+
+		// The request is recorded without a principal
+		record, err := records.Get(context.Background(), "", "key-1")
+		require.NoError(t, err)
+		require.Equal(t, w.Body.String(), string(record.Body))
+	})
+
+	t.Run("Reject the key reused by another request", func(t *testing.T) {
+		w := serve(http.MethodPost, "/configs", "alice", "key-1", `{"type":"mysql"}`)
+		require.Equal(t, http.StatusConflict, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "A0504", resp["code"])
+	})
+
+	t.Run("Reject the request in progress", func(t *testing.T) {
+		require.NoError(t, records.Create(context.Background(), &entity.IdempotencyRecord{
+			Key:         "key-2",
+			Principal:   "alice",
+			Fingerprint: fingerprint(httptest.NewRequest(http.MethodPost, "/configs", nil), []byte("{}")),
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}))
+		w := serve(http.MethodPost, "/configs", "alice", "key-2", "{}")
+		require.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Keys of another principal", func(t *testing.T) {
+		before := created
+		w := serve(http.MethodPost, "/configs", "bob", "key-1", `{"type":"mysql"}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, before+1, created)
+	})
+
+	t.Run("Handle the requests without a key", func(t *testing.T) {
+		before := created
+		serve(http.MethodPost, "/configs", "alice", "", "{}")
+		serve(http.MethodPost, "/configs", "alice", "", "{}")
+		require.Equal(t, before+2, created)
+	})
+
+	t.Run("Handle the retries of server errors", func(t *testing.T) {
+		before := created
+		w := serve(http.MethodPut, "/configs/fail", "alice", "key-3", "{}")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		w = serve(http.MethodPut, "/configs/fail", "alice", "key-3", "{}")
+		require.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+		require.Equal(t, before+2, created)
+	})
+
+	t.Run("Handle the retries of panics", func(t *testing.T) {
+		before := created
+		w := serve(http.MethodPut, "/configs/panic", "alice", "key-4", "{}")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		_, err := records.Get(context.Background(), "alice", "key-4")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		w = serve(http.MethodPut, "/configs/panic", "alice", "key-4", "{}")
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Equal(t, before+2, created)
+	})
+
+	t.Run("Keep the completed records until the ttl", func(t *testing.T) {
+		serve(http.MethodPost, "/configs", "alice", "key-5", "{}")
+		record, err := records.Get(context.Background(), "alice", "key-5")
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), record.ExpiresAt, time.Minute)
+	})
+
+	t.Run("Take over the requests in progress after the lease", func(t *testing.T) {
+		// The server handling the request crashed before its lease expired
+		require.NoError(t, records.Create(context.Background(), &entity.IdempotencyRecord{
+			Key:         "key-6",
+			Principal:   "alice",
+			Fingerprint: fingerprint(httptest.NewRequest(http.MethodPost, "/configs", nil), []byte("{}")),
+			ExpiresAt:   time.Now().Add(-time.Second),
+		}))
+
+		before := created
+		w := serve(http.MethodPost, "/configs", "alice", "key-6", "{}")
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, before+1, created)
+	})
+
+	t.Run("Take over the expired record by one retry only", func(t *testing.T) {
+		expired := &entity.IdempotencyRecord{
+			Key:         "key-7",
+			Principal:   "alice",
+			Fingerprint: "fingerprint",
+			ExpiresAt:   time.Now().Add(-time.Second),
+		}
+		require.NoError(t, records.Create(context.Background(), expired))
+
+		// Both retries have found the record expired, the second one finds
+		// the first one in progress
+		retry := func() *entity.IdempotencyRecord {
+			record := *expired
+			record.ExpiresAt = time.Now().Add(time.Minute)
+			return &record
+		}
+		recorded, err := begin(context.Background(), records, retry())
+		require.NoError(t, err)
+		require.Nil(t, recorded)
+		require.ErrorIs(t, records.Replace(context.Background(), retry(), time.Now()), entity.ErrIdempotencyKeyInUse)
+		recorded, err = begin(context.Background(), records, retry())
+		require.NoError(t, err)
+		require.NotNil(t, recorded)
+		require.False(t, recorded.Completed())
+	})
+
+	t.Run("Replace the expired records", func(t *testing.T) {
+		record, err := records.Get(context.Background(), "alice", "key-1")
+		require.NoError(t, err)
+		record.ExpiresAt = time.Now().Add(-time.Second)
+		require.NoError(t, records.Complete(context.Background(), record))
+
+		before := created
+		w := serve(http.MethodPost, "/configs", "alice", "key-1", `{"type":"mysql"}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, before+1, created)
+	})
+
+	t.Run("Reject too long keys", func(t *testing.T) {
+		w := serve(http.MethodPost, "/configs", "alice", strings.Repeat("k", MaxIdempotencyKeyLength+1), "{}")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Purge the expired records", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, records.Create(context.Background(), &entity.IdempotencyRecord{
+				Key:       fmt.Sprintf("expired-%d", i),
+				Principal: "alice",
+				ExpiresAt: time.Now().Add(-time.Second),
+			}))
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			PurgeIdempotencyRecords(ctx, records, 10*time.Millisecond)
+			close(done)
+		}()
+		require.Eventually(t, func() bool {
+			_, err := records.Get(context.Background(), "alice", "expired-0")
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		<-done
+	})
+}
+
+func TestIdempotencyLease(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	records := &fakeIdempotencyRecords{records: map[string]entity.IdempotencyRecord{}}
+	// The lease is bounded by the ttl
+	lease := 50 * time.Millisecond
+	release := make(chan struct{})
+	var handled int32
+	engine := gin.New()
+	engine.Use(Idempotency(records, lease))
+	engine.POST("/configs", func(c *gin.Context) {
+		// Only the first request is slow
+		id := atomic.AddInt32(&handled, 1)
+		if id == 1 {
+			<-release
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": id})
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/configs", strings.NewReader("{}"))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- serve() }()
+
+	// The slow request keeps its key after the lease
+	require.Eventually(t, func() bool {
+		_, err := records.Get(context.Background(), "", "key-1")
+		return err == nil
+	}, time.Second, time.Millisecond)
+	time.Sleep(4 * lease)
+	require.Equal(t, http.StatusConflict, serve().Code)
+
+	close(release)
+	require.Equal(t, http.StatusCreated, (<-first).Code)
+	require.EqualValues(t, 1, atomic.LoadInt32(&handled))
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ReadYourWritesHeader opts a request in to reading from the primary
+	// database. On a write it also pins the caller to the primary for the
+	// read-your-writes window, so that the following reads see the write
+	// despite the replication lag of the read replicas.
+	ReadYourWritesHeader = "X-Read-Your-Writes"
+	// ReadYourWritesCookie holds the unix milliseconds until which the
+	// caller is pinned to the primary database.
+	ReadYourWritesCookie = "read_primary_until"
+)
+
+// ReadYourWrites returns a gin middleware which sends the read-only queries
+// of the request to the primary database if the request asks for it with
+// the ReadYourWritesHeader, or if the caller wrote with that header within
+// the window. A zero window only pins the requests carrying the header.
+func ReadYourWrites(window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now()
+		optIn, _ := strconv.ParseBool(c.GetHeader(ReadYourWritesHeader))
+
+		pinned := optIn
+		if value, err := c.Cookie(ReadYourWritesCookie); err == nil {
+			until, err := strconv.ParseInt(value, 10, 64)
+			pinned = pinned || err == nil && now.Before(time.UnixMilli(until))
+		}
+		if pinned {
+			c.Request = c.Request.WithContext(repository.WithReadPrimary(c.Request.Context()))
+		}
+
+		// The cookie must be set before the handler writes the response
+		if optIn && window > 0 && isWrite(c.Request.Method) {
+			until := now.Add(window)
+			maxAge := int((window + time.Second - 1) / time.Second)
+			c.SetSameSite(http.SameSiteLaxMode)
+			c.SetCookie(ReadYourWritesCookie, strconv.FormatInt(until.UnixMilli(), 10), maxAge, "/", "", false, true)
+		}
+
+		c.Next()
+	}
+}
+
+// isWrite returns true if the HTTP method may change the resources.
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(ReadYourWrites(5 * time.Second))
+	handle := func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.FormatBool(repository.ReadPrimaryFrom(c.Request.Context())))
+	}
+	engine.GET("/config", handle)
+	engine.PUT("/config", handle)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Read from replicas by default", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/config", nil))
+		require.Equal(t, "false", w.Body.String())
+	})
+
+	t.Run("Write without the header isn't pinned", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodPut, "/config", nil))
+		require.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("Read your writes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/config", nil)
+		req.Header.Set(ReadYourWritesHeader, "true")
+		w := serve(req)
+		require.Equal(t, "true", w.Body.String())
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, ReadYourWritesCookie, cookies[0].Name)
+		require.Equal(t, 5, cookies[0].MaxAge)
+
+		req = httptest.NewRequest(http.MethodGet, "/config", nil)
+		req.AddCookie(cookies[0])
+		w = serve(req)
+		require.Equal(t, "true", w.Body.String())
+	})
+
+	t.Run("Expired pin reads from replicas", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/config", nil)
+		req.AddCookie(&http.Cookie{
+			Name:  ReadYourWritesCookie,
+			Value: strconv.FormatInt(time.Now().Add(-time.Second).UnixMilli(), 10),
+		})
+		w := serve(req)
+		require.Equal(t, "false", w.Body.String())
+	})
+}
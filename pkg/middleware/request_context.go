@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/netip"
+
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// PrincipalHeader carries the principal making the request. It is set
+	// by the trusted proxy in front of the server, which authenticates the
+	// caller, and is ignored on the requests coming from anywhere else.
+	PrincipalHeader = "X-Principal"
+	// TenantHeader carries the tenant the request is made for.
+	TenantHeader = "X-Tenant"
+)
+
+// RequestContext returns a gin middleware which keeps the trace ID, the
+// principal and the tenant of the request in the context of the request,
+// where the handlers and the repositories read them by the ctxutil
+// accessors. It must be used after requestid.New, which sets the trace ID.
+//
+// The principal is only taken from the requests sent by the trusted proxies,
+// i.e. whose peer address is in one of the prefixes, as anyone else could
+// claim to be any principal. Without trusted proxies there is no principal.
+func RequestContext(trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := ctxutil.WithTraceID(c.Request.Context(), requestid.Get(c))
+		if principal := c.GetHeader(PrincipalHeader); principal != "" && trusted(c.Request.RemoteAddr, trustedProxies) {
+			ctx = ctxutil.WithPrincipal(ctx, principal)
+		}
+		if tenant := c.GetHeader(TenantHeader); tenant != "" {
+			ctx = ctxutil.WithTenant(ctx, tenant)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// trusted returns true if the peer address of the request is in one of the
+// prefixes of the trusted proxies.
+func trusted(remoteAddr string, trustedProxies []netip.Prefix) bool {
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/util/ctxutil"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	// httptest sends the requests from 192.0.2.1
+	engine.Use(requestid.New(), RequestContext([]netip.Prefix{netip.MustParsePrefix("192.0.2.0/30")}))
+	engine.GET("/whoami", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		c.JSON(http.StatusOK, []string{ctxutil.GetTraceID(ctx), ctxutil.GetPrincipal(ctx), ctxutil.GetTenant(ctx)})
+	})
+	engine.GET("/wait", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.Status(http.StatusRequestTimeout)
+	})
+
+	t.Run("Principal, tenant and trace ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set(PrincipalHeader, "elliotxx")
+		req.Header.Set(TenantHeader, "MAIN_SITE")
+		req.Header.Set("X-Request-ID", "trace-1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.JSONEq(t, `["trace-1", "elliotxx", "MAIN_SITE"]`, w.Body.String())
+	})
+
+	t.Run("Ignore the principal of untrusted callers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.RemoteAddr = "203.0.113.7:40000"
+		req.Header.Set(PrincipalHeader, "elliotxx")
+		req.Header.Set(TenantHeader, "MAIN_SITE")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		var values []string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &values))
+		require.Equal(t, []string{"", "MAIN_SITE"}, values[1:])
+
+		// Without trusted proxies every principal is ignored
+		untrusting := gin.New()
+		untrusting.Use(RequestContext(nil))
+		untrusting.GET("/whoami", func(c *gin.Context) {
+			c.String(http.StatusOK, ctxutil.GetPrincipal(c.Request.Context()))
+		})
+		req = httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set(PrincipalHeader, "elliotxx")
+		w = httptest.NewRecorder()
+		untrusting.ServeHTTP(w, req)
+		require.Empty(t, w.Body.String())
+	})
+
+	t.Run("Without headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/whoami", nil))
+		var values []string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &values))
+		require.NotEmpty(t, values[0])
+		require.Equal(t, []string{"", ""}, values[1:])
+	})
+
+	t.Run("Keep the cancellation of the request", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/wait", nil).WithContext(ctx))
+		require.Equal(t, http.StatusRequestTimeout, w.Code)
+	})
+}
@@ -0,0 +1,170 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/util/refutil"
+	"github.com/elliotxx/go-web-template/third_party/metadecoders"
+	"gorm.io/gorm"
+)
+
+// Resolver renders the content of system configs by replacing the
+// references like "${ref:tenant/env/type#/path}" with the values they point
+// to. Referenced system configs are rendered recursively.
+type Resolver struct {
+	configs repository.SystemConfigRepository
+}
+
+// New creates a new Resolver reading the referenced system configs from the
+// repository.
+func New(configs repository.SystemConfigRepository) *Resolver {
+	return &Resolver{
+		configs: configs,
+	}
+}
+
+// Render returns the content of the system config with all references
+// resolved. It returns entity.ErrReferenceCycle if the system config
+// depends on itself, and entity.ErrReferenceNotResolved if a reference
+// points to a missing system config or path.
+func (r *Resolver) Render(ctx context.Context, config *entity.SystemConfig) (string, error) {
+	return r.render(ctx, config, newRenderState())
+}
+
+// CheckCycle returns entity.ErrReferenceCycle if writing the system config
+// would make the references form a cycle. Unresolved references are
+// ignored, as the referenced system configs may be created later.
+func (r *Resolver) CheckCycle(ctx context.Context, config *entity.SystemConfig) error {
+	return r.visit(ctx, config, []string{configKey(config)})
+}
+
+// renderState keeps the system configs being rendered, to detect cycles,
+// and the documents already rendered, so that a system config referenced
+// several times is decoded once.
+type renderState struct {
+	path []string
+	docs map[string]any
+}
+
+func newRenderState() *renderState {
+	return &renderState{docs: map[string]any{}}
+}
+
+func (r *Resolver) render(ctx context.Context, config *entity.SystemConfig, state *renderState) (string, error) {
+	key := configKey(config)
+	if err := checkPath(state.path, key); err != nil {
+		return "", err
+	}
+	state.path = append(state.path, key)
+	defer func() { state.path = state.path[:len(state.path)-1] }()
+
+	return refutil.Replace(config.Config, func(ref refutil.Reference) (string, error) {
+		doc, err := r.document(ctx, ref, state)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := refutil.Lookup(doc, ref.Path)
+		if err != nil {
+			return "", errors.Wrapf(entity.ErrReferenceNotResolved, "%s: %v", ref, err)
+		}
+
+		return refutil.Format(value)
+	})
+}
+
+// document returns the decoded content of the referenced system config,
+// rendered with its own references resolved.
+func (r *Resolver) document(ctx context.Context, ref refutil.Reference, state *renderState) (any, error) {
+	if doc, ok := state.docs[ref.Key()]; ok {
+		return doc, nil
+	}
+
+	target, err := r.get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.render(ctx, target, state)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decode(content)
+	if err != nil {
+		return nil, errors.Wrapf(entity.ErrReferenceNotResolved, "%s: %v", ref, err)
+	}
+	state.docs[ref.Key()] = doc
+
+	return doc, nil
+}
+
+// visit walks the references of the system config depth first.
+func (r *Resolver) visit(ctx context.Context, config *entity.SystemConfig, path []string) error {
+	for _, ref := range refutil.Parse(config.Config) {
+		if err := checkPath(path, ref.Key()); err != nil {
+			return err
+		}
+
+		target, err := r.get(ctx, ref)
+		if err != nil {
+			if errors.Is(err, entity.ErrReferenceNotResolved) {
+				continue
+			}
+			return err
+		}
+
+		if err = r.visit(ctx, target, append(path, ref.Key())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// get returns the referenced system config.
+func (r *Resolver) get(ctx context.Context, ref refutil.Reference) (*entity.SystemConfig, error) {
+	target, err := r.configs.GetByKey(ctx, ref.Tenant, entity.Env(ref.Env), ref.Type)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrapf(entity.ErrReferenceNotResolved, "%s: system config not found", ref)
+		}
+		return nil, errors.Wrapf(err, "failed to get referenced system config %s", ref.Key())
+	}
+
+	return target, nil
+}
+
+// checkPath returns entity.ErrReferenceCycle if the key is already on the
+// path of the system configs being visited.
+func checkPath(path []string, key string) error {
+	for i, visited := range path {
+		if visited == key {
+			cycle := append(append([]string{}, path[i:]...), key)
+			return errors.Wrapf(entity.ErrReferenceCycle, "%s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	return nil
+}
+
+// configKey returns the "tenant/env/type" key of the system config.
+func configKey(config *entity.SystemConfig) string {
+	return fmt.Sprintf("%s/%s/%s", config.Tenant, config.Env, config.Type)
+}
+
+// decode decodes the content in the format detected from it.
+func decode(content string) (any, error) {
+	format := metadecoders.Default.FormatFromContentString(content)
+	if format == "" {
+		// A plain value can only be referenced as a whole
+		return content, nil
+	}
+
+	return metadecoders.Default.Unmarshal([]byte(content), format)
+}
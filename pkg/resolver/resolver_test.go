@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeConfigs struct {
+	repository.SystemConfigRepository
+	configs []*entity.SystemConfig
+}
+
+func (f *fakeConfigs) GetByKey(_ context.Context, tenant string, env entity.Env, typ string) (*entity.SystemConfig, error) {
+	for _, config := range f.configs {
+		if config.Tenant == tenant && config.Env == env && config.Type == typ {
+			return config, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newConfig(typ, content string) *entity.SystemConfig {
+	return &entity.SystemConfig{Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: typ, Config: content}
+}
+
+func TestResolver(t *testing.T) {
+	redis := newConfig("redis", `{"nodes": ["10.0.0.1:6379", "10.0.0.2:6379"], "db": 3}`)
+	cache := newConfig("cache", "backend: ${ref:MAIN_SITE/prod/redis#/nodes/0}\nttl: 60")
+	r := New(&fakeConfigs{configs: []*entity.SystemConfig{redis, cache}})
+
+	t.Run("Render nested references", func(t *testing.T) {
+		app := newConfig("app", `{"cache": "${ref:MAIN_SITE/prod/cache#/backend}", "db": ${ref:MAIN_SITE/prod/redis#/db}, "nodes": ${ref:MAIN_SITE/prod/redis#/nodes}}`)
+		actual, err := r.Render(context.Background(), app)
+		require.NoError(t, err)
+		require.Equal(t, `{"cache": "10.0.0.1:6379", "db": 3, "nodes": ["10.0.0.1:6379","10.0.0.2:6379"]}`, actual)
+	})
+
+	t.Run("Render missing path", func(t *testing.T) {
+		app := newConfig("app", "${ref:MAIN_SITE/prod/redis#/password}")
+		_, err := r.Render(context.Background(), app)
+		require.ErrorIs(t, err, entity.ErrReferenceNotResolved)
+	})
+
+	t.Run("Render missing config", func(t *testing.T) {
+		app := newConfig("app", "${ref:MAIN_SITE/gray/redis#/db}")
+		_, err := r.Render(context.Background(), app)
+		require.ErrorIs(t, err, entity.ErrReferenceNotResolved)
+	})
+
+	t.Run("Detect cycle", func(t *testing.T) {
+		a := newConfig("a", "b: ${ref:MAIN_SITE/prod/b#/a}")
+		b := newConfig("b", "a: ${ref:MAIN_SITE/prod/a#/b}")
+		r := New(&fakeConfigs{configs: []*entity.SystemConfig{a, b}})
+
+		_, err := r.Render(context.Background(), a)
+		require.ErrorIs(t, err, entity.ErrReferenceCycle)
+
+		err = r.CheckCycle(context.Background(), a)
+		require.ErrorIs(t, err, entity.ErrReferenceCycle)
+	})
+
+	t.Run("Check cycle ignores missing configs", func(t *testing.T) {
+		app := newConfig("app", "${ref:MAIN_SITE/prod/cache#/backend} ${ref:MAIN_SITE/gray/redis#/db}")
+		err := r.CheckCycle(context.Background(), app)
+		require.NoError(t, err)
+	})
+}
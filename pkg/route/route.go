@@ -3,8 +3,15 @@ package route
 import (
 	"github.com/elliotxx/expvar"
 	docs "github.com/elliotxx/go-web-template/api/openapispec"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
 	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/environment"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/errcodes"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/freezewindow"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/schedule"
 	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/systemconfig"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/tenant"
+	"github.com/elliotxx/go-web-template/pkg/handler/crud"
 	"github.com/elliotxx/go-web-template/pkg/handler/debug/statsviz"
 	"github.com/elliotxx/go-web-template/pkg/handler/endpoints"
 	"github.com/elliotxx/go-web-template/pkg/handler/healthz"
@@ -17,13 +24,21 @@ import (
 )
 
 type Route struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	SystemConfigs repository.SystemConfigRepository
 }
 
 // Register registers some api to the route
 func (r *Route) Register(engine *gin.Engine) error {
 	// Create the workspace domain service
-	systemConfigHandler := systemconfig.NewHandler(persistence.NewSystemConfigRepository(r.DB))
+	tenantRepo := persistence.NewTenantRepository(r.DB)
+	scheduledChangeRepo := persistence.NewScheduledChangeRepository(r.DB)
+	systemConfigHandler := systemconfig.NewHandler(r.SystemConfigs, scheduledChangeRepo, tenantRepo)
+	environmentHandler := environment.NewHandler(persistence.NewEnvironmentRepository(r.DB))
+	tenantHandler := tenant.NewHandler(tenantRepo)
+	scheduleHandler := schedule.NewHandler(scheduledChangeRepo)
+	freezeWindowHandler := freezewindow.NewHandler(persistence.NewFreezeWindowRepository(r.DB))
+	errcodesHandler := errcodes.NewHandler()
 
 	// Registers some api to the route
 	docs.SwaggerInfo.BasePath = "/"
@@ -52,8 +67,40 @@ func (r *Route) Register(engine *gin.Engine) error {
 		apiv1.DELETE("/systemconfig/:id", handler.WrapFD(systemConfigHandler.DeleteSystemConfig))
 		apiv1.PUT("/systemconfig", handler.WrapFD(systemConfigHandler.UpdateSystemConfig))
 		apiv1.GET("/systemconfig/:id", handler.WrapFD(systemConfigHandler.GetSystemConfig))
+		apiv1.GET("/systemconfig/:id/render", handler.WrapFD(systemConfigHandler.RenderSystemConfig))
+		apiv1.GET("/systemconfig/:id/dependents", handler.WrapFD(systemConfigHandler.FindDependents))
+		apiv1.GET("/systemconfig/by-key/:tenant/:env/:type", handler.WrapFD(systemConfigHandler.GetSystemConfigByKey))
+		apiv1.PUT("/systemconfig/by-key/:tenant/:env/:type", handler.WrapFD(systemConfigHandler.UpsertSystemConfigByKey))
 		apiv1.GET("/systemconfigs", handler.WrapFD(systemConfigHandler.FindSystemConfigs))
 		apiv1.GET("/systemconfig/count", handler.WrapFD(systemConfigHandler.CountSystemConfigs))
+
+		// Register environment handler
+		crud.Mount(apiv1, "environment", environmentHandler.Routes())
+
+		// Register tenant handler
+		apiv1.POST("/tenant", handler.WrapFD(tenantHandler.CreateTenant))
+		apiv1.DELETE("/tenant/:id", handler.WrapFD(tenantHandler.DeleteTenant))
+		apiv1.PUT("/tenant", handler.WrapFD(tenantHandler.UpdateTenant))
+		apiv1.GET("/tenant/:id", handler.WrapFD(tenantHandler.GetTenant))
+		apiv1.GET("/tenants", handler.WrapFD(tenantHandler.FindTenants))
+		apiv1.GET("/tenant/count", handler.WrapFD(tenantHandler.CountTenants))
+
+		// Register scheduled change handler
+		apiv1.GET("/schedules", handler.WrapFD(scheduleHandler.ListSchedules))
+		apiv1.GET("/schedule/:id", handler.WrapFD(scheduleHandler.GetSchedule))
+		apiv1.DELETE("/schedule/:id", handler.WrapFD(scheduleHandler.CancelSchedule))
+
+		// Register freeze window handler
+		apiv1.POST("/freezewindow", handler.WrapFD(freezeWindowHandler.CreateFreezeWindow))
+		apiv1.DELETE("/freezewindow/:id", handler.WrapFD(freezeWindowHandler.DeleteFreezeWindow))
+		apiv1.PUT("/freezewindow", handler.WrapFD(freezeWindowHandler.UpdateFreezeWindow))
+		apiv1.GET("/freezewindow/:id", handler.WrapFD(freezeWindowHandler.GetFreezeWindow))
+		apiv1.GET("/freezewindow/:id/overrides", handler.WrapFD(freezeWindowHandler.FindFreezeOverrides))
+		apiv1.GET("/freezewindows", handler.WrapFD(freezeWindowHandler.FindFreezeWindows))
+		apiv1.GET("/freezewindow/status", handler.WrapFD(freezeWindowHandler.GetFreezeStatus))
+
+		// Register error code handler
+		apiv1.GET("/errcodes", handler.WrapT(errcodesHandler.ListErrorCodes))
 	}
 
 	engine.GET("/endpoints", endpoints.NewEndpointsGETHandler(engine.Routes()))
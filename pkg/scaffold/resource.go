@@ -0,0 +1,387 @@
+package scaffold
+
+import (
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/elliotxx/errors"
+)
+
+// Field types of the --fields flag
+const (
+	TypeString  = "string"
+	TypeText    = "text"
+	TypeInt     = "int"
+	TypeInt64   = "int64"
+	TypeUint    = "uint"
+	TypeFloat64 = "float64"
+	TypeBool    = "bool"
+	TypeTime    = "time"
+)
+
+// columnTypes are the column definitions of the field types by database
+// driver.
+var columnTypes = map[string]map[string]string{
+	TypeString: {
+		"mysql":    "varchar(256) NOT NULL DEFAULT ''",
+		"postgres": "varchar(256) NOT NULL DEFAULT ''",
+		"sqlite":   "varchar(256) NOT NULL DEFAULT ''",
+	},
+	TypeText: {
+		"mysql":    "text DEFAULT NULL",
+		"postgres": "text DEFAULT NULL",
+		"sqlite":   "text DEFAULT NULL",
+	},
+	TypeInt: {
+		"mysql":    "int(11) NOT NULL DEFAULT 0",
+		"postgres": "integer NOT NULL DEFAULT 0",
+		"sqlite":   "integer NOT NULL DEFAULT 0",
+	},
+	TypeInt64: {
+		"mysql":    "bigint(20) NOT NULL DEFAULT 0",
+		"postgres": "bigint NOT NULL DEFAULT 0",
+		"sqlite":   "integer NOT NULL DEFAULT 0",
+	},
+	TypeUint: {
+		"mysql":    "bigint(20) unsigned NOT NULL DEFAULT 0",
+		"postgres": "bigint NOT NULL DEFAULT 0",
+		"sqlite":   "integer NOT NULL DEFAULT 0",
+	},
+	TypeFloat64: {
+		"mysql":    "double NOT NULL DEFAULT 0",
+		"postgres": "double precision NOT NULL DEFAULT 0",
+		"sqlite":   "real NOT NULL DEFAULT 0",
+	},
+	TypeBool: {
+		"mysql":    "tinyint(1) NOT NULL DEFAULT 0",
+		"postgres": "boolean NOT NULL DEFAULT false",
+		"sqlite":   "boolean NOT NULL DEFAULT 0",
+	},
+	TypeTime: {
+		"mysql":    "timestamp(3) NULL DEFAULT NULL",
+		"postgres": "timestamptz(3) DEFAULT NULL",
+		"sqlite":   "datetime DEFAULT NULL",
+	},
+}
+
+// reservedColumns are the columns every resource has.
+var reservedColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// reservedPackages are the packages imported along with the handler
+// package of a resource, or by its tests.
+var reservedPackages = map[string]bool{
+	"context":     true,
+	"copier":      true,
+	"crud":        true,
+	"entity":      true,
+	"errcode":     true,
+	"errors":      true,
+	"fmt":         true,
+	"gin":         true,
+	"gorm":        true,
+	"handler":     true,
+	"handlertest": true,
+	"http":        true,
+	"json":        true,
+	"logrus":      true,
+	"persistence": true,
+	"repository":  true,
+	"require":     true,
+	"testing":     true,
+	"time":        true,
+}
+
+// nameRegexp matches the names of resources and fields, e.g. PriceRule,
+// price_rule or price-rule.
+var nameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Resource describes the resource to generate.
+type Resource struct {
+	// Name is the Go name of the resource, e.g. PriceRule
+	Name string
+	// Var is the Go name of a variable of the resource, e.g. priceRule
+	Var string
+	// Package is the name of the handler package, e.g. pricerule
+	Package string
+	// Table is the name of the database table, e.g. price_rule
+	Table string
+	// Path is the path of the routes, e.g. pricerule
+	Path string
+	// Title is the name of the resource in comments, e.g. price rule
+	Title string
+	// Fields are the fields of the resource besides ID and timestamps
+	Fields []*Field
+}
+
+// Field describes a field of the resource.
+type Field struct {
+	// Name is the Go name of the field, e.g. MaxCount
+	Name string
+	// JSON is the JSON name of the field, e.g. maxCount
+	JSON string
+	// Column is the column of the field, e.g. max_count
+	Column string
+	// Title is the name of the field in comments, e.g. max count
+	Title string
+	// Type is one of the field types, e.g. int
+	Type string
+	// Required fields must be set on creation
+	Required bool
+}
+
+// NewResource creates a resource of the name with the fields parsed from
+// specs in the name:type[:required] format, e.g. "maxCount:int:required".
+func NewResource(name string, specs []string) (*Resource, error) {
+	words, err := splitName(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid resource name")
+	}
+
+	r := &Resource{
+		Name:    pascal(words),
+		Var:     camel(words),
+		Package: strings.Join(words, ""),
+		Table:   strings.Join(words, "_"),
+		Path:    strings.Join(words, ""),
+		Title:   strings.Join(words, " "),
+	}
+	if token.IsKeyword(r.Package) || reservedPackages[r.Package] {
+		return nil, errors.Errorf("resource name %q is reserved", name)
+	}
+
+	columns := map[string]bool{}
+	for _, spec := range specs {
+		f, err := parseField(spec)
+		if err != nil {
+			return nil, err
+		}
+		if reservedColumns[f.Column] {
+			return nil, errors.Errorf("field %q is reserved", f.JSON)
+		}
+		if columns[f.Column] {
+			return nil, errors.Errorf("field %q is duplicated", f.JSON)
+		}
+		columns[f.Column] = true
+		r.Fields = append(r.Fields, f)
+	}
+	if len(r.Fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+
+	return r, nil
+}
+
+// parseField parses a field spec in the name:type[:required] format.
+func parseField(spec string) (*Field, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "required") {
+		return nil, errors.Errorf("invalid field %q, the format is name:type[:required]", spec)
+	}
+
+	words, err := splitName(parts[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid field %q", spec)
+	}
+	if _, ok := columnTypes[parts[1]]; !ok {
+		return nil, errors.Errorf("invalid field %q, unknown type %q", spec, parts[1])
+	}
+
+	return &Field{
+		Name:     pascal(words),
+		JSON:     camel(words),
+		Column:   strings.Join(words, "_"),
+		Title:    strings.Join(words, " "),
+		Type:     parts[1],
+		Required: len(parts) == 3,
+	}, nil
+}
+
+// splitName splits a name in PascalCase, camelCase, snake_case or
+// kebab-case into lower case words.
+func splitName(name string) ([]string, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, errors.Errorf("%q must start with a letter and contain only letters, digits, '_' and '-'", name)
+	}
+
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(name)
+	for i, c := range runes {
+		if c == '_' || c == '-' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		// A word starts at an upper case letter following a lower case
+		// letter or a digit, or preceding a lower case letter in an
+		// acronym, e.g. HTTPServer is http and server
+		if unicode.IsUpper(c) && len(word) > 0 {
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, unicode.ToLower(c))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words, nil
+}
+
+// pascal joins the words in PascalCase, with the common initialisms in
+// upper case.
+func pascal(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		if initialisms[w] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// camel joins the words in camelCase.
+func camel(words []string) string {
+	return words[0] + pascal(words[1:])
+}
+
+// initialisms are the words kept in upper case in Go names.
+var initialisms = map[string]bool{
+	"api":  true,
+	"http": true,
+	"id":   true,
+	"ip":   true,
+	"json": true,
+	"sql":  true,
+	"url":  true,
+	"uuid": true,
+}
+
+// GoType returns the Go type of the field in entities and models.
+func (f *Field) GoType() string {
+	switch f.Type {
+	case TypeString, TypeText:
+		return "string"
+	case TypeTime:
+		return "*time.Time"
+	default:
+		return f.Type
+	}
+}
+
+// IsString reports whether the field is searched by keyword.
+func (f *Field) IsString() bool {
+	return f.Type == TypeString || f.Type == TypeText
+}
+
+// IsTime reports whether the field is a timestamp.
+func (f *Field) IsTime() bool {
+	return f.Type == TypeTime
+}
+
+// ColumnType returns the column definition of the field for the driver.
+func (f *Field) ColumnType(driver string) string {
+	return columnTypes[f.Type][driver]
+}
+
+// Sample returns a Go literal of a value of the field, the other sample is
+// a different value.
+func (f *Field) Sample(other bool) string {
+	switch f.Type {
+	case TypeString, TypeText:
+		if other {
+			return `"updated ` + f.Title + `"`
+		}
+		return `"test ` + f.Title + `"`
+	case TypeFloat64:
+		if other {
+			return "2.5"
+		}
+		return "1.5"
+	case TypeBool:
+		return "true"
+	case TypeTime:
+		return "&now"
+	default:
+		if other {
+			return "2"
+		}
+		return "1"
+	}
+}
+
+// KeywordColumns returns the columns searched by keyword.
+func (r *Resource) KeywordColumns() []string {
+	var columns []string
+	for _, f := range r.Fields {
+		if f.IsString() {
+			columns = append(columns, f.Column)
+		}
+	}
+	return columns
+}
+
+// HasTime reports whether a field of the resource is a timestamp.
+func (r *Resource) HasTime() bool {
+	for _, f := range r.Fields {
+		if f.IsTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdatedField returns the field changed by the generated update test, the
+// first field whose samples differ.
+func (r *Resource) UpdatedField() *Field {
+	for _, f := range r.Fields {
+		if f.Sample(false) != f.Sample(true) && !f.IsTime() {
+			return f
+		}
+	}
+	return nil
+}
+
+// KeywordField returns the first field searched by keyword, if any.
+func (r *Resource) KeywordField() *Field {
+	for _, f := range r.Fields {
+		if f.IsString() {
+			return f
+		}
+	}
+	return nil
+}
+
+// RequiredStrings returns the required string fields, which are checked by
+// the Validate method of the entity.
+func (r *Resource) RequiredStrings() []*Field {
+	var fields []*Field
+	for _, f := range r.Fields {
+		if f.Required && f.IsString() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Doc returns the name of the field at the start of comments, e.g. Max
+// count.
+func (f *Field) Doc() string {
+	return strings.ToUpper(f.Title[:1]) + f.Title[1:]
+}
@@ -0,0 +1,105 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elliotxx/errors"
+)
+
+// routeFile is the file registering the routes in the module.
+var routeFile = filepath.Join("pkg", "route", "route.go")
+
+// The anchors of the route file where the handler of a resource is
+// registered.
+const (
+	handlerImportPrefix = "/pkg/handler/api/v1/"
+	crudImportPath      = "/pkg/handler/crud"
+	registerComment     = "\t// Registers some api to the route"
+	apiGroupLine        = "\tapiv1 := engine.Group(\"/api/v1\")"
+	blockEnd            = "\t}"
+)
+
+// registerRoutes adds the imports, the handler and the crud.Mount of the
+// routes of the resource to the route file, and reports whether the file is
+// changed. The file is left unchanged if the handler is already registered.
+func registerRoutes(path string, r *Resource, module string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read the route file")
+	}
+	if strings.Contains(string(src), r.Package+".NewHandler(") {
+		return false, nil
+	}
+	lines := strings.Split(string(src), "\n")
+	failed := func(anchor string) error {
+		return errors.Errorf("failed to find %q in %s, register the %s handler by hand", strings.TrimSpace(anchor), routeFile, r.Title)
+	}
+
+	// Add the import among the imports of the handlers, gofmt sorts them
+	importPath := "\t\"" + module + handlerImportPrefix + r.Package + "\""
+	importAt := indexOf(lines, 0, func(line string) bool {
+		return strings.HasPrefix(line, "\t\""+module+handlerImportPrefix)
+	})
+	if importAt < 0 {
+		return false, failed(module + handlerImportPrefix)
+	}
+	lines = insert(lines, importAt, importPath)
+	if crudImport := "\t\"" + module + crudImportPath + "\""; indexOf(lines, 0, func(line string) bool { return line == crudImport }) < 0 {
+		lines = insert(lines, importAt, crudImport)
+	}
+
+	// Create the handler after the existing ones
+	createAt := indexOf(lines, 0, func(line string) bool { return line == registerComment })
+	if createAt < 0 {
+		return false, failed(registerComment)
+	}
+	if createAt > 0 && lines[createAt-1] == "" {
+		createAt--
+	}
+	lines = insert(lines, createAt, fmt.Sprintf("\t%sHandler := %s.NewHandler(persistence.New%sRepository(r.DB))", r.Var, r.Package, r.Name))
+
+	// Register the routes at the end of the api group
+	groupAt := indexOf(lines, 0, func(line string) bool { return line == apiGroupLine })
+	if groupAt < 0 {
+		return false, failed(apiGroupLine)
+	}
+	routesAt := indexOf(lines, groupAt, func(line string) bool { return line == blockEnd })
+	if routesAt < 0 {
+		return false, failed(apiGroupLine)
+	}
+	lines = insert(lines, routesAt,
+		"",
+		fmt.Sprintf("\t\t// Register %s handler", r.Title),
+		fmt.Sprintf("\t\tcrud.Mount(apiv1, %q, %sHandler.Routes())", r.Path, r.Var),
+	)
+
+	formatted, err := format.Source([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to format the route file")
+	}
+	if err = os.WriteFile(path, formatted, 0o644); err != nil {
+		return false, errors.Wrap(err, "failed to write the route file")
+	}
+
+	return true, nil
+}
+
+// indexOf returns the index of the first line from start matching the
+// function, or -1.
+func indexOf(lines []string, start int, match func(line string) bool) int {
+	for i := start; i < len(lines); i++ {
+		if match(lines[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insert inserts the new lines before the line at the index.
+func insert(lines []string, at int, newLines ...string) []string {
+	return append(lines[:at], append(newLines, lines[at:]...)...)
+}
@@ -0,0 +1,204 @@
+// Package scaffold generates the code of new domain resources, following the
+// layout of the existing ones: the entity, the repository interface, the
+// model and the repository backed by persistence.Repository, the handler
+// with typed requests, its swag annotations and responses, the schema
+// migrations of every driver, the route registration, and the tests of the
+// repository and the handler.
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/elliotxx/errors"
+)
+
+// drivers are the database drivers with schema migrations.
+var drivers = []string{"mysql", "postgres", "sqlite"}
+
+//go:embed templates
+var templates embed.FS
+
+// Generator generates the resources into a module created from this
+// template.
+type Generator struct {
+	// Dir is the root directory of the module
+	Dir string
+	// Force overwrites the existing files of the resource
+	Force bool
+}
+
+// file is a file to generate from the template of the name.
+type file struct {
+	path     string
+	template string
+}
+
+// data is the data of the templates.
+type data struct {
+	*Resource
+	// Module is the path of the Go module
+	Module string
+}
+
+// Generate writes the files of the resource and registers its routes, and
+// returns the paths of the written files relative to the directory of the
+// module. No file is written if one exists, unless Force is set.
+func (g *Generator) Generate(r *Resource) ([]string, error) {
+	module, err := g.module()
+	if err != nil {
+		return nil, err
+	}
+	version, err := g.migrationVersion(r)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []file{
+		{filepath.Join("pkg", "domain", "entity", r.Table+".go"), "entity.go.tmpl"},
+		{filepath.Join("pkg", "domain", "repository", r.Table+"_repository.go"), "repository.go.tmpl"},
+		{filepath.Join("pkg", "infrastructure", "persistence", r.Table+"_model.go"), "model.go.tmpl"},
+		{filepath.Join("pkg", "infrastructure", "persistence", r.Table+"_repository.go"), "repository_impl.go.tmpl"},
+		{filepath.Join("pkg", "infrastructure", "persistence", r.Table+"_repository_test.go"), "repository_test.go.tmpl"},
+		{filepath.Join("pkg", "handler", "api", "v1", r.Package, "handler.go"), "handler.go.tmpl"},
+		{filepath.Join("pkg", "handler", "api", "v1", r.Package, "handler_test.go"), "handler_test.go.tmpl"},
+		{filepath.Join("pkg", "handler", "api", "v1", r.Package, "request.go"), "request.go.tmpl"},
+		{filepath.Join("pkg", "handler", "api", "v1", r.Package, "response.go"), "response.go.tmpl"},
+	}
+	for _, driver := range drivers {
+		for _, direction := range []string{"up", "down"} {
+			files = append(files, file{
+				path:     filepath.Join(migrationsDir, driver, fmt.Sprintf("%06d_create_%s.%s.sql", version, r.Table, direction)),
+				template: driver + "." + direction + ".sql.tmpl",
+			})
+		}
+	}
+
+	if !g.Force {
+		for _, f := range files {
+			if _, err := os.Stat(filepath.Join(g.Dir, f.path)); err == nil {
+				return nil, errors.Errorf("%s already exists", f.path)
+			}
+		}
+	}
+
+	// Render all files before writing any of them
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		contents[i], err = render(f, data{Resource: r, Module: module})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var written []string
+	for i, f := range files {
+		path := filepath.Join(g.Dir, f.path)
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return written, errors.Wrapf(err, "failed to create the directory of %s", f.path)
+		}
+		if err = os.WriteFile(path, contents[i], 0o644); err != nil {
+			return written, errors.Wrapf(err, "failed to write %s", f.path)
+		}
+		written = append(written, f.path)
+	}
+
+	registered, err := registerRoutes(filepath.Join(g.Dir, routeFile), r, module)
+	if err != nil {
+		return written, err
+	}
+	if registered {
+		written = append(written, routeFile)
+	}
+
+	return written, nil
+}
+
+// module returns the path of the module in go.mod.
+func (g *Generator) module() (string, error) {
+	f, err := os.Open(filepath.Join(g.Dir, "go.mod"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open go.mod, run in the root directory of the module or set --dir")
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", errors.Wrap(err, "failed to read go.mod")
+	}
+
+	return "", errors.New("no module path in go.mod")
+}
+
+// migrationsDir is the directory of the schema migrations in the module.
+var migrationsDir = filepath.Join("assets", "migrations")
+
+// migrationVersion returns the version of the migration creating the
+// resource: the version of the existing migration if any, or the version
+// following the latest migration of every driver.
+func (g *Generator) migrationVersion(r *Resource) (int, error) {
+	suffix := "_create_" + r.Table + ".up.sql"
+	latest := 0
+	for _, driver := range drivers {
+		entries, err := os.ReadDir(filepath.Join(g.Dir, migrationsDir, driver))
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to read the %s migrations", driver)
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			prefix, _, ok := strings.Cut(name, "_")
+			if !ok {
+				continue
+			}
+			version, err := strconv.Atoi(prefix)
+			if err != nil {
+				continue
+			}
+			if strings.HasSuffix(name, suffix) {
+				return version, nil
+			}
+			if version > latest {
+				latest = version
+			}
+		}
+	}
+
+	return latest + 1, nil
+}
+
+// render executes the template of the file, and formats the Go files.
+func render(f file, d data) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/"+f.template)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the template of %s", f.path)
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, d); err != nil {
+		return nil, errors.Wrapf(err, "failed to render %s", f.path)
+	}
+	if filepath.Ext(f.path) != ".go" {
+		return buf.Bytes(), nil
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to format %s", f.path)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,212 @@
+package scaffold
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Run("Names", func(t *testing.T) {
+		for _, name := range []string{"PriceRule", "priceRule", "price_rule", "price-rule"} {
+			r, err := NewResource(name, []string{"name:string"})
+			require.NoError(t, err)
+			require.Equal(t, "PriceRule", r.Name)
+			require.Equal(t, "priceRule", r.Var)
+			require.Equal(t, "pricerule", r.Package)
+			require.Equal(t, "price_rule", r.Table)
+			require.Equal(t, "price rule", r.Title)
+		}
+
+		r, err := NewResource("HTTPRoute", []string{"name:string"})
+		require.NoError(t, err)
+		require.Equal(t, "HTTPRoute", r.Name)
+		require.Equal(t, "httpRoute", r.Var)
+		require.Equal(t, "http_route", r.Table)
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		r, err := NewResource("PriceRule", []string{"name:string:required", "ownerID:uint", "expires_at:time"})
+		require.NoError(t, err)
+		require.Equal(t, &Field{Name: "Name", JSON: "name", Column: "name", Title: "name", Type: TypeString, Required: true}, r.Fields[0])
+		require.Equal(t, &Field{Name: "OwnerID", JSON: "ownerID", Column: "owner_id", Title: "owner id", Type: TypeUint}, r.Fields[1])
+		require.Equal(t, &Field{Name: "ExpiresAt", JSON: "expiresAt", Column: "expires_at", Title: "expires at", Type: TypeTime}, r.Fields[2])
+		require.Equal(t, []string{"name"}, r.KeywordColumns())
+		require.Equal(t, []*Field{r.Fields[0]}, r.RequiredStrings())
+		require.Equal(t, r.Fields[0], r.UpdatedField())
+		require.True(t, r.HasTime())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, fields := range map[string][]string{
+			"PriceRule":  nil,
+			"1PriceRule": {"name:string"},
+			"handler":    {"name:string"},
+			"type":       {"name:string"},
+		} {
+			_, err := NewResource(name, fields)
+			require.Error(t, err, name)
+		}
+		for _, field := range []string{"name", "name:decimal", "name:string:optional", "id:uint", "created_at:time"} {
+			_, err := NewResource("PriceRule", []string{field})
+			require.Error(t, err, field)
+		}
+		_, err := NewResource("PriceRule", []string{"ownerID:uint", "owner_id:uint"})
+		require.ErrorContains(t, err, "duplicated")
+	})
+}
+
+// newModule creates the module with the route file of testdata and the
+// given migrations.
+func newModule(t *testing.T, module string, migrations ...string) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+module+"\n\ngo 1.19\n"), 0o644))
+
+	route, err := os.ReadFile(filepath.Join("testdata", "route.go"))
+	require.NoError(t, err)
+	route = []byte(strings.ReplaceAll(string(route), "github.com/elliotxx/go-web-template", module))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg", "route"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, routeFile), route, 0o644))
+
+	for _, driver := range drivers {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, migrationsDir, driver), 0o755))
+		for _, m := range migrations {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, migrationsDir, driver, m), nil, 0o644))
+		}
+	}
+
+	return dir
+}
+
+func TestGenerator(t *testing.T) {
+	r, err := NewResource("PriceRule", []string{"name:string:required", "discount:float64", "expiresAt:time"})
+	require.NoError(t, err)
+
+	t.Run("Generate", func(t *testing.T) {
+		dir := newModule(t, "example.com/app", "000001_init_schema.up.sql", "000002_system_config_change.up.sql")
+		g := &Generator{Dir: dir}
+
+		written, err := g.Generate(r)
+		require.NoError(t, err)
+		require.Len(t, written, 16)
+		require.Contains(t, written, filepath.Join("pkg", "handler", "api", "v1", "pricerule", "handler.go"))
+		require.Contains(t, written, filepath.Join(migrationsDir, "postgres", "000003_create_price_rule.up.sql"))
+		require.Equal(t, routeFile, written[len(written)-1])
+
+		handler, err := os.ReadFile(filepath.Join(dir, "pkg", "handler", "api", "v1", "pricerule", "handler.go"))
+		require.NoError(t, err)
+		require.Contains(t, string(handler), `"example.com/app/pkg/handler/crud"`)
+		require.Contains(t, string(handler), "// @Router       /api/v1/pricerule/{id} [put]")
+		require.Contains(t, string(handler), "handler.WrapT(h.FindPriceRules)")
+		require.Contains(t, string(handler), "// @Param        perPage  query")
+		require.FileExists(t, filepath.Join(dir, "pkg", "handler", "api", "v1", "pricerule", "handler_test.go"))
+
+		migration, err := os.ReadFile(filepath.Join(dir, migrationsDir, "sqlite", "000003_create_price_rule.up.sql"))
+		require.NoError(t, err)
+		require.Contains(t, string(migration), "`discount` real NOT NULL DEFAULT 0,")
+
+		route, err := os.ReadFile(filepath.Join(dir, routeFile))
+		require.NoError(t, err)
+		require.Contains(t, string(route), "\t\"example.com/app/pkg/handler/api/v1/pricerule\"\n")
+		require.Contains(t, string(route), "\tpriceRuleHandler := pricerule.NewHandler(persistence.NewPriceRuleRepository(r.DB))\n")
+		require.Contains(t, string(route), "\t\"example.com/app/pkg/handler/crud\"\n")
+		require.Contains(t, string(route), "\t\tcrud.Mount(apiv1, \"pricerule\", priceRuleHandler.Routes())\n")
+
+		// The existing files are kept unless forced
+		_, err = g.Generate(r)
+		require.ErrorContains(t, err, "already exists")
+
+		g.Force = true
+		written, err = g.Generate(r)
+		require.NoError(t, err)
+		require.Len(t, written, 15)
+		forced, err := os.ReadFile(filepath.Join(dir, routeFile))
+		require.NoError(t, err)
+		require.Equal(t, string(route), string(forced))
+	})
+
+	t.Run("Unknown route file", func(t *testing.T) {
+		dir := newModule(t, "example.com/app")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, routeFile), []byte("package route\n"), 0o644))
+
+		written, err := (&Generator{Dir: dir}).Generate(r)
+		require.ErrorContains(t, err, "register the price rule handler by hand")
+		require.Len(t, written, 15)
+		require.FileExists(t, filepath.Join(dir, migrationsDir, "mysql", "000001_create_price_rule.down.sql"))
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping the build of the generated code in short mode")
+		}
+
+		// Generate into a copy of this repository with the route file of
+		// testdata, and build the generated packages
+		root, err := filepath.Abs(filepath.Join("..", ".."))
+		require.NoError(t, err)
+		dir := t.TempDir()
+		require.NoError(t, copyTree(root, dir))
+		route, err := os.ReadFile(filepath.Join("testdata", "route.go"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, routeFile), route, 0o644))
+
+		written, err := (&Generator{Dir: dir}).Generate(r)
+		require.NoError(t, err)
+		packages, seen := []string{}, map[string]bool{}
+		for _, f := range written {
+			if p := "./" + filepath.ToSlash(filepath.Dir(f)); filepath.Ext(f) == ".go" && !seen[p] {
+				packages, seen[p] = append(packages, p), true
+			}
+		}
+
+		for _, command := range []string{"build", "vet"} {
+			cmd := exec.Command("go", append([]string{command}, packages...)...)
+			cmd.Dir = dir
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, "go %s %v failed:\n%s", command, packages, out)
+		}
+
+		// The generated handler tests pass on the generated migrations
+		cmd := exec.Command("go", "test", "./pkg/handler/api/v1/pricerule/")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "go test of the generated handler failed:\n%s", out)
+	})
+
+	t.Run("Not a module", func(t *testing.T) {
+		_, err := (&Generator{Dir: t.TempDir()}).Generate(r)
+		require.ErrorContains(t, err, "go.mod")
+	})
+}
+
+// copyTree copies the files of the directory src except .git to dst.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), b, 0o644)
+	})
+}
@@ -0,0 +1,33 @@
+package route
+
+import (
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/handler/api/v1/tenant"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Route struct {
+	DB *gorm.DB
+}
+
+// Register registers some api to the route
+func (r *Route) Register(engine *gin.Engine) error {
+	// Create the workspace domain service
+	tenantHandler := tenant.NewHandler(persistence.NewTenantRepository(r.DB))
+
+	// Registers some api to the route
+	root := engine.Group("/")
+	{
+		root.GET("/livez", func(c *gin.Context) { c.String(200, "OK") })
+	}
+	apiv1 := engine.Group("/api/v1")
+	{
+		// Register tenant handler
+		apiv1.POST("/tenant", handler.WrapFD(tenantHandler.CreateTenant))
+		apiv1.GET("/tenants", handler.WrapFD(tenantHandler.FindTenants))
+	}
+
+	return nil
+}
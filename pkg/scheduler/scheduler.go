@@ -0,0 +1,339 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/elliotxx/errors"
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultInterval is the default interval between two scans of the due
+	// scheduled changes.
+	DefaultInterval = 10 * time.Second
+	// DefaultLease is the default duration a scheduled change is leased to
+	// a replica while it's being processed.
+	DefaultLease = time.Minute
+	// batchSize is the maximum number of due changes processed in a scan.
+	batchSize = 100
+)
+
+// Scheduler applies and reverts the scheduled changes of system configs
+// when they're due.
+//
+// Every replica of the server runs a scheduler, a scheduler claims a due
+// change with a lease before processing it, so a change is never processed
+// by two replicas at the same time. A change whose lease expired, e.g. its
+// replica crashed, is picked up by any replica in a later scan.
+type Scheduler struct {
+	tm        repository.TransactionManager
+	changes   repository.ScheduledChangeRepository
+	configs   repository.SystemConfigRepository
+	revisions repository.SystemConfigRevisionRepository
+	tenants   repository.TenantRepository
+
+	owner    string
+	interval time.Duration
+	lease    time.Duration
+	now      func() time.Time
+	log      logrus.FieldLogger
+}
+
+// New creates a new Scheduler, a non-positive interval or lease falls back
+// to the default value.
+func New(
+	tm repository.TransactionManager,
+	changes repository.ScheduledChangeRepository,
+	configs repository.SystemConfigRepository,
+	revisions repository.SystemConfigRevisionRepository,
+	tenants repository.TenantRepository,
+	interval, lease time.Duration,
+) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if lease <= 0 {
+		lease = DefaultLease
+	}
+
+	owner := newOwnerID()
+	return &Scheduler{
+		tm:        tm,
+		changes:   changes,
+		configs:   configs,
+		revisions: revisions,
+		tenants:   tenants,
+		owner:     owner,
+		interval:  interval,
+		lease:     lease,
+		now:       time.Now,
+		log:       logrus.WithFields(logrus.Fields{"component": "scheduler", "owner": owner}),
+	}
+}
+
+// Run scans and processes the due changes periodically until the context
+// is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.log.Infof("Scheduler started, scanning every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx); err != nil {
+			s.log.Errorf("Failed to process scheduled changes: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("Scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce processes the changes which are due now. A change claimed by
+// another replica in the meantime is skipped.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	// Read from the primary, a lagging replica would return the changes and
+	// system configs as they were before their last write
+	ctx = repository.WithReadPrimary(ctx)
+
+	now := s.now()
+	dueChanges, err := s.changes.FindDue(ctx, now, batchSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to find due scheduled changes")
+	}
+
+	for _, change := range dueChanges {
+		claimed, err := s.changes.Claim(ctx, change, s.owner, now, now.Add(s.lease))
+		if err != nil {
+			return errors.Wrapf(err, "failed to claim scheduled change %d", change.ID)
+		}
+		if !claimed {
+			s.log.Debugf("Scheduled change %d is claimed by another owner", change.ID)
+			continue
+		}
+
+		s.process(ctx, change)
+	}
+
+	return nil
+}
+
+// process applies or reverts the claimed change and records the result in
+// the change.
+//
+// The system config, its revision and the result are written in a single
+// transaction, which is completed only while the lease is still held. If
+// the lease has been lost, e.g. it expired and another replica claimed the
+// change, the transaction is rolled back and the change is left to the new
+// owner, so it's never applied twice.
+//
+// A change whose system config is in a freeze window is left due and is
+// retried by the later scans, so it goes live once the window is over.
+func (s *Scheduler) process(ctx context.Context, change *entity.ScheduledChange) {
+	// Work on a copy, the results of a rolled back transaction are dropped
+	result := *change
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		result = *change
+		switch change.Status {
+		case entity.ScheduleStatusPending:
+			if err := s.apply(ctx, &result); err != nil {
+				return err
+			}
+		case entity.ScheduleStatusApplied:
+			if err := s.revert(ctx, &result); err != nil {
+				return err
+			}
+		}
+		result.Message = ""
+		return s.changes.Complete(ctx, &result, s.owner)
+	})
+	if errors.Is(err, entity.ErrScheduleLeaseLost) {
+		s.log.Warnf("Lease of scheduled change %d is lost, leaving it to the new owner", change.ID)
+		return
+	}
+	if errors.Is(err, entity.ErrConfigFrozen) {
+		// Leave the change due, it's retried by the scans until the freeze
+		// window is over
+		s.log.Infof("Scheduled change %d is deferred: %v", change.ID, err)
+		result = *change
+		result.Message = "deferred: " + err.Error()
+		if err = s.changes.Complete(ctx, &result, s.owner); err != nil {
+			s.log.Errorf("Failed to complete scheduled change %d: %v", change.ID, err)
+		}
+		return
+	}
+	if err != nil {
+		s.log.Errorf("Failed to process scheduled change %d: %v", change.ID, err)
+		result = *change
+		result.Status = entity.ScheduleStatusFailed
+		result.Message = err.Error()
+		if err = s.changes.Complete(ctx, &result, s.owner); err != nil {
+			s.log.Errorf("Failed to complete scheduled change %d: %v", change.ID, err)
+		}
+		return
+	}
+	s.log.Infof("Scheduled change %d of system config %d is %s", change.ID, change.SystemConfigID, result.Status)
+}
+
+// apply writes the target values to the system config, the current values
+// are kept in the change so that they can be restored when it expires.
+func (s *Scheduler) apply(ctx context.Context, change *entity.ScheduledChange) error {
+	current, err := s.configs.Get(ctx, change.SystemConfigID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get system config")
+	}
+
+	previous := &entity.SystemConfig{}
+	if err = copier.CopyWithOption(previous, current, copier.Option{DeepCopy: true}); err != nil {
+		return errors.Wrap(err, "failed to snapshot system config")
+	}
+	if previous.Labels == nil {
+		// Make sure the labels added by the change are removed on revert
+		previous.Labels = map[string]string{}
+	}
+
+	// Overwrite non-zero target values to the current system config
+	if err = copier.CopyWithOption(current, change.Target, copier.Option{IgnoreEmpty: true}); err != nil {
+		return errors.Wrap(err, "failed to merge target values")
+	}
+	current.ID = change.SystemConfigID
+
+	if err = s.configs.Update(ctx, current); err != nil {
+		return errors.Wrap(err, "failed to update system config")
+	}
+	if err = s.recordRevision(ctx, change, current, "apply"); err != nil {
+		return err
+	}
+
+	now := s.now()
+	change.Previous = previous
+	change.Status = entity.ScheduleStatusApplied
+	change.AppliedAt = &now
+
+	return nil
+}
+
+// revert restores the values written by the change to the ones the system
+// config had before it was applied. The other values are left as they are.
+// It fails instead of overwriting a value which has been edited since the
+// change was applied.
+func (s *Scheduler) revert(ctx context.Context, change *entity.ScheduledChange) error {
+	if change.Previous == nil || change.Target == nil {
+		return errors.New("no snapshot to restore")
+	}
+
+	current, err := s.configs.Get(ctx, change.SystemConfigID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get system config")
+	}
+
+	var edited []string
+	restored := *current
+	restoreField(&edited, "tenant", &restored.Tenant, change.Target.Tenant, change.Previous.Tenant)
+	restoreField(&edited, "env", &restored.Env, change.Target.Env, change.Previous.Env)
+	restoreField(&edited, "type", &restored.Type, change.Target.Type, change.Previous.Type)
+	restoreField(&edited, "config", &restored.Config, change.Target.Config, change.Previous.Config)
+	restoreField(&edited, "description", &restored.Description, change.Target.Description, change.Previous.Description)
+	if len(change.Target.Labels) > 0 {
+		if !sameLabels(current.Labels, change.Target.Labels) {
+			edited = append(edited, "labels")
+		}
+		restored.Labels = change.Previous.Labels
+	}
+	if len(edited) > 0 {
+		return errors.Errorf("system config was edited after the change was applied, not reverting %s", strings.Join(edited, ", "))
+	}
+
+	restored.ID = change.SystemConfigID
+	if err = s.configs.Update(ctx, &restored); err != nil {
+		return errors.Wrap(err, "failed to restore system config")
+	}
+	if err = s.recordRevision(ctx, change, &restored, "revert"); err != nil {
+		return err
+	}
+
+	now := s.now()
+	change.Status = entity.ScheduleStatusReverted
+	change.RevertedAt = &now
+
+	return nil
+}
+
+// restoreField sets a field written by the change back to its previous
+// value. A field the change didn't write is skipped, and one which doesn't
+// hold the written value anymore is added to edited.
+func restoreField[T comparable](edited *[]string, name string, field *T, target, previous T) {
+	var zero T
+	if target == zero {
+		return
+	}
+	if *field != target {
+		*edited = append(*edited, name)
+		return
+	}
+	*field = previous
+}
+
+// sameLabels returns true if the labels are equal.
+func sameLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// recordRevision records a revision of the changed system config and prunes
+// the revisions exceeding the quota of its tenant. It runs in the transaction
+// of the change, so a failure rolls the change back.
+func (s *Scheduler) recordRevision(ctx context.Context, change *entity.ScheduledChange, config *entity.SystemConfig, action string) error {
+	revision := &entity.SystemConfigRevision{
+		SystemConfigID: change.SystemConfigID,
+		SystemConfig:   config,
+		Reason:         fmt.Sprintf("schedule/%d/%s", change.ID, action),
+		Operator:       change.Creator,
+	}
+	if err := s.revisions.Create(ctx, revision); err != nil {
+		return errors.Wrap(err, "failed to record revision")
+	}
+
+	tenant, err := s.tenants.GetByName(ctx, config.Tenant)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get tenant %s", config.Tenant)
+	}
+	if err = s.revisions.Prune(ctx, change.SystemConfigID, tenant.Quota.MaxRevisions); err != nil {
+		return errors.Wrap(err, "failed to prune revisions")
+	}
+
+	return nil
+}
+
+// newOwnerID returns an ID identifying the scheduler among the replicas.
+func newOwnerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/elliotxx/go-web-template/pkg/domain/entity"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChanges struct {
+	repository.ScheduledChangeRepository
+	changes   []*entity.ScheduledChange
+	claimable bool
+	leaseLost bool
+	claimedAt []time.Time
+	completed []entity.ScheduledChange
+}
+
+func (f *fakeChanges) FindDue(ctx context.Context, now time.Time, _ int) ([]*entity.ScheduledChange, error) {
+	if !repository.ReadPrimaryFrom(ctx) {
+		return nil, errors.New("due changes must be read from the primary")
+	}
+	var due []*entity.ScheduledChange
+	for _, change := range f.changes {
+		if change.Due(now) {
+			due = append(due, change)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeChanges) Claim(_ context.Context, _ *entity.ScheduledChange, _ string, now, _ time.Time) (bool, error) {
+	f.claimedAt = append(f.claimedAt, now)
+	return f.claimable, nil
+}
+
+func (f *fakeChanges) Complete(_ context.Context, change *entity.ScheduledChange, _ string) error {
+	if f.leaseLost {
+		return entity.ErrScheduleLeaseLost
+	}
+	for _, stored := range f.changes {
+		if stored.ID == change.ID {
+			*stored = *change
+		}
+	}
+	f.completed = append(f.completed, *change)
+	return nil
+}
+
+type fakeConfigs struct {
+	repository.SystemConfigRepository
+	config *entity.SystemConfig
+	err    error
+}
+
+func (f *fakeConfigs) Get(context.Context, uint) (*entity.SystemConfig, error) {
+	config := *f.config
+	return &config, nil
+}
+
+func (f *fakeConfigs) Update(_ context.Context, config *entity.SystemConfig) error {
+	if f.err != nil {
+		return f.err
+	}
+	updated := *config
+	f.config = &updated
+	return nil
+}
+
+type fakeRevisions struct {
+	repository.SystemConfigRevisionRepository
+	reasons []string
+	retain  int
+	err     error
+}
+
+func (f *fakeRevisions) Create(_ context.Context, revision *entity.SystemConfigRevision) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.reasons = append(f.reasons, revision.Reason)
+	return nil
+}
+
+func (f *fakeRevisions) Prune(_ context.Context, _ uint, retain int) error {
+	f.retain = retain
+	return nil
+}
+
+type fakeTenants struct {
+	repository.TenantRepository
+}
+
+func (f *fakeTenants) GetByName(_ context.Context, name string) (*entity.Tenant, error) {
+	return &entity.Tenant{Name: name, Quota: entity.TenantQuota{MaxRevisions: 20}}, nil
+}
+
+// fakeTransactions restores the system config of the fake repository when
+// a transaction is rolled back.
+type fakeTransactions struct {
+	configs *fakeConfigs
+}
+
+func (f *fakeTransactions) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := f.configs.config
+	err := fn(ctx)
+	if err != nil {
+		f.configs.config = snapshot
+	}
+	return err
+}
+
+func TestScheduler(t *testing.T) {
+	now := time.Date(2023, 6, 1, 2, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+	newScheduler := func(claimable bool) (*Scheduler, *fakeChanges, *fakeConfigs, *fakeRevisions) {
+		changes := &fakeChanges{
+			claimable: claimable,
+			changes: []*entity.ScheduledChange{{
+				ID:             3,
+				SystemConfigID: 1,
+				Target:         &entity.SystemConfig{ID: 1, Config: "v2"},
+				EffectiveAt:    now,
+				ExpiresAt:      &expiresAt,
+				Status:         entity.ScheduleStatusPending,
+			}},
+		}
+		configs := &fakeConfigs{config: &entity.SystemConfig{
+			ID: 1, Tenant: "MAIN_SITE", Env: entity.EnvProd, Type: "cache", Config: "v1",
+		}}
+		revisions := &fakeRevisions{}
+		s := New(&fakeTransactions{configs: configs}, changes, configs, revisions, &fakeTenants{}, 0, 0)
+		s.now = func() time.Time { return now }
+		return s, changes, configs, revisions
+	}
+
+	t.Run("Apply and revert", func(t *testing.T) {
+		s, changes, configs, revisions := newScheduler(true)
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, []time.Time{now}, changes.claimedAt)
+		require.Equal(t, "v2", configs.config.Config)
+		require.Equal(t, "cache", configs.config.Type)
+		require.Len(t, changes.completed, 1)
+		require.Equal(t, entity.ScheduleStatusApplied, changes.completed[0].Status)
+		require.Equal(t, "v1", changes.completed[0].Previous.Config)
+		require.Equal(t, 20, revisions.retain)
+
+		// Nothing to do until the change expires
+		changes.completed = nil
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Empty(t, changes.completed)
+
+		s.now = func() time.Time { return expiresAt }
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configs.config.Config)
+		require.Len(t, changes.completed, 1)
+		require.Equal(t, entity.ScheduleStatusReverted, changes.completed[0].Status)
+		require.Equal(t, []string{"schedule/3/apply", "schedule/3/revert"}, revisions.reasons)
+	})
+
+	t.Run("Skip change claimed by another owner", func(t *testing.T) {
+		s, changes, configs, revisions := newScheduler(false)
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configs.config.Config)
+		require.Empty(t, changes.completed)
+		require.Empty(t, revisions.reasons)
+	})
+
+	t.Run("Roll back the change when its revision fails", func(t *testing.T) {
+		s, changes, configs, revisions := newScheduler(true)
+		revisions.err = errors.New("database is gone")
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configs.config.Config)
+		require.Len(t, changes.completed, 1)
+		require.Equal(t, entity.ScheduleStatusFailed, changes.completed[0].Status)
+		require.Contains(t, changes.completed[0].Message, "database is gone")
+	})
+
+	t.Run("Roll back the change when the lease is lost", func(t *testing.T) {
+		s, changes, configs, _ := newScheduler(true)
+		changes.leaseLost = true
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configs.config.Config)
+		require.Empty(t, changes.completed)
+		require.Equal(t, entity.ScheduleStatusPending, changes.changes[0].Status)
+	})
+
+	t.Run("Keep edits made after the change was applied", func(t *testing.T) {
+		s, changes, configs, _ := newScheduler(true)
+		changes.changes[0].Target.Labels = map[string]string{"team": "payments"}
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, map[string]string{"team": "payments"}, configs.config.Labels)
+
+		// A value the change didn't write is kept on revert
+		configs.config.Description = "edited"
+		s.now = func() time.Time { return expiresAt }
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configs.config.Config)
+		require.Empty(t, configs.config.Labels)
+		require.Equal(t, "edited", configs.config.Description)
+		require.Equal(t, entity.ScheduleStatusReverted, changes.completed[1].Status)
+	})
+
+	t.Run("Refuse to revert a value edited after the change was applied", func(t *testing.T) {
+		s, changes, configs, _ := newScheduler(true)
+
+		require.NoError(t, s.RunOnce(context.Background()))
+
+		configs.config.Config = "v3"
+		s.now = func() time.Time { return expiresAt }
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v3", configs.config.Config)
+		require.Equal(t, entity.ScheduleStatusFailed, changes.completed[1].Status)
+		require.Contains(t, changes.completed[1].Message, "not reverting config")
+	})
+
+	t.Run("Defer the change during a freeze", func(t *testing.T) {
+		s, changes, configs, revisions := newScheduler(true)
+		configs.err = fmt.Errorf("failed to update: %w", entity.ErrConfigFrozen)
+
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v1", configs.config.Config)
+		require.Empty(t, revisions.reasons)
+		require.Len(t, changes.completed, 1)
+		require.Equal(t, entity.ScheduleStatusPending, changes.completed[0].Status)
+		require.Contains(t, changes.completed[0].Message, "deferred")
+
+		// The change is still due and applied after the freeze
+		configs.err = nil
+		require.NoError(t, s.RunOnce(context.Background()))
+		require.Equal(t, "v2", configs.config.Config)
+		require.Equal(t, entity.ScheduleStatusApplied, changes.completed[1].Status)
+		require.Empty(t, changes.completed[1].Message)
+	})
+}
@@ -1,12 +1,26 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/netip"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"syscall"
 	"time"
 
 	recovery "github.com/akkuman/gin-logrus-recovery"
+	"github.com/elliotxx/go-web-template/pkg/domain/repository"
+	"github.com/elliotxx/go-web-template/pkg/errcode"
+	"github.com/elliotxx/go-web-template/pkg/handler"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/cache"
+	"github.com/elliotxx/go-web-template/pkg/infrastructure/persistence"
+	"github.com/elliotxx/go-web-template/pkg/middleware"
 	"github.com/elliotxx/go-web-template/pkg/route"
+	"github.com/elliotxx/go-web-template/pkg/scheduler"
+	"github.com/elliotxx/go-web-template/pkg/util/safeutil"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-contrib/requestid"
@@ -16,9 +30,41 @@ import (
 	"gorm.io/gorm"
 )
 
+// shutdownTimeout is how long the in-flight requests are waited for when the
+// server is shutting down.
+const shutdownTimeout = 30 * time.Second
+
 type Config struct {
 	LoggingDirectory string
 	DB               *gorm.DB
+	// SystemConfigs is the repository of the system configs, defaults to
+	// the one of DB
+	SystemConfigs    repository.SystemConfigRepository
+	EnableScheduler  bool
+	ScheduleInterval time.Duration
+	ScheduleLease    time.Duration
+	// ReadYourWritesWindow is how long a caller is pinned to the primary
+	// database after writing with the X-Read-Your-Writes header
+	ReadYourWritesWindow time.Duration
+	// CacheSize is the maximum number of cached system config lookups, the
+	// cache is disabled if it's 0
+	CacheSize         int
+	CacheTTL          time.Duration
+	CacheNegativeTTL  time.Duration
+	CachePollInterval time.Duration
+	// ErrorFormat is the default format of the error responses
+	ErrorFormat handler.ErrorFormat
+	// DefaultLocale is the locale of the error messages for the requests
+	// accepting none of the supported locales, and MessagesDirectory holds
+	// the <locale>.yaml files overriding the embedded error messages
+	DefaultLocale     string
+	MessagesDirectory string
+	// IdempotencyTTL is how long the responses of the requests with the
+	// Idempotency-Key header are replayed, they aren't if it's 0
+	IdempotencyTTL time.Duration
+	// TrustedProxies are the addresses of the proxies authenticating the
+	// callers, the X-Principal header of the other requests is ignored
+	TrustedProxies []netip.Prefix
 }
 
 func NewConfig() *Config {
@@ -26,38 +72,183 @@ func NewConfig() *Config {
 }
 
 type AppServer struct {
-	ginEngine *gin.Engine
-	route     *route.Route
+	ginEngine    *gin.Engine
+	route        *route.Route
+	scheduler    *scheduler.Scheduler
+	cache        *cache.SystemConfigRepository
+	cacheChanges repository.SystemConfigChangeRepository
+	pollInterval time.Duration
+	// idempotencyRecords are purged every idempotencyPurgeInterval
+	idempotencyRecords       repository.IdempotencyRecordRepository
+	idempotencyPurgeInterval time.Duration
+	// stop cancels the background tasks started by PreRun, which are
+	// tracked by background
+	stop       context.CancelFunc
+	background sync.WaitGroup
 }
 
 // New creates a new AppServer instance from Config
 func (c *Config) New() (*AppServer, error) {
+	// Load the error messages before serving any request
+	if c.MessagesDirectory != "" {
+		if err := errcode.LoadMessages(c.MessagesDirectory); err != nil {
+			return nil, err
+		}
+	}
+	if c.DefaultLocale != "" {
+		if err := errcode.SetDefaultLocale(c.DefaultLocale); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize the gin engine and route
 	engine := NewGinEngine(c)
+	systemConfigs := c.SystemConfigs
+	if systemConfigs == nil {
+		systemConfigs = persistence.NewSystemConfigRepository(c.DB)
+	}
+
+	// Cache the system configs, the changes of other replicas are polled
+	// from the database unless they're kept in this process
+	var (
+		cached  *cache.SystemConfigRepository
+		changes repository.SystemConfigChangeRepository
+	)
+	// The scheduler snapshots and writes the system configs in transactions,
+	// which must not read the cached values
+	uncached := systemConfigs
+	if c.CacheSize > 0 {
+		cached = cache.NewSystemConfigRepository(systemConfigs, c.CacheSize, c.CacheTTL, c.CacheNegativeTTL)
+		cached.Publish("systemConfigCache")
+		if c.SystemConfigs == nil {
+			changes = persistence.NewSystemConfigChangeRepository(c.DB)
+		}
+		systemConfigs = cached
+	}
 	router := &route.Route{
-		DB: c.DB,
+		DB:            c.DB,
+		SystemConfigs: systemConfigs,
 	}
 	err := router.Register(engine)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AppServer{
-		ginEngine: engine,
-		route:     router,
-	}, nil
+	// Initialize the scheduler of system config changes
+	var s *scheduler.Scheduler
+	if c.EnableScheduler {
+		s = scheduler.New(
+			persistence.NewTransactionManager(c.DB),
+			persistence.NewScheduledChangeRepository(c.DB),
+			uncached,
+			persistence.NewSystemConfigRevisionRepository(c.DB),
+			persistence.NewTenantRepository(c.DB),
+			c.ScheduleInterval,
+			c.ScheduleLease,
+		)
+	}
+
+	server := &AppServer{
+		ginEngine:    engine,
+		route:        router,
+		scheduler:    s,
+		cache:        cached,
+		cacheChanges: changes,
+		pollInterval: c.CachePollInterval,
+	}
+	if c.IdempotencyTTL > 0 {
+		server.idempotencyRecords = persistence.NewIdempotencyRecordRepository(c.DB)
+		server.idempotencyPurgeInterval = c.IdempotencyTTL
+		if server.idempotencyPurgeInterval > time.Hour {
+			server.idempotencyPurgeInterval = time.Hour
+		}
+	}
+
+	return server, nil
 }
 
 // PreRun is a function that will be called before the server starts to run
 func (s *AppServer) PreRun() error {
-	_ = logrus.WithFields(logrus.Fields{"func": "PreRun"})
+	logger := logrus.WithFields(logrus.Fields{"func": "PreRun"})
+
+	// The background tasks run until the server is stopped
+	ctx, stop := context.WithCancel(context.Background())
+	s.stop = stop
+
+	// Start the scheduler in background
+	if s.scheduler != nil {
+		s.goBackground(func() {
+			s.scheduler.Run(ctx)
+		}, logger)
+	}
+
+	// Poll the system config changes in background to invalidate the cache
+	if s.cache != nil && s.cacheChanges != nil {
+		s.goBackground(func() {
+			s.cache.Watch(ctx, s.cacheChanges, s.pollInterval)
+		}, logger)
+	}
+
+	// Delete the expired idempotency records in background
+	if s.idempotencyRecords != nil {
+		s.goBackground(func() {
+			middleware.PurgeIdempotencyRecords(ctx, s.idempotencyRecords, s.idempotencyPurgeInterval)
+		}, logger)
+	}
 
 	return nil
 }
 
-// Run is a function that will be called when the server starts to run
+// Run is a function that will be called when the server starts to run. It
+// serves until the process receives SIGINT or SIGTERM, then it stops the
+// background tasks and waits for the in-flight requests.
 func (s *AppServer) Run(addr ...string) error {
-	return s.ginEngine.Run(addr...)
+	address := ":8080"
+	if len(addr) > 0 {
+		address = addr[0]
+	}
+	httpServer := &http.Server{Addr: address, Handler: s.ginEngine}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	served := make(chan error, 1)
+	safeutil.Go(func() {
+		served <- httpServer.ListenAndServe()
+	})
+
+	select {
+	case err := <-served:
+		s.Stop()
+		return err
+	case <-ctx.Done():
+	}
+
+	logrus.Info("Shutting down the server ...")
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	err := httpServer.Shutdown(shutdownCtx)
+	s.Stop()
+
+	return err
+}
+
+// Stop stops the background tasks started by PreRun and waits for them to
+// return.
+func (s *AppServer) Stop() {
+	if s.stop != nil {
+		s.stop()
+	}
+	s.background.Wait()
+}
+
+// goBackground starts a background task tracked by the server.
+func (s *AppServer) goBackground(do func(), logger logrus.FieldLogger) {
+	s.background.Add(1)
+	safeutil.GoL(func() {
+		defer s.background.Done()
+		do()
+	}, logger)
 }
 
 // NewGinEngine creates a new GinEngine instance
@@ -80,6 +271,7 @@ func NewGinEngine(c *Config) *gin.Engine {
 
 	// Use some middlewares
 	r.Use(requestid.New())
+	r.Use(middleware.RequestContext(c.TrustedProxies))
 	r.Use(gzip.Gzip(gzip.DefaultCompression))
 	// NOTE: cors.Default() allows all origins
 	r.Use(cors.Default())
@@ -88,6 +280,11 @@ func NewGinEngine(c *Config) *gin.Engine {
 		Output:    auditRotateWriter,
 	}))
 	r.Use(recovery.Recovery(logrus.StandardLogger()))
+	r.Use(middleware.ReadYourWrites(c.ReadYourWritesWindow))
+	r.Use(handler.UseErrorFormat(c.ErrorFormat))
+	if c.IdempotencyTTL > 0 {
+		r.Use(middleware.Idempotency(persistence.NewIdempotencyRecordRepository(c.DB), c.IdempotencyTTL))
+	}
 
 	return r
 }
@@ -9,10 +9,14 @@ import (
 type ContextKey string
 
 const (
-	ContextKeyLogger ContextKey = "logger"
+	ContextKeyLogger    ContextKey = "logger"
+	ContextKeyPrincipal ContextKey = "principal"
+	ContextKeyTenant    ContextKey = "tenant"
+	ContextKeyTraceID   ContextKey = "traceID"
 )
 
-// GetLogger returns the logger from the given context.
+// GetLogger returns the logger from the given context, or the standard
+// logger if the context has none.
 //
 // Example:
 //
@@ -22,7 +26,7 @@ func GetLogger(ctx context.Context) logrus.FieldLogger {
 		return logger
 	}
 
-	return logrus.New()
+	return logrus.StandardLogger()
 }
 
 // WithLogger returns a context by the TODO context and the given logger.
@@ -30,6 +34,9 @@ func GetLogger(ctx context.Context) logrus.FieldLogger {
 // Example:
 //
 //	ctx = ctxutil.WithLogger(logger)
+//
+// Deprecated: The TODO context drops the cancellation and deadline of the
+// request, use CtxWithLogger with the context of the request instead.
 func WithLogger(logger logrus.FieldLogger) context.Context {
 	return context.WithValue(context.TODO(), ContextKeyLogger, logger)
 }
@@ -42,3 +49,66 @@ func WithLogger(logger logrus.FieldLogger) context.Context {
 func CtxWithLogger(ctx context.Context, logger logrus.FieldLogger) context.Context {
 	return context.WithValue(ctx, ContextKeyLogger, logger)
 }
+
+// GetPrincipal returns the principal making the request from the given
+// context, or an empty string if the context has none.
+//
+// Example:
+//
+//	principal := ctxutil.GetPrincipal(ctx)
+func GetPrincipal(ctx context.Context) string {
+	principal, _ := ctx.Value(ContextKeyPrincipal).(string)
+	return principal
+}
+
+// WithPrincipal returns a context by the parent context and the principal
+// making the request.
+//
+// Example:
+//
+//	ctx = ctxutil.WithPrincipal(ctx, "elliotxx")
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, ContextKeyPrincipal, principal)
+}
+
+// GetTenant returns the tenant of the request from the given context, or an
+// empty string if the context has none.
+//
+// Example:
+//
+//	tenant := ctxutil.GetTenant(ctx)
+func GetTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(ContextKeyTenant).(string)
+	return tenant
+}
+
+// WithTenant returns a context by the parent context and the tenant of the
+// request.
+//
+// Example:
+//
+//	ctx = ctxutil.WithTenant(ctx, "MAIN_SITE")
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, ContextKeyTenant, tenant)
+}
+
+// GetTraceID returns the trace ID of the request from the given context, or
+// an empty string if the context has none.
+//
+// Example:
+//
+//	traceID := ctxutil.GetTraceID(ctx)
+func GetTraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(ContextKeyTraceID).(string)
+	return traceID
+}
+
+// WithTraceID returns a context by the parent context and the trace ID of
+// the request.
+//
+// Example:
+//
+//	ctx = ctxutil.WithTraceID(ctx, traceID)
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, ContextKeyTraceID, traceID)
+}
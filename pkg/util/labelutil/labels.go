@@ -0,0 +1,87 @@
+package labelutil
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxKeyLength is the maximum length of a label key.
+	MaxKeyLength = 63
+	// MaxValueLength is the maximum length of a label value.
+	MaxValueLength = 63
+)
+
+// labelRegexp matches a valid label key or value, which must start and end
+// with an alphanumeric character and may contain '-', '_' and '.' in between.
+var labelRegexp = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+
+// ValidateKey checks if the given string is a valid label key.
+func ValidateKey(key string) error {
+	if len(key) == 0 {
+		return fmt.Errorf("label key must be non-empty")
+	}
+	if len(key) > MaxKeyLength {
+		return fmt.Errorf("label key %q must be no more than %d characters", key, MaxKeyLength)
+	}
+	if !labelRegexp.MatchString(key) {
+		return fmt.Errorf("label key %q must consist of alphanumeric characters, '-', '_' or '.', "+
+			"and must start and end with an alphanumeric character", key)
+	}
+
+	return nil
+}
+
+// ValidateValue checks if the given string is a valid label value.
+// An empty value is allowed.
+func ValidateValue(value string) error {
+	if len(value) == 0 {
+		return nil
+	}
+	if len(value) > MaxValueLength {
+		return fmt.Errorf("label value %q must be no more than %d characters", value, MaxValueLength)
+	}
+	if !labelRegexp.MatchString(value) {
+		return fmt.Errorf("label value %q must consist of alphanumeric characters, '-', '_' or '.', "+
+			"and must start and end with an alphanumeric character", value)
+	}
+
+	return nil
+}
+
+// Validate checks if all keys and values in the given labels are valid.
+func Validate(labels map[string]string) error {
+	for _, key := range SortedKeys(labels) {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if err := ValidateValue(labels[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SortedKeys returns the keys of the given labels in ascending order.
+func SortedKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Format returns the labels in the form of "k1=v1,k2=v2", sorted by key.
+func Format(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range SortedKeys(labels) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+
+	return strings.Join(pairs, ",")
+}
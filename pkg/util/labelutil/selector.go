@@ -0,0 +1,248 @@
+package labelutil
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operator is the set of operators that can be used in a selector requirement.
+type Operator string
+
+// These constants represent the supported selector operators.
+const (
+	// Equals requires the label to exist and equal to the value.
+	Equals Operator = "="
+	// NotEquals requires the label to be absent or not equal to the value.
+	NotEquals Operator = "!="
+	// In requires the label to exist and equal to one of the values.
+	In Operator = "in"
+	// NotIn requires the label to be absent or not equal to any of the values.
+	NotIn Operator = "notin"
+	// Exists requires the label to exist.
+	Exists Operator = "exists"
+	// DoesNotExist requires the label to be absent.
+	DoesNotExist Operator = "!"
+)
+
+var (
+	// equalityRegexp matches requirements like "key=value", "key==value" and "key!=value".
+	equalityRegexp = regexp.MustCompile(`^([^\s=!(),]+)\s*(==|=|!=)\s*([^\s=!(),]*)$`)
+	// setRegexp matches requirements like "key in (a,b)" and "key notin (a,b)".
+	setRegexp = regexp.MustCompile(`^([^\s=!(),]+)\s+(in|notin)\s*\(([^()]*)\)$`)
+)
+
+// Requirement is a single label constraint of a selector.
+type Requirement struct {
+	// Key is the label key the requirement applies to.
+	Key string
+	// Operator is the relationship between the key and the values.
+	Operator Operator
+	// Values is the set of values the operator applies to. It contains
+	// exactly one value for Equals and NotEquals, and is empty for
+	// Exists and DoesNotExist.
+	Values []string
+}
+
+// Matches returns true if the given labels satisfy the requirement.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+
+	switch r.Operator {
+	case Equals, In:
+		return exists && r.hasValue(value)
+	case NotEquals, NotIn:
+		return !exists || !r.hasValue(value)
+	case Exists:
+		return exists
+	case DoesNotExist:
+		return !exists
+	default:
+		return false
+	}
+}
+
+// String returns the requirement in its textual selector form.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Equals, NotEquals:
+		return r.Key + string(r.Operator) + r.Values[0]
+	case In, NotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	case DoesNotExist:
+		return "!" + r.Key
+	default:
+		return r.Key
+	}
+}
+
+func (r Requirement) hasValue(value string) bool {
+	for _, v := range r.Values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Selector is a list of requirements that must all be satisfied.
+// An empty selector matches everything.
+type Selector []Requirement
+
+// Empty returns true if the selector has no requirements.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches returns true if the given labels satisfy all requirements.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the selector in its textual form.
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		parts = append(parts, r.String())
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Parse parses a Kubernetes-style label selector, for example:
+//
+//	team=payments,region!=us,tier in (a,b),!deprecated
+//
+// The supported requirements are "key=value", "key==value", "key!=value",
+// "key in (v1,v2)", "key notin (v1,v2)", "key" and "!key".
+func Parse(selector string) (Selector, error) {
+	parts, err := splitRequirements(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(Selector, 0, len(parts))
+	for _, part := range parts {
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, r)
+	}
+
+	// Keep a stable order so that equal selectors produce equal queries
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].Key < s[j].Key
+	})
+
+	return s, nil
+}
+
+// MustParse is like Parse but panics if the selector is invalid.
+func MustParse(selector string) Selector {
+	s, err := Parse(selector)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// splitRequirements splits the selector by the commas which are not
+// enclosed in parentheses.
+func splitRequirements(selector string) ([]string, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return nil, nil
+	}
+
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("invalid label selector %q: nested parentheses", selector)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid label selector %q: unbalanced parentheses", selector)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(selector[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid label selector %q: unbalanced parentheses", selector)
+	}
+	parts = append(parts, strings.TrimSpace(selector[start:]))
+
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid label selector %q: empty requirement", selector)
+		}
+	}
+
+	return parts, nil
+}
+
+// parseRequirement parses a single requirement of the selector.
+func parseRequirement(part string) (Requirement, error) {
+	var r Requirement
+
+	switch {
+	case strings.HasPrefix(part, "!") && !strings.Contains(part, "="):
+		r.Key = strings.TrimSpace(part[1:])
+		r.Operator = DoesNotExist
+	case setRegexp.MatchString(part):
+		matches := setRegexp.FindStringSubmatch(part)
+		r.Key = matches[1]
+		r.Operator = Operator(matches[2])
+		for _, v := range strings.Split(matches[3], ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				return r, fmt.Errorf("invalid requirement %q: empty value in set", part)
+			}
+			r.Values = append(r.Values, v)
+		}
+		sort.Strings(r.Values)
+	case equalityRegexp.MatchString(part):
+		matches := equalityRegexp.FindStringSubmatch(part)
+		r.Key = matches[1]
+		r.Operator = Equals
+		if matches[2] == string(NotEquals) {
+			r.Operator = NotEquals
+		}
+		r.Values = []string{matches[3]}
+	default:
+		r.Key = part
+		r.Operator = Exists
+	}
+
+	if err := ValidateKey(r.Key); err != nil {
+		return r, fmt.Errorf("invalid requirement %q: %w", part, err)
+	}
+	for _, v := range r.Values {
+		if err := ValidateValue(v); err != nil {
+			return r, fmt.Errorf("invalid requirement %q: %w", part, err)
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,105 @@
+package labelutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "empty-selector",
+			selector: "",
+			want:     "",
+		},
+		{
+			name:     "equality-requirements",
+			selector: "team=payments, region!=us, env==prod",
+			want:     "env=prod,region!=us,team=payments",
+		},
+		{
+			name:     "set-requirements",
+			selector: "tier in (b, a),zone notin (z1)",
+			want:     "tier in (a,b),zone notin (z1)",
+		},
+		{
+			name:     "existence-requirements",
+			selector: "team,!deprecated",
+			want:     "!deprecated,team",
+		},
+		{
+			name:     "failed-unbalanced-parentheses",
+			selector: "tier in (a,b",
+			wantErr:  true,
+		},
+		{
+			name:     "failed-empty-requirement",
+			selector: "team=payments,,region=eu",
+			wantErr:  true,
+		},
+		{
+			name:     "failed-empty-set-value",
+			selector: "tier in (a,)",
+			wantErr:  true,
+		},
+		{
+			name:     "failed-invalid-key",
+			selector: "-team=payments",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.selector)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{
+		"team":   "payments",
+		"region": "eu",
+		"tier":   "a",
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		want     bool
+	}{
+		{name: "empty", selector: "", want: true},
+		{name: "equals", selector: "team=payments", want: true},
+		{name: "equals-mismatch", selector: "team=search", want: false},
+		{name: "not-equals", selector: "region!=us", want: true},
+		{name: "not-equals-absent", selector: "zone!=z1", want: true},
+		{name: "in", selector: "tier in (a,b)", want: true},
+		{name: "in-absent", selector: "zone in (z1)", want: false},
+		{name: "notin", selector: "tier notin (a,b)", want: false},
+		{name: "exists", selector: "team", want: true},
+		{name: "does-not-exist", selector: "!team", want: false},
+		{name: "all", selector: "team=payments,region!=us,tier in (a,b)", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, MustParse(tt.selector).Matches(labels))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(map[string]string{"team": "payments", "app.kind": ""}))
+	require.Error(t, Validate(map[string]string{"": "payments"}))
+	require.Error(t, Validate(map[string]string{"team": "pay ments"}))
+}
@@ -0,0 +1,147 @@
+package refutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// referenceRegexp matches references like "${ref:tenant/env/type#/path}",
+// the path is a JSON pointer and may be omitted to reference the whole
+// document.
+var referenceRegexp = regexp.MustCompile(`\$\{ref:([^/#{}\s]+)/([^/#{}\s]+)/([^/#{}\s]+)(#[^{}\s]*)?\}`)
+
+// Reference points to a value in the content of another system config.
+type Reference struct {
+	// Tenant of the referenced system config.
+	Tenant string
+	// Env of the referenced system config.
+	Env string
+	// Type of the referenced system config.
+	Type string
+	// Path is a JSON pointer (RFC 6901) to the referenced value, empty
+	// means the whole document.
+	Path string
+}
+
+// Key returns the "tenant/env/type" key of the referenced system config.
+func (r Reference) Key() string {
+	return r.Tenant + "/" + r.Env + "/" + r.Type
+}
+
+// String returns the reference in the "${ref:tenant/env/type#/path}" form.
+func (r Reference) String() string {
+	if r.Path == "" {
+		return "${ref:" + r.Key() + "}"
+	}
+	return "${ref:" + r.Key() + "#" + r.Path + "}"
+}
+
+// Parse returns the references in the content, in order of appearance and
+// without duplicates.
+func Parse(content string) []Reference {
+	matches := referenceRegexp.FindAllStringSubmatch(content, -1)
+
+	refs := make([]Reference, 0, len(matches))
+	seen := make(map[Reference]struct{}, len(matches))
+	for _, m := range matches {
+		ref := newReference(m)
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// Replace replaces every reference in the content with the value returned
+// by the resolve function. It stops at the first error.
+func Replace(content string, resolve func(Reference) (string, error)) (string, error) {
+	var firstErr error
+	result := referenceRegexp.ReplaceAllStringFunc(content, func(s string) string {
+		if firstErr != nil {
+			return s
+		}
+
+		value, err := resolve(newReference(referenceRegexp.FindStringSubmatch(s)))
+		if err != nil {
+			firstErr = err
+			return s
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	return result, nil
+}
+
+// Lookup walks the decoded document along the JSON pointer and returns the
+// value it points to.
+func Lookup(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid path %q: must start with '/'", pointer)
+	}
+
+	current := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		// Unescape the token as defined by RFC 6901
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found: no key %q", pointer, token)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("path %q not found: invalid index %q", pointer, token)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %q not found: %q is not a container", pointer, token)
+		}
+	}
+
+	return current, nil
+}
+
+// Format returns the text substituted for a referenced value. Scalars are
+// substituted as is and containers are encoded as JSON.
+func Format(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// newReference creates a reference from the submatches of referenceRegexp.
+func newReference(m []string) Reference {
+	return Reference{
+		Tenant: m[1],
+		Env:    m[2],
+		Type:   m[3],
+		Path:   strings.TrimPrefix(m[4], "#"),
+	}
+}
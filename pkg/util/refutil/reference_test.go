@@ -0,0 +1,75 @@
+package refutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	content := `addr: ${ref:MAIN_SITE/prod/redis#/addr}
+backup: ${ref:MAIN_SITE/prod/redis#/addr}
+all: ${ref:MAIN_SITE/gray/mq}
+broken: ${ref:MAIN_SITE/prod}`
+
+	refs := Parse(content)
+	require.Equal(t, []Reference{
+		{Tenant: "MAIN_SITE", Env: "prod", Type: "redis", Path: "/addr"},
+		{Tenant: "MAIN_SITE", Env: "gray", Type: "mq"},
+	}, refs)
+	require.Equal(t, "${ref:MAIN_SITE/prod/redis#/addr}", refs[0].String())
+	require.Equal(t, "${ref:MAIN_SITE/gray/mq}", refs[1].String())
+}
+
+func TestReplace(t *testing.T) {
+	t.Run("replace-all-references", func(t *testing.T) {
+		actual, err := Replace(`{"addr": "${ref:a/prod/redis#/addr}", "port": ${ref:a/prod/redis#/port}}`,
+			func(ref Reference) (string, error) {
+				return map[string]string{"/addr": "10.0.0.1", "/port": "6379"}[ref.Path], nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, `{"addr": "10.0.0.1", "port": 6379}`, actual)
+	})
+
+	t.Run("failed-to-resolve", func(t *testing.T) {
+		_, err := Replace("${ref:a/prod/redis#/addr}", func(Reference) (string, error) {
+			return "", errors.New("not found")
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestLookup(t *testing.T) {
+	doc := map[string]any{
+		"redis": map[string]any{
+			"nodes": []any{"10.0.0.1", "10.0.0.2"},
+			"a/b":   true,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		pointer string
+		want    any
+		wantErr bool
+	}{
+		{name: "whole-document", pointer: "", want: doc},
+		{name: "array-element", pointer: "/redis/nodes/1", want: "10.0.0.2"},
+		{name: "escaped-key", pointer: "/redis/a~1b", want: true},
+		{name: "failed-missing-key", pointer: "/redis/port", wantErr: true},
+		{name: "failed-index-out-of-range", pointer: "/redis/nodes/2", wantErr: true},
+		{name: "failed-relative-path", pointer: "redis", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lookup(doc, tt.pointer)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}